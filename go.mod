@@ -4,7 +4,11 @@ go 1.23.0
 
 toolchain go1.24.1
 
-require encore.dev v1.48.13
+require (
+	encore.dev v1.48.13
+	github.com/mozillazg/go-unidecode v0.2.0
+	golang.org/x/text v0.28.0
+)
 
 require (
 	github.com/abadojack/whatlanggo v1.0.1 // indirect
@@ -12,9 +16,7 @@ require (
 	github.com/jackc/pgservicefile v0.0.0-20200714003250-2b9c44734f2b // indirect
 	github.com/jackc/pgx/v5 v5.2.0 // indirect
 	github.com/jackc/puddle/v2 v2.1.2 // indirect
-	github.com/mozillazg/go-unidecode v0.2.0 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
 	golang.org/x/crypto v0.0.0-20220829220503-c86fa9a7ed90 // indirect
 	golang.org/x/sync v0.16.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
 )
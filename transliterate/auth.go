@@ -0,0 +1,31 @@
+package transliterate
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+)
+
+// secrets holds the service's Encore secrets
+var secrets struct {
+	AdminToken string // Bearer token for administrative endpoints such as mapping imports
+}
+
+// adminUID identifies callers authenticated with the admin token
+const adminUID auth.UID = "admin"
+
+// AuthHandler authenticates administrators by the bearer token in the Authorization header
+//
+//encore:authhandler
+func AuthHandler(ctx context.Context, token string) (auth.UID, error) {
+	if secrets.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secrets.AdminToken)) != 1 {
+		return "", &errs.Error{
+			Code:    errs.Unauthenticated,
+			Message: "invalid admin token",
+			Details: ErrorDetails{Reason: ReasonInvalidToken},
+		}
+	}
+	return adminUID, nil
+}
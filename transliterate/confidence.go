@@ -0,0 +1,38 @@
+package transliterate
+
+// confidenceBound is the range a script pair's confidence scores are clamped to
+type confidenceBound struct {
+	Floor   float64 // Lowest score reported for any output
+	Ceiling float64 // Highest score achievable, however clean the output
+}
+
+// defaultConfidenceBound applies to script pairs without an entry of their own
+var defaultConfidenceBound = confidenceBound{Floor: 0.0, Ceiling: 1.0}
+
+// confidenceBounds caps the confidence of conversions that stay uncertain however well they
+// go, so clients don't over-trust them: Chinese and Japanese characters have several
+// readings, Arabic and Hebrew are written without most vowels, and restored Vietnamese
+// diacritics are a guess. Edit the table to tune a pair; the bounds apply to both
+// confidence_score and confidence_factors.score.
+var confidenceBounds = map[string]map[string]confidenceBound{
+	"chinese":  {"latin": {0.0, 0.8}, "ascii": {0.0, 0.8}},
+	"japanese": {"latin": {0.0, 0.8}, "ascii": {0.0, 0.8}},
+	"arabic":   {"latin": {0.0, 0.85}, "ascii": {0.0, 0.85}},
+	"hebrew":   {"latin": {0.0, 0.85}, "ascii": {0.0, 0.85}},
+	"latin":    {"vietnamese": {0.0, 0.5}},
+	"ascii":    {"vietnamese": {0.0, 0.5}},
+}
+
+// confidenceBoundFor returns the bounds of a script pair
+func confidenceBoundFor(inputScript, outputScript string) confidenceBound {
+	if bound, ok := confidenceBounds[inputScript][outputScript]; ok {
+		return bound
+	}
+	return defaultConfidenceBound
+}
+
+// boundConfidence clamps a confidence score to its script pair's bounds
+func boundConfidence(inputScript, outputScript string, score float64) float64 {
+	bound := confidenceBoundFor(inputScript, outputScript)
+	return max(bound.Floor, min(bound.Ceiling, score))
+}
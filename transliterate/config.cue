@@ -0,0 +1,10 @@
+// Deployment configuration for the transliterate service
+
+// Set to true where gender must not be inferred at all: responses then never include
+// `gender`, and requests asking for it with "infer_gender": true are rejected.
+DisableGenderInference: false
+
+// Stored transliterations scoring below RescoreConfidenceThreshold are rescored by the
+// rescore-transliterations cron job, RescoreBatchSize at a time, least recently rescored first.
+RescoreBatchSize:           100
+RescoreConfidenceThreshold: 0.7
@@ -0,0 +1,32 @@
+package transliterate
+
+import "encore.dev/config"
+
+// Config is the service's deployment configuration, set per environment in config.cue
+type Config struct {
+	// DisableGenderInference turns gender inference off for every request, for deployments
+	// that must not infer gender; requests then never carry a gender
+	DisableGenderInference bool
+
+	// RescoreBatchSize is how many stored transliterations each rescoring run recomputes
+	RescoreBatchSize int
+
+	// RescoreConfidenceThreshold selects stored transliterations scoring below it for rescoring
+	RescoreConfidenceThreshold float64
+}
+
+// cfg is the configuration of the environment the service runs in. Encore loads it when the
+// service starts, under encore run and encore test alike; config.Load panics outside the
+// Encore runtime, so cfg is never nil.
+var cfg = config.Load[*Config]()
+
+// genderInferenceDisabled reports whether the deployment has turned gender inference off
+func genderInferenceDisabled() bool {
+	return cfg.DisableGenderInference
+}
+
+// infersGender reports whether a request gets gender inference: unless the deployment has
+// turned it off, it runs when infer_gender is true or omitted
+func infersGender(requested *bool) bool {
+	return !genderInferenceDisabled() && (requested == nil || *requested)
+}
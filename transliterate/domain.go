@@ -0,0 +1,57 @@
+package transliterate
+
+import "strings"
+
+// maxDomainLabelLength is the longest DNS label, in bytes (RFC 1035)
+const maxDomainLabelLength = 63
+
+// applyDomainSafe replaces the output with a DNS label when domain_safe is requested
+func applyDomainSafe(resp *TransliterationResponse, req *TransliterationRequest) {
+	if !req.DomainSafe {
+		return
+	}
+	resp.OutputText = domainLabel(resp.OutputText, resp.Name)
+}
+
+// domainLabel reduces ASCII output to the letters, digits and hyphens of a DNS label for a
+// domain or email local part: titles the name parser found are dropped, letters lowercased,
+// spaces, hyphens and underscores joined into single hyphens and everything else removed
+// ('Dr. Juergen Gross' juergen-gross, "O'Brien" obrien). Output without a letter or digit
+// gives an empty label.
+func domainLabel(output string, name *NameStructure) string {
+	titles := make(map[string]bool)
+	if name != nil {
+		for _, title := range name.Titles {
+			titles[strings.ToLower(strings.TrimSuffix(title, "."))] = true
+		}
+	}
+
+	var label strings.Builder
+	for _, word := range strings.Fields(output) {
+		if titles[strings.ToLower(strings.TrimSuffix(word, "."))] {
+			continue
+		}
+		label.WriteByte('-')
+		for _, r := range strings.ToLower(word) {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				label.WriteRune(r)
+			case r == '-' || r == '_':
+				label.WriteByte('-')
+			}
+		}
+	}
+
+	// Labels neither start nor end with a hyphen, and the hyphens between words collapse
+	result := collapseHyphens(label.String())
+	if len(result) > maxDomainLabelLength {
+		result = strings.TrimRight(result[:maxDomainLabelLength], "-")
+	}
+	return result
+}
+
+// collapseHyphens joins runs of hyphens into one and trims them from both ends
+func collapseHyphens(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' })
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,182 @@
+package transliterate
+
+import (
+	"fmt"
+	"strings"
+
+	"encore.dev/beta/errs"
+)
+
+// ErrorDetails carries a stable, machine-readable reason alongside the Encore error code
+// so clients can branch on failures without matching message strings
+type ErrorDetails struct {
+	Reason       string       `json:"reason"`                  // e.g. "unsupported_script_pair", "text_too_long"
+	Errors       []FieldError `json:"errors,omitempty"`        // Every failed check when a request is invalid
+	ValidTargets []string     `json:"valid_targets,omitempty"` // Output scripts the input script converts to, for unsupported_script_pair
+}
+
+// FieldError describes one invalid request field
+type FieldError struct {
+	Field   string `json:"field"`   // JSON name of the field, e.g. "output_script"
+	Code    string `json:"code"`    // Stable reason, as in ErrorDetails.Reason
+	Message string `json:"message"` // Human-readable description
+}
+
+// ErrDetails marks ErrorDetails as Encore error details
+func (ErrorDetails) ErrDetails() {}
+
+// Stable error reasons returned in ErrorDetails.Reason
+const (
+	ReasonRequestMissing                = "request_missing"
+	ReasonTextEmpty                     = "text_empty"
+	ReasonTextTooLong                   = "text_too_long"
+	ReasonInvalidUTF8                   = "invalid_utf8"
+	ReasonInvalidMaxAlternatives        = "invalid_max_alternatives"
+	ReasonInvalidMaxLength              = "invalid_max_length"
+	ReasonInputScriptRequired           = "input_script_required"
+	ReasonOutputScriptRequired          = "output_script_required"
+	ReasonUnsupportedInputScript        = "unsupported_input_script"
+	ReasonUnsupportedOutputScript       = "unsupported_output_script"
+	ReasonDuplicateOutputScript         = "duplicate_output_script"
+	ReasonTooManyOutputScripts          = "too_many_output_scripts"
+	ReasonInvalidLocale                 = "invalid_locale"
+	ReasonInvalidBoundarySpacing        = "invalid_boundary_spacing"
+	ReasonInvalidLongVowels             = "invalid_long_vowels"
+	ReasonInvalidUnmappedPolicy         = "invalid_unmapped_policy"
+	ReasonInvalidNameFormat             = "invalid_name_format"
+	ReasonCultureRequired               = "culture_required"
+	ReasonUnsupportedCulture            = "unsupported_culture"
+	ReasonInvalidCodePointPolicy        = "invalid_code_point_policy"
+	ReasonInvalidCodePoints             = "invalid_code_points"
+	ReasonUnsupportedStandard           = "unsupported_standard"
+	ReasonStandardScriptMismatch        = "standard_script_mismatch"
+	ReasonInvalidScriptMismatchPolicy   = "invalid_script_mismatch_policy"
+	ReasonScriptMismatch                = "script_mismatch"
+	ReasonInvalidInlineTemplate         = "invalid_inline_template"
+	ReasonInvalidDomainSafe             = "invalid_domain_safe"
+	ReasonScriptUndetectable            = "script_undetectable"
+	ReasonInvalidMinDetectionConfidence = "invalid_min_detection_confidence"
+	ReasonDetectionConfidenceLow        = "detection_confidence_low"
+	ReasonUnsupportedScriptPair         = "unsupported_script_pair"
+	ReasonInvalidID                     = "invalid_id"
+	ReasonNotFound                      = "transliteration_not_found"
+	ReasonCacheMiss                     = "cache_miss"
+	ReasonInvalidCacheOnly              = "invalid_cache_only"
+	ReasonGenderInferenceDisabled       = "gender_inference_disabled"
+	ReasonSuggestedOutputEmpty          = "suggested_output_empty"
+	ReasonSuggestedOutputTooLong        = "suggested_output_too_long"
+	ReasonInvalidFeedbackType           = "invalid_feedback_type"
+	ReasonFeedbackNotFound              = "feedback_not_found"
+	ReasonFeedbackAlreadyReviewed       = "feedback_already_reviewed"
+	ReasonFeedbackNotCorrection         = "feedback_not_correction"
+	ReasonCorrectionNotAlignable        = "correction_not_alignable"
+	ReasonInvalidLimit                  = "invalid_limit"
+	ReasonInvalidOffset                 = "invalid_offset"
+	ReasonInvalidMinConfidence          = "invalid_min_confidence"
+	ReasonInvalidNormalizationForm      = "invalid_normalization_form"
+	ReasonInvalidMapping                = "invalid_mapping"
+	ReasonTooManyMappings               = "too_many_mappings"
+	ReasonInvalidProfileName            = "invalid_profile_name"
+	ReasonProfileNotFound               = "profile_not_found"
+	ReasonProfileBuiltIn                = "profile_built_in"
+	ReasonUnsupportedAPIVersion         = "unsupported_api_version"
+	ReasonInvalidToken                  = "invalid_token"
+	ReasonTransliterationFailed         = "transliteration_failed"
+	ReasonDatabaseError                 = "database_error"
+	ReasonDatabaseUnavailable           = "database_unavailable"
+)
+
+// invalidArgument builds an InvalidArgument error with a stable reason
+func invalidArgument(reason, format string, args ...any) error {
+	return &errs.Error{
+		Code:    errs.InvalidArgument,
+		Message: fmt.Sprintf(format, args...),
+		Details: ErrorDetails{Reason: reason},
+	}
+}
+
+// notFound builds a NotFound error with a stable reason
+func notFound(reason, format string, args ...any) error {
+	return &errs.Error{
+		Code:    errs.NotFound,
+		Message: fmt.Sprintf(format, args...),
+		Details: ErrorDetails{Reason: reason},
+	}
+}
+
+// failedPrecondition builds a FailedPrecondition error with a stable reason, for requests that
+// are valid but conflict with the current state
+func failedPrecondition(reason, format string, args ...any) error {
+	return &errs.Error{
+		Code:    errs.FailedPrecondition,
+		Message: fmt.Sprintf(format, args...),
+		Details: ErrorDetails{Reason: reason},
+	}
+}
+
+// internalError builds an Internal error with a stable reason. The cause is logged rather than
+// returned, since it can describe the database or other internals to clients.
+func internalError(reason string, cause error, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	logError(message, "reason", reason, "err", cause)
+	return &errs.Error{
+		Code:    errs.Internal,
+		Message: message,
+		Details: ErrorDetails{Reason: reason},
+	}
+}
+
+// unavailable builds an Unavailable (503) error with a stable reason, logging the cause as
+// internalError does
+func unavailable(reason string, cause error, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	logError(message, "reason", reason, "err", cause)
+	return &errs.Error{
+		Code:    errs.Unavailable,
+		Message: message,
+		Details: ErrorDetails{Reason: reason},
+	}
+}
+
+// unsupportedScriptPair builds the InvalidArgument error for a script pair without a
+// conversion, listing the output scripts the input script does convert to so clients can
+// offer a supported direction
+func unsupportedScriptPair(inputScript, outputScript string) error {
+	targets := supportedOutputScripts(inputScript)
+	message := fmt.Sprintf("unsupported script conversion: %s to %s", inputScript, outputScript)
+	if len(targets) > 0 {
+		message += fmt.Sprintf(" (%s converts to %s)", inputScript, strings.Join(targets, ", "))
+	}
+	return &errs.Error{
+		Code:    errs.InvalidArgument,
+		Message: message,
+		Details: ErrorDetails{Reason: ReasonUnsupportedScriptPair, ValidTargets: targets},
+	}
+}
+
+// validationErrors collects every failed check so clients can fix a request in one pass
+type validationErrors []FieldError
+
+// add records a failed check on field
+func (v *validationErrors) add(field, reason, format string, args ...any) {
+	*v = append(*v, FieldError{Field: field, Code: reason, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns nil if no check failed, otherwise an InvalidArgument error listing every
+// failure; its Reason is the first failure's, for clients that only read one
+func (v validationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(v))
+	for i, problem := range v {
+		messages[i] = problem.Message
+	}
+
+	return &errs.Error{
+		Code:    errs.InvalidArgument,
+		Message: strings.Join(messages, "; "),
+		Details: ErrorDetails{Reason: v[0].Code, Errors: v},
+	}
+}
@@ -0,0 +1,307 @@
+package transliterate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"encore.app/transliterate/internal/detection"
+	"encore.app/transliterate/internal/learning"
+	"encore.app/transliterate/internal/transliteration"
+
+	"encore.dev"
+	"encore.dev/storage/sqldb"
+)
+
+// minCorroboratingCorrections is how many distinct transliterations, corrected by as many
+// distinct clients, must suggest the same mapping before it is promoted to
+// character_mappings, and how many distinct clients must prefer the same output before it
+// replaces the cached one
+const minCorroboratingCorrections = 2
+
+// feedbackClient identifies the client submitting feedback; tests replace it to submit as
+// several clients
+var feedbackClient = requestClient
+
+// requestClient identifies the client of the current request by the peer address the gateway
+// saw, so corroboration can't come from one client alone. It is "" when the address isn't
+// known.
+func requestClient() string {
+	req := encore.CurrentRequest()
+	if req == nil {
+		return ""
+	}
+	return clientFromHeaders(req.Headers)
+}
+
+// clientFromHeaders hashes the last X-Forwarded-For hop, the peer address appended by the
+// gateway. Earlier hops and X-Real-IP are written by the caller, so they could make one client
+// pass for many, and are ignored.
+func clientFromHeaders(headers http.Header) string {
+	forwarded := headers.Values("X-Forwarded-For")
+	if len(forwarded) == 0 {
+		return ""
+	}
+	hops := strings.Split(forwarded[len(forwarded)-1], ",")
+	addr := strings.TrimSpace(hops[len(hops)-1])
+	if addr == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(addr))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Weight adjustments applied when a learned mapping is promoted
+const (
+	learnedWeightMargin = 0.05 // Promoted mappings outrank the best competing mapping by this much
+	competingWeightStep = 0.05 // Competing mappings for the same character are demoted by this much
+)
+
+// learnFromCorrection records the character mapping implied by a correction from client and
+// promotes it once enough distinct transliterations and clients agree, approving the pending
+// corrections that implied it. Suggestions from unknown clients are recorded but corroborate
+// nothing.
+func learnFromCorrection(ctx context.Context, original *TransliterationResponse, suggested, client string) error {
+	// Align with the computed output, not a preferred output that has replaced it
+	computed := *original
+	err := db.QueryRow(ctx, `SELECT output_text FROM transliterations WHERE id = $1`, original.ID).Scan(&computed.OutputText)
+	if err != nil {
+		return err
+	}
+
+	mapping, ok, err := impliedMapping(ctx, &computed, suggested)
+	if err != nil || !ok {
+		return err
+	}
+
+	_, err = db.Exec(ctx, `
+		INSERT INTO mapping_suggestions (transliteration_id, source_char, target_char, source_script, target_script, client_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT DO NOTHING
+	`, original.ID, mapping.Source, mapping.Target, mapping.Script, original.OutputScript, client)
+	if err != nil {
+		return err
+	}
+
+	var transliterations, clients int
+	err = db.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT transliteration_id), COUNT(DISTINCT client_id)
+		FROM mapping_suggestions
+		WHERE source_char = $1 AND target_char = $2 AND source_script = $3 AND target_script = $4
+	`, mapping.Source, mapping.Target, mapping.Script, original.OutputScript).Scan(&transliterations, &clients)
+	if err != nil {
+		return err
+	}
+
+	if transliterations < minCorroboratingCorrections || clients < minCorroboratingCorrections {
+		return nil
+	}
+
+	// Found before promotion, since the corrections are aligned with the outputs they corrected
+	corroborating, err := correctionsImplying(ctx, mapping, original.OutputScript)
+	if err != nil {
+		return err
+	}
+	return promoteMapping(ctx, mapping, original.OutputScript, corroborating)
+}
+
+// correctionsImplying returns the IDs of the pending corrections, on transliterations that
+// suggested mapping, that imply it
+func correctionsImplying(ctx context.Context, mapping learning.Mapping, targetScript string) ([]string, error) {
+	rows, err := db.Query(ctx, `
+		SELECT f.id, f.suggested_output,
+			t.id, t.input_text, t.output_text, t.input_script, t.output_script, t.input_locale
+		FROM transliteration_feedback f
+		JOIN transliterations t ON t.id = f.transliteration_id
+		JOIN mapping_suggestions s ON s.transliteration_id = t.id
+		WHERE f.feedback_type = 'correction' AND f.status = $1
+			AND s.source_char = $2 AND s.target_char = $3 AND s.source_script = $4 AND s.target_script = $5
+	`, feedbackPending, mapping.Source, mapping.Target, mapping.Script, targetScript)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids, suggestions []string
+	var originals []*TransliterationResponse
+	for rows.Next() {
+		var id, suggested string
+		var original TransliterationResponse
+		if err := rows.Scan(&id, &suggested,
+			&original.ID, &original.InputText, &original.OutputText, &original.InputScript, &original.OutputScript, &original.InputLocale); err != nil {
+			return nil, err
+		}
+		ids, suggestions = append(ids, id), append(suggestions, suggested)
+		originals = append(originals, &original)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Alignment looks mappings up, so it waits until the rows are read
+	var implying []string
+	for i, original := range originals {
+		implied, ok, err := impliedMapping(ctx, original, suggestions[i])
+		if err != nil {
+			return nil, err
+		}
+		if ok && implied == mapping {
+			implying = append(implying, ids[i])
+		}
+	}
+	return implying, nil
+}
+
+// applyPreferredOutput records a preferred output for a transliteration once enough distinct
+// clients, including the client whose feedback was just stored, prefer the same form. Clients are
+// those identified by requestClient, so a caller can't agree with itself by rewriting its
+// forwarding headers; feedback from unknown clients agrees with nothing.
+func applyPreferredOutput(ctx context.Context, original *TransliterationResponse, suggested, client string) error {
+	if client == "" {
+		return nil
+	}
+
+	var agreeing int
+	err := db.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT client_id)
+		FROM transliteration_feedback
+		WHERE transliteration_id = $1 AND feedback_type = 'preferred' AND suggested_output = $2
+			AND client_id IS NOT NULL
+	`, original.ID, suggested).Scan(&agreeing)
+	if err != nil {
+		return err
+	}
+
+	if agreeing < minCorroboratingCorrections {
+		return nil
+	}
+
+	_, err = db.Exec(ctx, `
+		UPDATE transliterations
+		SET preferred_output = $2, updated_at = NOW()
+		WHERE id = $1
+	`, original.ID, suggested)
+	return err
+}
+
+// impliedMapping aligns a correction with the per-character output of the transliteration
+// it corrects, returning the single character mapping it changes. Corrections that change
+// more than one character, or outputs that no longer match their recomputation (options such
+// as number words were used, or mappings have since changed), imply no mapping.
+func impliedMapping(ctx context.Context, original *TransliterationResponse, suggested string) (learning.Mapping, bool, error) {
+	scriptInfo := detection.DetectScript(original.InputText)
+	language := detection.DetectLanguage(original.InputText, scriptInfo).Language
+	if original.InputLocale != nil {
+		language = *original.InputLocale
+	}
+	engine := transliteration.NewEngine(transliteration.DefaultConfig(), db)
+
+	segments, err := engine.Segments(ctx, original.InputText, original.InputScript, original.OutputScript, language)
+	if err != nil {
+		return learning.Mapping{}, false, err
+	}
+
+	var recomputed strings.Builder
+	for _, segment := range segments {
+		recomputed.WriteString(segment.Output)
+	}
+	if recomputed.String() != original.OutputText {
+		return learning.Mapping{}, false, nil
+	}
+
+	mapping, ok := learning.DiffCorrection(segments, suggested)
+	if !ok || !isLearnableTarget(mapping.Target, original.OutputScript) {
+		return learning.Mapping{}, false, nil
+	}
+	return mapping, true, nil
+}
+
+// promoteMapping upserts a learned mapping so it outranks competing mappings for its character,
+// marking the pending corrections that corroborated it approved
+func promoteMapping(ctx context.Context, mapping learning.Mapping, targetScript string, corroborating []string) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := promoteMappingTx(ctx, tx, mapping, targetScript); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE transliteration_feedback
+		SET status = $2, reviewed_at = NOW()
+		WHERE id = ANY($1::uuid[]) AND status = $3
+	`, corroborating, feedbackApproved, feedbackPending)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// promoteMappingTx promotes a learned mapping within tx
+func promoteMappingTx(ctx context.Context, tx *sqldb.Tx, mapping learning.Mapping, targetScript string) error {
+	var bestCompeting float64
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(frequency_weight), 0.50)
+		FROM character_mappings
+		WHERE source_char = $1 AND source_script = $2 AND target_script = $3 AND target_char <> $4
+	`, mapping.Source, mapping.Script, targetScript, mapping.Target).Scan(&bestCompeting)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE character_mappings
+		SET frequency_weight = GREATEST(0.00, frequency_weight - $5), updated_at = NOW()
+		WHERE source_char = $1 AND source_script = $2 AND target_script = $3 AND target_char <> $4
+	`, mapping.Source, mapping.Script, targetScript, mapping.Target, competingWeightStep)
+	if err != nil {
+		return err
+	}
+
+	weight := bestCompeting + learnedWeightMargin
+	if weight > 1.0 {
+		weight = 1.0
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE character_mappings
+		SET frequency_weight = $5, updated_at = NOW()
+		WHERE source_char = $1 AND source_script = $2 AND target_script = $3 AND target_char = $4
+	`, mapping.Source, mapping.Script, targetScript, mapping.Target, weight)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO character_mappings (source_char, target_char, source_script, target_script, frequency_weight)
+			VALUES ($1, $2, $3, $4, $5)
+		`, mapping.Source, mapping.Target, mapping.Script, targetScript, weight)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLearnableTarget rejects corrections that don't fit the output script or the mappings table
+func isLearnableTarget(target, outputScript string) bool {
+	if len(target) > 50 || strings.TrimSpace(target) != target {
+		return false
+	}
+
+	for _, r := range target {
+		if unicode.IsControl(r) || (outputScript == "ascii" && r > unicode.MaxASCII) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+package transliterate
+
+import (
+	"context"
+)
+
+// HealthResponse reports that the service and its database are reachable
+type HealthResponse struct {
+	Status string `json:"status"` // Always "ok"; failures are returned as errors
+	DB     string `json:"db"`     // "up" when the transliterate database answered
+}
+
+// Healthz checks that the service is running and the database answers a trivial query
+//
+//encore:api public method=GET path=/api/healthz
+func Healthz(ctx context.Context) (*HealthResponse, error) {
+	var one int
+	if err := db.QueryRow(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return nil, unavailable(ReasonDatabaseUnavailable, err, "database unreachable")
+	}
+	return &HealthResponse{Status: "ok", DB: "up"}, nil
+}
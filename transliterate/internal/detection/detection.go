@@ -16,13 +16,43 @@ type ScriptInfo struct {
 
 // LanguageHint provides hints about the likely language
 type LanguageHint struct {
-	Language   string  // Language code (e.g., "vi", "zh", "ru")
-	Confidence float64 // Confidence score (0.0-1.0)
-	Indicators []string // What led to this detection
+	Language   string   `json:"language"`   // Language code (e.g., "vi", "zh", "ru")
+	Confidence float64  `json:"confidence"` // Confidence score (0.0-1.0)
+	Indicators []string `json:"indicators"` // What led to this detection
 }
 
-// DetectScript identifies the primary script used in the text
+// Latin letters are incidental, and the text has no script, when there are fewer than
+// minLatinLetters of them and they make up less than minLatinLetterShare of the characters
+// other than spaces (serial numbers and codes such as "12345 AB" or "A1-234-567")
+const (
+	minLatinLetters     = 4
+	minLatinLetterShare = 0.5
+)
+
+// Large inputs are classified from a sample: once detectionSampleLetters letters have been
+// counted, and again after each further sample of that size, counting stops if one script
+// has at least detectionDominance of the letters so far. Mixed text is counted to the end.
+const (
+	detectionSampleLetters = 4096
+	detectionDominance     = 0.9
+)
+
+// DetectScript identifies the primary script used in the text. Text longer than a sample
+// whose letters are clearly of one script is classified from its beginning, so Details then
+// counts only the letters examined; DetectScriptFull counts every letter.
 func DetectScript(text string) ScriptInfo {
+	return detectScript(text, detectionSampleLetters)
+}
+
+// DetectScriptFull identifies the primary script from every letter of the text, for callers
+// reporting exact per-script counts
+func DetectScriptFull(text string) ScriptInfo {
+	return detectScript(text, 0)
+}
+
+// detectScript classifies text, stopping early on a clear result after each sample of
+// sampleLetters letters; zero examines every letter
+func detectScript(text string, sampleLetters int) ScriptInfo {
 	if text == "" {
 		return ScriptInfo{Script: "unknown", Confidence: 0.0}
 	}
@@ -30,12 +60,20 @@ func DetectScript(text string) ScriptInfo {
 	// Count characters by script
 	scriptCounts := make(map[string]int)
 	totalLetters := 0
+	visible := 0
 
 	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			visible++
+		}
 		if unicode.IsLetter(r) {
 			totalLetters++
-			script := classifyRune(r)
+			script := ClassifyRune(r)
 			scriptCounts[script]++
+
+			if sampleLetters > 0 && totalLetters%sampleLetters == 0 && clearlyDominant(scriptCounts, totalLetters) {
+				break
+			}
 		}
 	}
 
@@ -43,25 +81,11 @@ func DetectScript(text string) ScriptInfo {
 		return ScriptInfo{Script: "unknown", Confidence: 0.0, Details: scriptCounts}
 	}
 
-	// Find the dominant script, prioritizing specific language variants
-	maxScript := "unknown"
-	maxCount := 0
-	
-	// Check for Vietnamese first (it's more specific than general latin)
-	if scriptCounts["vietnamese"] > 0 {
-		maxScript = "vietnamese"
-		maxCount = scriptCounts["vietnamese"]
-	} else if scriptCounts["german"] > 0 {
-		maxScript = "german"
-		maxCount = scriptCounts["german"]
-	} else {
-		// Fall back to highest count
-		for script, count := range scriptCounts {
-			if count > maxCount {
-				maxScript = script
-				maxCount = count
-			}
-		}
+	maxScript, maxCount := dominantScript(scriptCounts)
+
+	// A few Latin letters among digits and punctuation label a number rather than write text
+	if isLatinFamily(maxScript) && totalLetters < minLatinLetters && float64(totalLetters) < minLatinLetterShare*float64(visible) {
+		return ScriptInfo{Script: "unknown", Confidence: 0.0, Details: scriptCounts}
 	}
 
 	// Calculate confidence
@@ -86,18 +110,56 @@ func DetectScript(text string) ScriptInfo {
 	}
 }
 
+// dominantScript picks the script of the text from its letter counts, with the count behind
+// it. Vietnamese and then German letters decide the script however few there are, since
+// they are more specific than general Latin.
+func dominantScript(scriptCounts map[string]int) (string, int) {
+	if scriptCounts["vietnamese"] > 0 {
+		return "vietnamese", scriptCounts["vietnamese"]
+	}
+	if scriptCounts["german"] > 0 {
+		return "german", scriptCounts["german"]
+	}
+
+	// Fall back to highest count
+	maxScript := "unknown"
+	maxCount := 0
+	for script, count := range scriptCounts {
+		if count > maxCount {
+			maxScript = script
+			maxCount = count
+		}
+	}
+	return maxScript, maxCount
+}
+
+// clearlyDominant reports whether the letters counted so far settle the script: a Vietnamese
+// letter always does, as does one script having at least detectionDominance of the letters
+func clearlyDominant(scriptCounts map[string]int, totalLetters int) bool {
+	if scriptCounts["vietnamese"] > 0 {
+		return true
+	}
+	for _, count := range scriptCounts {
+		if float64(count) >= detectionDominance*float64(totalLetters) {
+			return true
+		}
+	}
+	return false
+}
+
 // DetectLanguage attempts to identify the language based on text characteristics
 func DetectLanguage(text string, scriptInfo ScriptInfo) LanguageHint {
 	indicators := make([]string, 0)
 	lowerText := strings.ToLower(text)
 	
 	switch scriptInfo.Script {
-	case "vietnamese", "latin":
-		if isVietnamese(lowerText) {
+	case "vietnamese", "german", "latin":
+		// Script detection already found letters unique to Vietnamese or German
+		if scriptInfo.Script == "vietnamese" || isVietnamese(lowerText) {
 			indicators = append(indicators, "vietnamese_diacritics")
 			return LanguageHint{Language: "vi", Confidence: 0.85, Indicators: indicators}
 		}
-		if isGerman(lowerText) {
+		if scriptInfo.Script == "german" || isGerman(lowerText) {
 			indicators = append(indicators, "german_umlauts")
 			return LanguageHint{Language: "de", Confidence: 0.80, Indicators: indicators}
 		}
@@ -133,13 +195,30 @@ func DetectLanguage(text string, scriptInfo ScriptInfo) LanguageHint {
 	case "greek":
 		indicators = append(indicators, "greek_script")
 		return LanguageHint{Language: "el", Confidence: 0.90, Indicators: indicators}
+
+	case "mongolian":
+		indicators = append(indicators, "mongolian_script")
+		return LanguageHint{Language: "mn", Confidence: 0.90, Indicators: indicators}
+
+	case "hebrew":
+		indicators = append(indicators, "hebrew_script")
+		return LanguageHint{Language: "he", Confidence: 0.90, Indicators: indicators}
+
+	case "armenian":
+		indicators = append(indicators, "armenian_script")
+		return LanguageHint{Language: "hy", Confidence: 0.90, Indicators: indicators}
+
+	case "ethiopic":
+		// Amharic is the most widely written language in the script, ahead of Tigrinya
+		indicators = append(indicators, "ethiopic_script")
+		return LanguageHint{Language: "am", Confidence: 0.70, Indicators: indicators}
 	}
 
 	return LanguageHint{Language: "unknown", Confidence: 0.1, Indicators: indicators}
 }
 
-// classifyRune determines which script family a rune belongs to
-func classifyRune(r rune) string {
+// ClassifyRune determines which script family a rune belongs to
+func ClassifyRune(r rune) string {
 	switch {
 	// Cyrillic
 	case r >= 0x0400 && r <= 0x04FF:
@@ -195,6 +274,18 @@ func classifyRune(r rune) string {
 	case r >= 0x0E00 && r <= 0x0E7F:
 		return "thai"
 
+	// Armenian
+	case r >= 0x0530 && r <= 0x058F:
+		return "armenian"
+
+	// Ethiopic (Ge'ez syllabary for Amharic and Tigrinya)
+	case r >= 0x1200 && r <= 0x137F:
+		return "ethiopic"
+
+	// Traditional Mongolian
+	case r >= 0x1800 && r <= 0x18AF:
+		return "mongolian"
+
 	// Korean
 	case r >= 0xAC00 && r <= 0xD7AF: // Hangul Syllables
 		return "korean"
@@ -305,11 +396,16 @@ func IsValidUTF8(text string) bool {
 // ContainsScript checks if text contains characters from a specific script
 func ContainsScript(text, script string) bool {
 	for _, r := range text {
-		if unicode.IsLetter(r) && classifyRune(r) == script {
+		if unicode.IsLetter(r) && ClassifyRune(r) == script {
 			return true
 		}
 	}
 	return false
 }
 
-// Note: Removed whatlanggo dependency due to compilation issues
\ No newline at end of file
+// Note: Removed whatlanggo dependency due to compilation issues
+
+// isLatinFamily reports whether script is Latin or one of its language-specific variants
+func isLatinFamily(script string) bool {
+	return script == "latin" || script == "german" || script == "vietnamese"
+}
@@ -5,12 +5,53 @@ import (
 	"strings"
 )
 
+// Gender values reported in Inference.Value
+const (
+	Male      = "M"
+	Female    = "F"
+	NonBinary = "X" // An explicit gender-neutral signal, such as the title Mx
+	Unknown   = "U" // No usable signal; Confidence is then at most 0.2
+)
+
 // Inference represents a gender inference with confidence and reasoning
 type Inference struct {
-	Value      string  `json:"value"`      // M, F, or X (unknown/non-binary)
+	Value      string  `json:"value"`      // M, F, X (explicitly non-binary) or U (unknown)
 	Confidence float64 `json:"confidence"` // 0.0 to 1.0
 	Source     string  `json:"source"`     // "cultural_marker", "statistical", "unknown"
 	Reason     string  `json:"reason"`     // Human-readable explanation
+	Distribution map[string]float64 `json:"distribution,omitempty"` // Probabilities of M, F and X summing to 1, only when requested
+}
+
+// priorWeights are the relative odds of each value before any signal: the binary values
+// equally likely and X half as likely as either
+var priorWeights = map[string]float64{Male: 2, Female: 2, NonBinary: 1}
+
+// Distribution spreads an inference over M, F and X: the inferred value gets its
+// confidence, and the rest goes to the other values in proportion to priorWeights, so a
+// 0.7 M gives {M: 0.7, F: 0.2, X: 0.1}. An unknown inference gives the prior itself.
+func Distribution(inference *Inference) map[string]float64 {
+	var total float64
+	for _, weight := range priorWeights {
+		total += weight
+	}
+
+	distribution := make(map[string]float64, len(priorWeights))
+	if _, known := priorWeights[inference.Value]; !known {
+		for value, weight := range priorWeights {
+			distribution[value] = weight / total
+		}
+		return distribution
+	}
+
+	rest := total - priorWeights[inference.Value]
+	for value, weight := range priorWeights {
+		if value == inference.Value {
+			distribution[value] = inference.Confidence
+		} else {
+			distribution[value] = (1 - inference.Confidence) * weight / rest
+		}
+	}
+	return distribution
 }
 
 // Engine provides gender inference capabilities
@@ -31,7 +72,7 @@ func NewEngine(useStatistical, culturalOnly bool) *Engine {
 func (e *Engine) InferGender(originalText, transliteratedText, culture, language string) *Inference {
 	// Default to unknown
 	result := &Inference{
-		Value:      "X",
+		Value:      Unknown,
 		Confidence: 0.1,
 		Source:     "unknown",
 		Reason:     "No gender indicators found",
@@ -79,6 +120,9 @@ func (e *Engine) inferFromCulturalMarkers(original, transliterated, culture, lan
 	case culture == "thai" || language == "th":
 		return e.inferThai(transliterated)
 		
+	case culture == "russian" || language == "ru":
+		return e.inferRussian(transliterated, language)
+		
 	default:
 		return e.inferWestern(transliterated, language)
 	}
@@ -92,7 +136,7 @@ func (e *Engine) inferVietnamese(original, transliterated string) *Inference {
 	// Vietnamese gender markers in middle names
 	if strings.Contains(originalLower, "văn") || strings.Contains(transliteratedLower, "van") {
 		return &Inference{
-			Value:      "M",
+			Value:      Male,
 			Confidence: 0.85,
 			Source:     "cultural_marker",
 			Reason:     "Vietnamese marker 'Văn' typically indicates male",
@@ -101,7 +145,7 @@ func (e *Engine) inferVietnamese(original, transliterated string) *Inference {
 	
 	if strings.Contains(originalLower, "thị") || strings.Contains(transliteratedLower, "thi") {
 		return &Inference{
-			Value:      "F",
+			Value:      Female,
 			Confidence: 0.85,
 			Source:     "cultural_marker",
 			Reason:     "Vietnamese marker 'Thị' typically indicates female",
@@ -115,7 +159,7 @@ func (e *Engine) inferVietnamese(original, transliterated string) *Inference {
 	for _, marker := range maleMarkers {
 		if strings.Contains(transliteratedLower, marker) {
 			return &Inference{
-				Value:      "M",
+				Value:      Male,
 				Confidence: 0.65,
 				Source:     "cultural_marker",
 				Reason:     "Vietnamese name pattern suggests male",
@@ -126,7 +170,7 @@ func (e *Engine) inferVietnamese(original, transliterated string) *Inference {
 	for _, marker := range femaleMarkers {
 		if strings.Contains(transliteratedLower, marker) {
 			return &Inference{
-				Value:      "F",
+				Value:      Female,
 				Confidence: 0.65,
 				Source:     "cultural_marker",
 				Reason:     "Vietnamese name pattern suggests female",
@@ -134,7 +178,7 @@ func (e *Engine) inferVietnamese(original, transliterated string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "No Vietnamese gender markers found"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "No Vietnamese gender markers found"}
 }
 
 // inferArabic uses Arabic patronymic indicators
@@ -143,7 +187,7 @@ func (e *Engine) inferArabic(text string) *Inference {
 	
 	if strings.Contains(textLower, "bin ") || strings.Contains(textLower, "ibn ") {
 		return &Inference{
-			Value:      "M",
+			Value:      Male,
 			Confidence: 0.90,
 			Source:     "cultural_marker",
 			Reason:     "Arabic patronymic 'bin/ibn' (son of) indicates male",
@@ -152,7 +196,7 @@ func (e *Engine) inferArabic(text string) *Inference {
 	
 	if strings.Contains(textLower, "bint ") || strings.Contains(textLower, "binte ") {
 		return &Inference{
-			Value:      "F",
+			Value:      Female,
 			Confidence: 0.90,
 			Source:     "cultural_marker",
 			Reason:     "Arabic patronymic 'bint' (daughter of) indicates female",
@@ -166,7 +210,7 @@ func (e *Engine) inferArabic(text string) *Inference {
 	for _, name := range maleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "M",
+				Value:      Male,
 				Confidence: 0.75,
 				Source:     "cultural_marker",
 				Reason:     "Common Arabic male name pattern",
@@ -177,7 +221,7 @@ func (e *Engine) inferArabic(text string) *Inference {
 	for _, name := range femaleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "F",
+				Value:      Female,
 				Confidence: 0.75,
 				Source:     "cultural_marker",
 				Reason:     "Common Arabic female name pattern",
@@ -185,7 +229,7 @@ func (e *Engine) inferArabic(text string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "No Arabic gender markers found"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "No Arabic gender markers found"}
 }
 
 // inferIndonesian uses Indonesian/Malaysian patronymic patterns
@@ -194,7 +238,7 @@ func (e *Engine) inferIndonesian(text string) *Inference {
 	
 	if strings.Contains(textLower, "bin ") {
 		return &Inference{
-			Value:      "M",
+			Value:      Male,
 			Confidence: 0.88,
 			Source:     "cultural_marker",
 			Reason:     "Malay/Indonesian patronymic 'bin' (son of) indicates male",
@@ -203,7 +247,7 @@ func (e *Engine) inferIndonesian(text string) *Inference {
 	
 	if strings.Contains(textLower, "binti ") || strings.Contains(textLower, "binte ") {
 		return &Inference{
-			Value:      "F",
+			Value:      Female,
 			Confidence: 0.88,
 			Source:     "cultural_marker",
 			Reason:     "Malay/Indonesian patronymic 'binti' (daughter of) indicates female",
@@ -217,7 +261,7 @@ func (e *Engine) inferIndonesian(text string) *Inference {
 	for _, name := range maleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "M",
+				Value:      Male,
 				Confidence: 0.70,
 				Source:     "cultural_marker",
 				Reason:     "Indonesian male name pattern",
@@ -228,7 +272,7 @@ func (e *Engine) inferIndonesian(text string) *Inference {
 	for _, name := range femaleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "F",
+				Value:      Female,
 				Confidence: 0.70,
 				Source:     "cultural_marker",
 				Reason:     "Indonesian female name pattern",
@@ -236,7 +280,7 @@ func (e *Engine) inferIndonesian(text string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "No Indonesian gender markers found"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "No Indonesian gender markers found"}
 }
 
 // inferChinese uses Chinese name patterns (limited accuracy)
@@ -253,7 +297,7 @@ func (e *Engine) inferChinese(original, transliterated string) *Inference {
 	for _, indicator := range maleIndicators {
 		if strings.Contains(textLower, indicator) {
 			return &Inference{
-				Value:      "M",
+				Value:      Male,
 				Confidence: 0.55,
 				Source:     "statistical",
 				Reason:     "Chinese name element suggests male (low confidence)",
@@ -264,7 +308,7 @@ func (e *Engine) inferChinese(original, transliterated string) *Inference {
 	for _, indicator := range femaleIndicators {
 		if strings.Contains(textLower, indicator) {
 			return &Inference{
-				Value:      "F",
+				Value:      Female,
 				Confidence: 0.55,
 				Source:     "statistical",
 				Reason:     "Chinese name element suggests female (low confidence)",
@@ -272,7 +316,7 @@ func (e *Engine) inferChinese(original, transliterated string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "Chinese names require cultural knowledge for gender inference"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "Chinese names require cultural knowledge for gender inference"}
 }
 
 // inferJapanese uses Japanese name patterns (limited)
@@ -282,7 +326,7 @@ func (e *Engine) inferJapanese(original, transliterated string) *Inference {
 	// Common Japanese name endings
 	if strings.HasSuffix(textLower, "ko") || strings.HasSuffix(textLower, "mi") || strings.HasSuffix(textLower, "ka") {
 		return &Inference{
-			Value:      "F",
+			Value:      Female,
 			Confidence: 0.70,
 			Source:     "cultural_marker",
 			Reason:     "Japanese name ending suggests female",
@@ -291,21 +335,21 @@ func (e *Engine) inferJapanese(original, transliterated string) *Inference {
 	
 	if strings.HasSuffix(textLower, "ro") || strings.HasSuffix(textLower, "ta") || strings.HasSuffix(textLower, "ki") {
 		return &Inference{
-			Value:      "M",
+			Value:      Male,
 			Confidence: 0.60,
 			Source:     "cultural_marker",
 			Reason:     "Japanese name ending suggests male",
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "Japanese gender inference requires cultural context"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "Japanese gender inference requires cultural context"}
 }
 
 // inferKorean uses Korean name patterns (very limited)
 func (e *Engine) inferKorean(original, transliterated string) *Inference {
 	// Korean gender inference is extremely difficult without cultural knowledge
 	return &Inference{
-		Value:      "X",
+		Value:      Unknown,
 		Confidence: 0.1,
 		Source:     "unknown",
 		Reason:     "Korean names require cultural knowledge for gender inference",
@@ -323,7 +367,7 @@ func (e *Engine) inferIndian(text string) *Inference {
 	for _, name := range maleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "M",
+				Value:      Male,
 				Confidence: 0.75,
 				Source:     "cultural_marker",
 				Reason:     "Indian male name pattern",
@@ -334,7 +378,7 @@ func (e *Engine) inferIndian(text string) *Inference {
 	for _, name := range femaleNames {
 		if strings.Contains(textLower, name) {
 			return &Inference{
-				Value:      "F",
+				Value:      Female,
 				Confidence: 0.75,
 				Source:     "cultural_marker",
 				Reason:     "Indian female name pattern",
@@ -342,20 +386,50 @@ func (e *Engine) inferIndian(text string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "No Indian gender markers found"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "No Indian gender markers found"}
 }
 
 // inferThai uses Thai name patterns
 func (e *Engine) inferThai(text string) *Inference {
 	// Thai names are difficult to gender without cultural knowledge
 	return &Inference{
-		Value:      "X",
+		Value:      Unknown,
 		Confidence: 0.1,
 		Source:     "unknown",
 		Reason:     "Thai names require cultural knowledge for gender inference",
 	}
 }
 
+// inferRussian uses the patronymic, which is gendered (Ivanovich son of Ivan, Sergeevna
+// daughter of Sergei), falling back to given-name patterns. Some surnames also end in -ich
+// (Karpovich), so that ending only counts in names long enough to carry a patronymic.
+func (e *Engine) inferRussian(text string, language string) *Inference {
+	words := strings.Fields(strings.ToLower(text))
+	for i, word := range words {
+		if i == 0 || len(word) < 6 {
+			continue
+		}
+		if strings.HasSuffix(word, "ovna") || strings.HasSuffix(word, "evna") || strings.HasSuffix(word, "ichna") {
+			return &Inference{
+				Value:      Female,
+				Confidence: 0.90,
+				Source:     "cultural_marker",
+				Reason:     "Russian patronymic ending '-ovna/-evna' (daughter of) indicates female",
+			}
+		}
+		if strings.HasSuffix(word, "ich") && len(words) >= 3 {
+			return &Inference{
+				Value:      Male,
+				Confidence: 0.90,
+				Source:     "cultural_marker",
+				Reason:     "Russian patronymic ending '-ovich/-evich' (son of) indicates male",
+			}
+		}
+	}
+
+	return e.inferWestern(text, language)
+}
+
 // inferWestern uses Western name patterns and statistical data
 func (e *Engine) inferWestern(text string, language string) *Inference {
 	textLower := strings.ToLower(text)
@@ -370,7 +444,7 @@ func (e *Engine) inferWestern(text string, language string) *Inference {
 		for _, name := range maleNames {
 			if word == name {
 				return &Inference{
-					Value:      "M",
+					Value:      Male,
 					Confidence: 0.85,
 					Source:     "statistical",
 					Reason:     "Common Western male name",
@@ -381,7 +455,7 @@ func (e *Engine) inferWestern(text string, language string) *Inference {
 		for _, name := range femaleNames {
 			if word == name {
 				return &Inference{
-					Value:      "F",
+					Value:      Female,
 					Confidence: 0.85,
 					Source:     "statistical",
 					Reason:     "Common Western female name",
@@ -397,7 +471,7 @@ func (e *Engine) inferWestern(text string, language string) *Inference {
 				// Female name endings
 				if strings.HasSuffix(word, "a") || strings.HasSuffix(word, "ia") || strings.HasSuffix(word, "ina") {
 					return &Inference{
-						Value:      "F",
+						Value:      Female,
 						Confidence: 0.60,
 						Source:     "statistical",
 						Reason:     "Name ending pattern suggests female",
@@ -407,7 +481,7 @@ func (e *Engine) inferWestern(text string, language string) *Inference {
 				// Male name endings
 				if strings.HasSuffix(word, "er") || strings.HasSuffix(word, "on") || strings.HasSuffix(word, "us") {
 					return &Inference{
-						Value:      "M",
+						Value:      Male,
 						Confidence: 0.55,
 						Source:     "statistical",
 						Reason:     "Name ending pattern suggests male",
@@ -417,19 +491,19 @@ func (e *Engine) inferWestern(text string, language string) *Inference {
 		}
 	}
 	
-	return &Inference{Value: "X", Confidence: 0.1, Source: "unknown", Reason: "No Western gender indicators found"}
+	return &Inference{Value: Unknown, Confidence: 0.1, Source: "unknown", Reason: "No Western gender indicators found"}
 }
 
 // inferFromStatisticalPatterns uses statistical analysis (placeholder for more sophisticated methods)
 func (e *Engine) inferFromStatisticalPatterns(text, culture, language string) *Inference {
 	if !e.useStatistical {
-		return &Inference{Value: "X", Confidence: 0.0, Source: "disabled"}
+		return &Inference{Value: Unknown, Confidence: 0.0, Source: "disabled"}
 	}
 	
 	// This would integrate with statistical models trained on name data
 	// For now, return low-confidence unknown
 	return &Inference{
-		Value:      "X",
+		Value:      Unknown,
 		Confidence: 0.2,
 		Source:     "statistical",
 		Reason:     "Statistical analysis inconclusive",
@@ -466,7 +540,7 @@ func GetGenderFromTitle(title string) *Inference {
 	switch titleLower {
 	case "mr", "sir", "lord", "herr", "señor", "monsieur":
 		return &Inference{
-			Value:      "M",
+			Value:      Male,
 			Confidence: 0.95,
 			Source:     "cultural_marker",
 			Reason:     "Male-specific title",
@@ -474,7 +548,7 @@ func GetGenderFromTitle(title string) *Inference {
 		
 	case "mrs", "ms", "miss", "lady", "dame", "frau", "señora", "señorita", "madame", "mademoiselle":
 		return &Inference{
-			Value:      "F",
+			Value:      Female,
 			Confidence: 0.95,
 			Source:     "cultural_marker",
 			Reason:     "Female-specific title",
@@ -482,7 +556,7 @@ func GetGenderFromTitle(title string) *Inference {
 		
 	case "mx":
 		return &Inference{
-			Value:      "X",
+			Value:      NonBinary,
 			Confidence: 0.95,
 			Source:     "cultural_marker",
 			Reason:     "Gender-neutral title",
@@ -491,7 +565,7 @@ func GetGenderFromTitle(title string) *Inference {
 	default:
 		// Dr, Prof, Rev, etc. are gender-neutral
 		return &Inference{
-			Value:      "X",
+			Value:      Unknown,
 			Confidence: 0.1,
 			Source:     "unknown",
 			Reason:     "Gender-neutral or unknown title",
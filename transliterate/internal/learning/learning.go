@@ -0,0 +1,61 @@
+// Package learning derives character mappings from user corrections.
+package learning
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"encore.app/transliterate/internal/transliteration"
+)
+
+// Mapping is a character mapping implied by a correction
+type Mapping struct {
+	Source string // Source character
+	Script string // Script of the source character
+	Target string // Corrected output for the source character
+}
+
+// DiffCorrection aligns a suggested output against the per-character segments of the
+// original output. A mapping is only returned when the correction changes exactly one
+// source character that occurs once in the text and was converted by its own mapping;
+// anything more ambiguous, or read from its neighbours (っ, a digraph, a word), is ignored
+// rather than guessed.
+func DiffCorrection(segments []transliteration.Segment, suggested string) (Mapping, bool) {
+	// Consume matching segments from the front; empty outputs (e.g. a dropped soft sign)
+	// match anywhere, so they stop the scan instead
+	start, pos := 0, 0
+	for start < len(segments) && segments[start].Output != "" && strings.HasPrefix(suggested[pos:], segments[start].Output) {
+		pos += len(segments[start].Output)
+		start++
+	}
+
+	// Consume matching segments from the back, never crossing the front
+	end, limit := len(segments)-1, len(suggested)
+	for end >= start && segments[end].Output != "" && strings.HasSuffix(suggested[pos:limit], segments[end].Output) {
+		limit -= len(segments[end].Output)
+		end--
+	}
+
+	// Exactly one differing source character is required
+	if start != end {
+		return Mapping{}, false
+	}
+
+	changed := segments[start]
+	target := suggested[pos:limit]
+	if target == "" || target == changed.Output {
+		return Mapping{}, false
+	}
+	if changed.Contextual || utf8.RuneCountInString(changed.Source) != 1 {
+		return Mapping{}, false
+	}
+
+	// A repeated character kept its old output elsewhere, so the correction is inconsistent
+	for i, segment := range segments {
+		if i != start && segment.Source == changed.Source {
+			return Mapping{}, false
+		}
+	}
+
+	return Mapping{Source: changed.Source, Script: changed.Script, Target: target}, true
+}
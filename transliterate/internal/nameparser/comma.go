@@ -0,0 +1,80 @@
+package nameparser
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// familyCommas are the commas that separate a family name from the given names, including
+// the full-width comma of Chinese and Japanese text
+const familyCommas = ",，"
+
+// cutFamilyComma splits a name written "Family, Given" (Smith, John Michael; 李, 明) at its
+// comma. Commas left at the end by a removed suffix (Smith, John, Jr.) are ignored; text
+// without exactly one comma between two non-empty parts reports false.
+func cutFamilyComma(text string) (string, string, bool) {
+	text = strings.TrimRight(strings.TrimSpace(text), familyCommas+" ")
+	if strings.Count(text, ",")+strings.Count(text, "，") != 1 {
+		return "", "", false
+	}
+
+	i := strings.IndexAny(text, familyCommas)
+	family := strings.TrimSpace(text[:i])
+	_, size := utf8.DecodeRuneInString(text[i:])
+	given := strings.TrimSpace(text[i+size:])
+	if family == "" || given == "" {
+		return "", "", false
+	}
+	return family, given, true
+}
+
+// familyCommaOrder rewrites a "Family, Given" name in the order its culture writes names, so
+// the culture's parser reads the given names by its own rules (Văn as the middle name of
+// Nguyễn Văn Minh, a Russian patronymic)
+func familyCommaOrder(family, given string, context CulturalContext) string {
+	if context.NameOrder == "family-first" {
+		return family + " " + given
+	}
+	return given + " " + family
+}
+
+// sameFamily reports whether a parsed family name is the family part of a "Family, Given"
+// name, whatever its case and however an article is joined (AL-MASRI for al Masri)
+func sameFamily(parsed, family string) bool {
+	normalize := func(name string) string {
+		return strings.Join(strings.Fields(strings.ReplaceAll(name, "-", " ")), " ")
+	}
+	return strings.EqualFold(normalize(parsed), normalize(family))
+}
+
+// parseFamilyComma parses a "Family, Given" name whose culture's parser would take a
+// different family name from the reordered words, as for a double-barrelled family name
+// without a hyphen (Garcia Lopez, Maria). Every word before the comma is the family name;
+// the given names are read in the culture's order, as for mixed-script names.
+func (p *Parser) parseFamilyComma(family, given string, context CulturalContext) *NameStructure {
+	result := &NameStructure{Family: cases.Upper(context.Casing).String(family)}
+	familyParts := strings.Fields(family)
+	for _, part := range familyParts[:len(familyParts)-1] {
+		if isNameParticle(part) {
+			result.Particles = append(result.Particles, strings.ToLower(part))
+		}
+	}
+
+	var givenNames []string
+	for _, part := range strings.Fields(given) {
+		givenNames = append(givenNames, westernTitleCase(part, context.Casing))
+	}
+	if context.NameOrder == "family-first" {
+		result.First = givenNames[len(givenNames)-1]
+		result.Middle = givenNames[:len(givenNames)-1]
+	} else {
+		result.First = givenNames[0]
+		result.Middle = givenNames[1:]
+	}
+	if len(result.Middle) == 0 {
+		result.Middle = nil
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package nameparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Honorific describes an honorific attached to a name
+type Honorific struct {
+	Native     string  // Honorific as written, e.g. "女士" or "-san"
+	Title      string  // Equivalent title for Titles, empty for politeness-only forms
+	Gender     string  // "M" or "F" when the honorific implies gender, otherwise empty
+	Confidence float64 // Confidence of the gender implication
+	Culture    string  // Naming culture a romanized honorific points to
+
+	// afterKana limits a kanji honorific to names written with kana, since the same character
+	// ends Chinese given names (张丽君); 様 isn't, so it follows kanji names too (田中様)
+	afterKana bool
+}
+
+// nativeHonorifics lists CJK honorific suffixes, longest first within each language
+var nativeHonorifics = []Honorific{
+	// Chinese
+	{Native: "女士", Title: "Ms", Gender: "F", Confidence: 0.9},
+	{Native: "小姐", Title: "Miss", Gender: "F", Confidence: 0.9},
+	{Native: "太太", Title: "Mrs", Gender: "F", Confidence: 0.9},
+	{Native: "夫人", Title: "Mrs", Gender: "F", Confidence: 0.85},
+	// 先生 is occasionally used for eminent women, so it is weaker than 女士
+	{Native: "先生", Title: "Mr", Gender: "M", Confidence: 0.8},
+
+	// Korean
+	{Native: "님", Title: ""},
+	{Native: "씨", Title: ""},
+
+	// Japanese
+	{Native: "さま", Title: ""},
+	{Native: "さん", Title: ""},
+	{Native: "ちゃん", Title: ""},
+	{Native: "くん", Title: "", Gender: "M", Confidence: 0.6},
+	{Native: "様", Title: ""},
+	{Native: "君", Title: "", Gender: "M", Confidence: 0.6, afterKana: true},
+}
+
+// romanizedHonorifics lists Japanese and Korean honorifics written in Latin script, which
+// attach to a name with a hyphen (Tanaka-san, Kim-ssi). They are politeness forms, not titles.
+var romanizedHonorifics = []Honorific{
+	// Japanese
+	{Native: "-sama", Culture: "japanese"},
+	{Native: "-san", Culture: "japanese"},
+	{Native: "-chan", Culture: "japanese"},
+	{Native: "-kun", Gender: "M", Confidence: 0.6, Culture: "japanese"},
+	{Native: "-senpai", Culture: "japanese"},
+	{Native: "-sensei", Culture: "japanese"},
+
+	// Korean
+	{Native: "-ssi", Culture: "korean"},
+	{Native: "-nim", Culture: "korean"},
+}
+
+// ExtractRomanizedHonorifics removes hyphenated honorifics from the words of a romanized
+// name, returning the bare name and the honorifics as written (-san, -SAN)
+func ExtractRomanizedHonorifics(text string) (string, []Honorific) {
+	var found []Honorific
+	words := strings.Fields(text)
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		for _, honorific := range romanizedHonorifics {
+			if !strings.HasSuffix(lower, honorific.Native) || len(lower) == len(honorific.Native) {
+				continue
+			}
+			cut := len(word) - len(honorific.Native)
+			honorific.Native = word[cut:]
+			found = append(found, honorific)
+			words[i] = word[:cut]
+			break
+		}
+	}
+
+	if len(found) == 0 {
+		return text, nil
+	}
+	return strings.Join(words, " "), found
+}
+
+// hasRomanizedHonorific reports whether text has a romanized honorific of the given culture
+func hasRomanizedHonorific(text, culture string) bool {
+	_, honorifics := ExtractRomanizedHonorifics(text)
+	for _, honorific := range honorifics {
+		if honorific.Culture == culture {
+			return true
+		}
+	}
+	return false
+}
+
+// FindHonorific returns the honorific attached to a name: a native-script one, or else the
+// first romanized one (nil when there is none)
+func FindHonorific(text string) *Honorific {
+	if _, honorific := ExtractNativeHonorific(text); honorific != nil {
+		return honorific
+	}
+	if _, honorifics := ExtractRomanizedHonorifics(text); len(honorifics) > 0 {
+		return &honorifics[0]
+	}
+	return nil
+}
+
+// ExtractNativeHonorific splits a trailing native-script honorific from a name,
+// returning the bare name and the honorific (nil when none is present)
+func ExtractNativeHonorific(text string) (string, *Honorific) {
+	trimmed := strings.TrimSpace(text)
+
+	for _, honorific := range nativeHonorifics {
+		if !strings.HasSuffix(trimmed, honorific.Native) {
+			continue
+		}
+		name := strings.TrimSpace(strings.TrimSuffix(trimmed, honorific.Native))
+		if name == "" || honorific.afterKana && !containsKana(name) {
+			continue
+		}
+
+		found := honorific
+		// In Japanese 先生 means teacher or doctor (sensei) and carries no gender
+		if found.Native == "先生" && containsKana(name) {
+			found = Honorific{Native: "先生", Title: "Sensei"}
+		}
+		return name, &found
+	}
+
+	return text, nil
+}
+
+// containsKana reports whether text contains hiragana or katakana
+func containsKana(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
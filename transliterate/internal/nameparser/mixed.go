@@ -0,0 +1,125 @@
+package nameparser
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+)
+
+// Word scripts distinguished when splitting a name written in more than one script
+const (
+	wordScriptHan   = "han"
+	wordScriptLatin = "latin"
+	wordScriptOther = "other"
+)
+
+// wordScript classifies a word of a name by its letters: han or latin when every letter is of
+// that script, other for any other mix, and "" for a word without letters
+func wordScript(word string) string {
+	script := ""
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letterScript := wordScriptOther
+		switch {
+		case unicode.Is(unicode.Han, r):
+			letterScript = wordScriptHan
+		case unicode.Is(unicode.Latin, r):
+			letterScript = wordScriptLatin
+		}
+		if script != "" && script != letterScript {
+			return wordScriptOther
+		}
+		script = letterScript
+	}
+	return script
+}
+
+// isChineseSurname reports whether a word of Han characters is a family name on its own: a
+// single character, or a compound surname (欧阳)
+func isChineseSurname(word string) bool {
+	return len([]rune(word)) == 1 || chineseCompoundSurnames[word]
+}
+
+// mixedScriptFamily finds the family name of a name that writes a Chinese surname in Han
+// characters beside Latin given names (李 John, John 李, 王 Mary Anne), returning the position
+// of the surname among the words. Each word keeps the rules of its own script, where parsing
+// the whole name by one culture would take a Latin word for the family name. Names in a single
+// script, mixing scripts another way, or whose romanized words don't match the original ones
+// report false.
+func mixedScriptFamily(originalWords, romanizedWords []string) (int, bool) {
+	if len(originalWords) < 2 || len(originalWords) != len(romanizedWords) {
+		return 0, false
+	}
+
+	family := -1
+	for i, word := range originalWords {
+		switch wordScript(word) {
+		case wordScriptLatin:
+		case wordScriptHan:
+			if family >= 0 || !isChineseSurname(word) {
+				return 0, false
+			}
+			family = i
+		default:
+			return 0, false
+		}
+	}
+	return family, family >= 0
+}
+
+// mixedScriptContext is the cultural context of a mixed-script name, in the order it is
+// written: family-first when the surname leads
+func mixedScriptContext(family int) CulturalContext {
+	context := CulturalContext{
+		Culture:       "mixed",
+		NameOrder:     "given-first",
+		CaseSensitive: true,
+		MinParts:      2,
+		MaxParts:      5,
+	}
+	if family == 0 {
+		context.NameOrder = "family-first"
+	}
+	return context
+}
+
+// parseMixedScript parses a mixed-script name whose family name is the romanized word at
+// position family. The Latin words are given names, read as the written order implies: the
+// first is the given name in given-first order (John Paul LI), the last in family-first order
+// (LI Paul John), as for names written entirely in either order.
+func (p *Parser) parseMixedScript(text string, family int, context CulturalContext) *NameStructure {
+	parts := strings.Fields(text)
+	var result NameStructure
+	result.Family = cases.Upper(context.Casing).String(parts[family])
+
+	var given []string
+	for i, part := range parts {
+		if i != family {
+			given = append(given, westernTitleCase(part, context.Casing))
+		}
+	}
+
+	if context.NameOrder == "family-first" {
+		result.First = given[len(given)-1]
+		result.Middle = given[:len(given)-1]
+	} else {
+		result.First = given[0]
+		result.Middle = given[1:]
+	}
+	if len(result.Middle) == 0 {
+		result.Middle = nil
+	}
+	return &result
+}
+
+// bareOriginal strips the honorifics, titles and suffixes found in the romanized name from the
+// original text, leaving the words of the name itself
+func (p *Parser) bareOriginal(originalText string, titles, suffixes []string) string {
+	original, _ := ExtractNativeHonorific(originalText)
+	original, _ = ExtractRomanizedHonorifics(original)
+	original = p.removeTitles(original, titles)
+	return p.removeSuffixes(original, suffixes)
+}
@@ -3,6 +3,10 @@ package nameparser
 
 import (
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // NameStructure represents parsed name components with cultural awareness
@@ -10,12 +14,18 @@ type NameStructure struct {
 	Family       string   `json:"family"`                // Family/surname (UPPERCASE for display)
 	First        string   `json:"first"`                 // Given/first name (Title Case)
 	Middle       []string `json:"middle,omitempty"`      // Middle names/patronymics
+	Patronymic   string   `json:"patronymic,omitempty"`  // Russian-style patronymic (Ivanovich, Sergeevna)
 	Titles       []string `json:"titles,omitempty"`      // Extracted titles (Dr, Prof, etc)
 	Suffixes     []string `json:"suffixes,omitempty"`    // Jr., Sr., III, etc.
 	Particles    []string `json:"particles,omitempty"`   // de, van, von, del, etc.
 	FullASCII    string   `json:"full_ascii"`            // Complete formatted ASCII name
 	OriginalForm string   `json:"original_form"`         // Original input for reference
 	Order        string   `json:"order"`                 // "western" or "eastern"
+
+	// The same parts in the original script (李, 明), for names not written in Latin script
+	FamilyOriginal string   `json:"family_original,omitempty"`
+	FirstOriginal  string   `json:"first_original,omitempty"`
+	MiddleOriginal []string `json:"middle_original,omitempty"`
 }
 
 // CulturalContext provides information about naming conventions
@@ -27,6 +37,9 @@ type CulturalContext struct {
 	ParticlePrefix    bool     `json:"particle_prefix"`     // Whether particles come before surnames
 	CaseSensitive     bool     `json:"case_sensitive"`      // Whether proper case is culturally important
 	PreservedElements []string `json:"preserved_elements"`  // Elements that should not be altered
+	MinParts          int      `json:"min_parts"`           // Fewest name parts a full name usually has, titles aside
+	MaxParts          int      `json:"max_parts"`           // Most name parts a full name usually has
+	Casing            language.Tag `json:"-"`                // Language whose case rules apply (Turkish İ/ı), or Und
 }
 
 // Parser handles name parsing with cultural awareness
@@ -52,9 +65,17 @@ func (p *Parser) ParseName(originalText, transliteratedText, culture, language s
 		}
 	}
 
+	// Honorifics such as -san and -ssi are dropped rather than read as titles or names
+	cleanText, _ := ExtractRomanizedHonorifics(transliteratedText)
+
 	// Extract titles first
-	titles := p.extractTitles(transliteratedText)
-	cleanText := p.removeTitles(transliteratedText, titles)
+	titles := p.extractTitles(cleanText)
+	cleanText = p.removeTitles(cleanText, titles)
+
+	// Native-script honorifics (女士, 先生) only survive in the original text
+	if honorific := FindHonorific(originalText); honorific != nil && honorific.Title != "" && !containsTitle(titles, honorific.Title) {
+		titles = append(titles, honorific.Title)
+	}
 
 	// Extract suffixes
 	suffixes := p.extractSuffixes(cleanText)
@@ -62,12 +83,34 @@ func (p *Parser) ParseName(originalText, transliteratedText, culture, language s
 
 	// Determine cultural context
 	context := p.getCulturalContext(culture, language, originalText)
+	context.Casing = casingLanguage(language)
+
+	// Records stored as "Family, Given" (Smith, John; 李, 明) are read in the culture's own
+	// order, and the original text with them
+	nameOriginal := originalText
+	family, given, familyComma := cutFamilyComma(cleanText)
+	if familyComma {
+		cleanText = familyCommaOrder(family, given, context)
+		if originalFamily, originalGiven, ok := cutFamilyComma(originalText); ok {
+			nameOriginal = familyCommaOrder(originalFamily, originalGiven, context)
+		}
+	}
+
+	// A Han-character surname beside Latin given names is read word by word, each word by
+	// the rules of its own script
+	mixedFamily, mixed := mixedScriptFamily(strings.Fields(p.bareOriginal(nameOriginal, titles, suffixes)), strings.Fields(cleanText))
+	if mixed {
+		context = mixedScriptContext(mixedFamily)
+		context.Casing = casingLanguage(language)
+	}
 
 	// Parse according to cultural conventions
 	var result *NameStructure
 	switch context.Culture {
+	case "mixed":
+		result = p.parseMixedScript(cleanText, mixedFamily, context)
 	case "vietnamese":
-		result = p.parseVietnamese(originalText, cleanText, context)
+		result = p.parseVietnamese(nameOriginal, cleanText, context)
 	case "chinese":
 		result = p.parseChinese(cleanText, context)
 	case "japanese":
@@ -82,16 +125,24 @@ func (p *Parser) ParseName(originalText, transliteratedText, culture, language s
 		result = p.parseIndonesian(cleanText, context)
 	case "thai":
 		result = p.parseThai(cleanText, context)
+	case "russian":
+		result = p.parseRussian(cleanText, context)
 	default:
 		result = p.parseWestern(cleanText, context)
 	}
 
+	// Every word before the comma is the family name, however the culture would split them
+	if familyComma && !sameFamily(result.Family, family) {
+		result = p.parseFamilyComma(family, given, context)
+	}
+
 	// Add metadata
 	result.Titles = titles
 	result.Suffixes = suffixes
 	result.OriginalForm = originalText
 	result.Order = context.NameOrder
 	result.FullASCII = p.formatFullName(result, context)
+	p.alignOriginal(result, nameOriginal, cleanText, context)
 
 	return result
 }
@@ -127,6 +178,16 @@ func (p *Parser) extractTitles(text string) []string {
 	return titles
 }
 
+// containsTitle reports whether titles already includes title
+func containsTitle(titles []string, title string) bool {
+	for _, existing := range titles {
+		if existing == title {
+			return true
+		}
+	}
+	return false
+}
+
 // extractSuffixes identifies generational and other suffixes
 func (p *Parser) extractSuffixes(text string) []string {
 	suffixMapping := map[string]string{
@@ -211,6 +272,8 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			HasGenderMarkers: true,
 			ParticlePrefix:   false,
 			CaseSensitive:    false,
+			MinParts:         2,
+			MaxParts:         5,
 		}
 		
 	case culture == "chinese" || language == "zh" || language == "zh-CN" || language == "zh-TW" || p.looksChinese(originalText):
@@ -218,13 +281,27 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			Culture:       "chinese",
 			NameOrder:     "family-first",
 			CaseSensitive: true,
+			MinParts:      2,
+			MaxParts:      4,
 		}
 		
+	case strings.ContainsRune(originalText, '・'):
+		// Foreign names in katakana keep their own given-first order (ジョン・スミス)
+		return CulturalContext{
+			Culture:       "western",
+			NameOrder:     "given-first",
+			CaseSensitive: true,
+			MinParts:      2,
+			MaxParts:      5,
+		}
+
 	case culture == "japanese" || language == "ja" || p.looksJapanese(originalText):
 		return CulturalContext{
 			Culture:       "japanese",
 			NameOrder:     "family-first",
 			CaseSensitive: true,
+			MinParts:      1,
+			MaxParts:      2,
 		}
 		
 	case culture == "korean" || language == "ko" || p.looksKorean(originalText):
@@ -232,6 +309,8 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			Culture:       "korean",
 			NameOrder:     "family-first",
 			CaseSensitive: true,
+			MinParts:      1,
+			MaxParts:      3,
 		}
 		
 	case culture == "arabic" || language == "ar" || p.looksArabic(originalText):
@@ -240,6 +319,18 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			NameOrder:      "given-first",
 			HasPatronymics: true,
 			ParticlePrefix: false,
+			MinParts:       2,
+			MaxParts:       6,
+		}
+		
+	case culture == "russian" || language == "ru" || p.looksCyrillic(originalText):
+		return CulturalContext{
+			Culture:        "russian",
+			NameOrder:      "given-first",
+			HasPatronymics: true,
+			CaseSensitive:  true,
+			MinParts:       2,
+			MaxParts:       3,
 		}
 		
 	case strings.Contains(language, "in") || culture == "indonesian" || culture == "malaysian":
@@ -247,6 +338,8 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			Culture:        "indonesian",
 			NameOrder:      "given-first",
 			HasPatronymics: true,
+			MinParts:       1,
+			MaxParts:       4,
 		}
 		
 	default:
@@ -255,6 +348,8 @@ func (p *Parser) getCulturalContext(culture, language, originalText string) Cult
 			NameOrder:      "given-first",
 			ParticlePrefix: true,
 			CaseSensitive:  true,
+			MinParts:       2,
+			MaxParts:       5,
 		}
 	}
 }
@@ -269,18 +364,21 @@ func (p *Parser) parseVietnamese(original, text string, context CulturalContext)
 	var result NameStructure
 	
 	if len(parts) >= 2 {
-		// Vietnamese: Family name first, then middle names, then given name
+		// Vietnamese: Family name first, then an optional gender marker, then the given name
 		// For mixed language text, identify the Vietnamese part
 		vietnameseParts := p.findVietnameseParts(parts, original)
 		if len(vietnameseParts) >= 2 {
 			result.Family = strings.ToUpper(vietnameseParts[0])
-			result.First = p.toTitleCase(vietnameseParts[len(vietnameseParts)-1])
-			
-			// Handle middle names
-			for i := 1; i < len(vietnameseParts)-1; i++ {
-				part := vietnameseParts[i]
-				result.Middle = append(result.Middle, p.toTitleCase(part))
+			given := vietnameseParts[1:]
+
+			// The gender marker (Văn, Thị) is kept as the middle name
+			if len(given) > 1 && isVietnameseGenderMarker(given[0]) {
+				result.Middle = append(result.Middle, p.toTitleCase(given[0]))
+				given = given[1:]
 			}
+
+			// The rest is one given name, compound when it has several syllables (Ngọc Lan)
+			result.First = p.toTitleCase(strings.Join(given, " "))
 		} else if len(vietnameseParts) == 1 {
 			// Only one Vietnamese part, probably just a first name
 			result.First = p.toTitleCase(vietnameseParts[0])
@@ -296,18 +394,27 @@ func (p *Parser) parseVietnamese(original, text string, context CulturalContext)
 	return &result
 }
 
-// findVietnameseParts identifies Vietnamese name components from mixed-language text
+// findVietnameseParts identifies Vietnamese name components from mixed-language text: the
+// name starts at the first recognisable part and, family name first, runs to the end, so
+// given names missing from the known lists (Anh, Ngọc) are kept
 func (p *Parser) findVietnameseParts(parts []string, original string) []string {
-	var vietnameseParts []string
-	
-	for _, part := range parts {
+	for i, part := range parts {
 		// Check if this part contains Vietnamese characters by comparing with original
 		if p.containsVietnameseCharacters(part) || p.isVietnameseNamePart(part, original) {
-			vietnameseParts = append(vietnameseParts, part)
+			return parts[i:]
 		}
 	}
-	
-	return vietnameseParts
+
+	return nil
+}
+
+// isVietnameseGenderMarker reports whether part is Văn or Thị, with or without diacritics
+func isVietnameseGenderMarker(part string) bool {
+	switch strings.ToLower(part) {
+	case "văn", "van", "thị", "thi":
+		return true
+	}
+	return false
 }
 
 // containsVietnameseCharacters checks if a word contains Vietnamese diacritics
@@ -402,11 +509,52 @@ func (p *Parser) looksLikeConcatenatedChinese(name string) bool {
 	return false
 }
 
+// chineseCompoundSurnames lists common two-character Chinese surnames
+var chineseCompoundSurnames = map[string]bool{
+	"欧阳": true, "歐陽": true, "司马": true, "司馬": true, "诸葛": true, "諸葛": true,
+	"上官": true, "东方": true, "東方": true, "皇甫": true, "令狐": true, "慕容": true,
+	"尉迟": true, "尉遲": true, "公孙": true, "公孫": true, "夏侯": true, "轩辕": true,
+	"軒轅": true, "端木": true, "长孙": true, "長孫": true, "宇文": true, "司徒": true,
+	"西门": true, "西門": true, "南宫": true, "南宮": true,
+}
+
+// ChineseSurnameLength returns how many leading characters of a Han-character name form
+// the family name, or 0 when the text doesn't look like a Chinese name
+func ChineseSurnameLength(text string) int {
+	parts := strings.Fields(text)
+	for _, part := range parts {
+		for _, r := range part {
+			if !unicode.Is(unicode.Han, r) {
+				return 0
+			}
+		}
+	}
+
+	switch len(parts) {
+	case 1:
+		// Concatenated names are two to four characters
+		name := []rune(parts[0])
+		if len(name) < 2 || len(name) > 4 {
+			return 0
+		}
+		if len(name) >= 3 && chineseCompoundSurnames[string(name[:2])] {
+			return 2
+		}
+		return 1
+	case 2:
+		// Family name written separately from the given name
+		family := []rune(parts[0])
+		if len(family) > 2 {
+			return 0
+		}
+		return len(family)
+	default:
+		return 0
+	}
+}
+
 // parseJapanese handles Japanese naming conventions
 func (p *Parser) parseJapanese(text string, context CulturalContext) *NameStructure {
-	// Remove honorifics like -san, -kun, -chan
-	text = p.removeJapaneseHonorifics(text)
-	
 	parts := strings.Fields(text)
 	if len(parts) == 0 {
 		return &NameStructure{}
@@ -430,32 +578,62 @@ func (p *Parser) parseJapanese(text string, context CulturalContext) *NameStruct
 	return &result
 }
 
-// parseArabic handles Arabic naming conventions
+// parseArabic handles Arabic naming conventions. The definite article (al, el) always stays
+// with the name it precedes, written or not with a hyphen (al Masri, Al-Masri: AL-MASRI).
 func (p *Parser) parseArabic(text string, context CulturalContext) *NameStructure {
-	parts := strings.Fields(text)
+	var result NameStructure
+	parts, articles := joinArabicArticles(strings.Fields(text))
 	if len(parts) == 0 {
-		return &NameStructure{}
+		return &result
 	}
-
-	var result NameStructure
+	result.Particles = articles
 
 	if len(parts) >= 2 {
 		// Arabic: Given name first, patronymics/family name last
-		result.First = p.toTitleCase(parts[0])
+		result.First = p.arabicTitleCase(parts[0])
 		result.Family = strings.ToUpper(parts[len(parts)-1])
 
 		// Handle patronymics (ibn, bin, bint, etc.)
 		for i := 1; i < len(parts)-1; i++ {
 			part := parts[i]
-			result.Middle = append(result.Middle, p.toTitleCase(part))
+			result.Middle = append(result.Middle, p.arabicTitleCase(part))
 		}
 	} else {
-		result.First = p.toTitleCase(parts[0])
+		result.First = p.arabicTitleCase(parts[0])
 	}
 
 	return &result
 }
 
+// arabicArticles are the romanized forms of the Arabic definite article
+var arabicArticles = map[string]bool{"al": true, "el": true}
+
+// joinArabicArticles hyphenates each standalone article onto the following name (al Masri
+// al-Masri) and returns the articles found, hyphenated or not, in lowercase
+func joinArabicArticles(parts []string) ([]string, []string) {
+	var joined, articles []string
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if arabicArticles[strings.ToLower(part)] && i+1 < len(parts) {
+			part += "-" + parts[i+1]
+			i++
+		}
+		if article, _, found := strings.Cut(part, "-"); found && arabicArticles[strings.ToLower(article)] {
+			articles = append(articles, strings.ToLower(article))
+		}
+		joined = append(joined, part)
+	}
+	return joined, articles
+}
+
+// arabicTitleCase capitalizes a name, keeping a leading article lowercase (al-Amin)
+func (p *Parser) arabicTitleCase(part string) string {
+	if article, name, found := strings.Cut(part, "-"); found && arabicArticles[strings.ToLower(article)] {
+		return strings.ToLower(article) + "-" + p.toTitleCase(name)
+	}
+	return p.toTitleCase(part)
+}
+
 // parseKorean handles Korean naming conventions
 func (p *Parser) parseKorean(text string, context CulturalContext) *NameStructure {
 	parts := strings.Fields(text)
@@ -561,7 +739,51 @@ func (p *Parser) parseThai(text string, context CulturalContext) *NameStructure
 	return &result
 }
 
-// parseWestern handles Western naming conventions
+// parseRussian handles Russian naming conventions: Given + Patronymic + Family (Ivan
+// Ivanovich Petrov), or the official Family + Given + Patronymic (Petrov Ivan Ivanovich).
+// Names without a patronymic parse as Western names.
+func (p *Parser) parseRussian(text string, context CulturalContext) *NameStructure {
+	parts := strings.Fields(text)
+	if len(parts) == 3 && IsPatronymic(parts[2]) && !IsPatronymic(parts[1]) {
+		return &NameStructure{
+			Family:     strings.ToUpper(parts[0]),
+			First:      westernTitleCase(parts[1], context.Casing),
+			Patronymic: westernTitleCase(parts[2], context.Casing),
+		}
+	}
+
+	for i := 1; i < len(parts)-1; i++ {
+		if !IsPatronymic(parts[i]) {
+			continue
+		}
+		result := p.parseWestern(strings.Join(append(parts[:i:i], parts[i+1:]...), " "), context)
+		result.Patronymic = westernTitleCase(parts[i], context.Casing)
+		return result
+	}
+
+	return p.parseWestern(text, context)
+}
+
+// patronymicSuffixes are the romanized endings of Russian patronymics: -ich for sons
+// (Ivanovich, Ilyich), -ovna, -evna and -ichna for daughters (Ivanovna, Sergeevna, Ilyinichna)
+var patronymicSuffixes = []string{"ich", "ovna", "evna", "ichna"}
+
+// IsPatronymic reports whether a romanized name is a Russian patronymic (Ivanovich, Sergeevna)
+func IsPatronymic(part string) bool {
+	lower := strings.ToLower(part)
+	for _, suffix := range patronymicSuffixes {
+		// The stem is a given name, so it is at least a few letters long
+		if strings.HasSuffix(lower, suffix) && len(lower) >= len(suffix)+3 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWestern handles Western naming conventions: the last word is the family name, joined
+// by any particles directly before it (de la Hoya, van Beethoven); particles earlier in the
+// name belong to the given names (María del Carmen). Hyphenated and apostrophe names
+// (Day-Lewis, O'Sullivan) stay single words.
 func (p *Parser) parseWestern(text string, context CulturalContext) *NameStructure {
 	parts := strings.Fields(text)
 	if len(parts) == 0 {
@@ -569,145 +791,239 @@ func (p *Parser) parseWestern(text string, context CulturalContext) *NameStructu
 	}
 
 	var result NameStructure
+	if len(parts) == 1 {
+		result.First = westernTitleCase(parts[0], context.Casing)
+		return &result
+	}
 
-	// For Spanish names, don't separate particles - include them in middle names
-	if context.Culture == "spanish" || strings.Contains(strings.ToLower(text), "del") || strings.Contains(strings.ToLower(text), "de ") {
-		// Spanish naming: treat particles as part of middle names
-		if len(parts) == 1 {
-			result.First = p.toTitleCase(parts[0])
-		} else if len(parts) == 2 {
-			result.First = p.toTitleCase(parts[0])
-			result.Family = strings.ToUpper(parts[1])
-		} else {
-			// First + middle names (including particles) + last
-			result.First = p.toTitleCase(parts[0])
-			result.Family = strings.ToUpper(parts[len(parts)-1])
-			
-			for i := 1; i < len(parts)-1; i++ {
-				part := parts[i]
-				// Keep Spanish particles lowercase
-				if strings.ToLower(part) == "del" || strings.ToLower(part) == "de" || strings.ToLower(part) == "la" || strings.ToLower(part) == "las" {
-					result.Middle = append(result.Middle, strings.ToLower(part))
-				} else {
-					result.Middle = append(result.Middle, p.toTitleCase(part))
-				}
-			}
-		}
-	} else {
-		// Non-Spanish Western names: extract particles separately
-		particles, cleanParts := p.extractParticles(parts)
-		result.Particles = particles
-
-		if len(cleanParts) == 1 {
-			result.First = p.toTitleCase(cleanParts[0])
-		} else if len(cleanParts) == 2 {
-			result.First = p.toTitleCase(cleanParts[0])
-			result.Family = strings.ToUpper(cleanParts[1])
+	// Walk back from the family name over its particles, never taking the first word
+	familyStart := len(parts) - 1
+	for familyStart > 1 && isNameParticle(parts[familyStart-1]) {
+		familyStart--
+	}
+
+	// A name ending in a particle (a truncated "Ahmed bin", "de la") has no family name left
+	// to attach it to; every word is a given name
+	if isNameParticle(parts[len(parts)-1]) {
+		familyStart = len(parts)
+	}
+
+	result.First = westernTitleCase(parts[0], context.Casing)
+	for _, part := range parts[1:familyStart] {
+		if isNameParticle(part) {
+			// Particles inside a given name stay lowercase (María del Carmen)
+			result.Middle = append(result.Middle, strings.ToLower(part))
+			result.Particles = append(result.Particles, strings.ToLower(part))
 		} else {
-			// First + middle names + last
-			result.First = p.toTitleCase(cleanParts[0])
-			result.Family = strings.ToUpper(cleanParts[len(cleanParts)-1])
-			for i := 1; i < len(cleanParts)-1; i++ {
-				result.Middle = append(result.Middle, p.toTitleCase(cleanParts[i]))
-			}
+			result.Middle = append(result.Middle, westernTitleCase(part, context.Casing))
 		}
 	}
 
+	if familyStart == len(parts) {
+		return &result
+	}
+	for _, part := range parts[familyStart : len(parts)-1] {
+		result.Particles = append(result.Particles, strings.ToLower(part))
+	}
+	result.Family = cases.Upper(context.Casing).String(strings.Join(parts[familyStart:], " "))
+
 	return &result
 }
 
-// extractParticles identifies and extracts nobiliary particles
-func (p *Parser) extractParticles(parts []string) ([]string, []string) {
-	particleSet := map[string]bool{
-		"de": true, "del": true, "della": true, "di": true, "da": true,
-		"van": true, "von": true, "der": true, "den": true, "ter": true,
-		"le": true, "la": true, "du": true, "des": true,
-		"bin": true, "binti": true, "ibn": true, "bint": true,
-		"al": true, "el": true,
+// nameParticles are the nobiliary and patronymic particles that attach to a following name
+var nameParticles = map[string]bool{
+	"de": true, "del": true, "della": true, "di": true, "da": true,
+	"van": true, "von": true, "der": true, "den": true, "ter": true,
+	"le": true, "la": true, "las": true, "los": true, "du": true, "des": true,
+	"bin": true, "binti": true, "ibn": true, "bint": true,
+	"al": true, "el": true,
+}
+
+// isNameParticle reports whether part is a lowercase-style particle such as de, van or von
+func isNameParticle(part string) bool {
+	return nameParticles[strings.ToLower(part)]
+}
+
+// westernTitleCase capitalizes a Western name word (Jean-Luc, O'Sullivan, D'Angelo, McDonald)
+// by the case rules of tag. Words already in mixed case (MacArthur, DiCaprio) are kept as written.
+func westernTitleCase(word string, tag language.Tag) string {
+	if word != strings.ToLower(word) && word != strings.ToUpper(word) {
+		return word
 	}
+	return nameTitleCase(word, tag)
+}
 
-	var particles []string
-	var cleanParts []string
+// macSurnames are the Mac names written with a capital after the prefix; Mac is otherwise
+// left alone, since many names only start with it (Mackenzie, Macey)
+var macSurnames = map[string]bool{
+	"macarthur": true, "macdonald": true, "macdougall": true, "macgregor": true,
+	"macintyre": true, "maclean": true, "macleod": true, "macmillan": true,
+	"macneil": true, "macpherson": true, "macquarie": true,
+}
 
-	for i, part := range parts {
-		lower := strings.ToLower(part)
-		if particleSet[lower] && i > 0 && i < len(parts)-1 {
-			// Keep particles with family name
-			particles = append(particles, strings.ToLower(part))
-		} else {
-			cleanParts = append(cleanParts, part)
+// nameTitleCase title-cases a single name word: each hyphenated part is capitalized
+// (Jean-Luc), as is the name after an O', D' or L' prefix (O'Brien) but not after other
+// apostrophes (Ma'mun), and after Mc or a known Mac (McDonald, MacLeod). tag selects
+// language-specific rules, such as Turkish IŞIK to Işık.
+func nameTitleCase(word string, tag language.Tag) string {
+	runes := []rune(cases.Title(tag).String(word))
+
+	switch {
+	case len(runes) > 2 && isApostrophe(runes[1]) && strings.ContainsRune("ODL", runes[0]):
+		runes[2] = unicode.ToUpper(runes[2])
+	case len(runes) > 2 && runes[0] == 'M' && runes[1] == 'c':
+		runes[2] = unicode.ToUpper(runes[2])
+	case macSurnames[strings.ToLower(word)]:
+		runes[3] = unicode.ToUpper(runes[3])
+	}
+
+	return string(runes)
+}
+
+// RespectInputCase restores the case of name words typed with internal capitals (MacArthur,
+// McDONALD, DiCaprio), which parsing would otherwise normalize, even in the uppercase family
+// name. Words typed in a single case, or merely capitalized, carry no signal and are left as
+// parsed. FullASCII is re-rendered in the name's own order.
+func RespectInputCase(name *NameStructure, text string) {
+	written := make(map[string]string)
+	for _, word := range strings.Fields(text) {
+		if hasInternalCapital(word) {
+			written[strings.ToLower(word)] = word
 		}
 	}
+	if name == nil || len(written) == 0 {
+		return
+	}
 
-	// If we found particles, add them back to the family name
-	if len(particles) > 0 && len(cleanParts) > 1 {
-		familyIndex := len(cleanParts) - 1
-		familyParts := append(particles, cleanParts[familyIndex])
-		cleanParts[familyIndex] = strings.Join(familyParts, " ")
+	restore := func(part string) string {
+		words := strings.Fields(part)
+		for i, word := range words {
+			if original, ok := written[strings.ToLower(word)]; ok {
+				words[i] = original
+			}
+		}
+		return strings.Join(words, " ")
+	}
+	name.First = restore(name.First)
+	for i, middle := range name.Middle {
+		name.Middle[i] = restore(middle)
 	}
+	name.Patronymic = restore(name.Patronymic)
+	name.Family = restore(name.Family)
+	name.FullASCII = FormatName(name, name.Order)
+}
 
-	return particles, cleanParts
+// hasInternalCapital reports whether a word mixes case with a capital after its first letter
+func hasInternalCapital(word string) bool {
+	if word == strings.ToUpper(word) || word == strings.ToLower(word) {
+		return false
+	}
+	for i, r := range []rune(word) {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
 }
 
-// removeJapaneseHonorifics removes Japanese honorific suffixes
-func (p *Parser) removeJapaneseHonorifics(text string) string {
-	honorifics := []string{"-san", "-kun", "-chan", "-sama", "-sensei", "-senpai"}
-	
-	for _, honorific := range honorifics {
-		text = strings.ReplaceAll(text, honorific, "")
+// casingLanguage returns the language whose case rules apply to names in the given language
+// or locale: Turkish and Azerbaijani, where i/İ and ı/I are separate letters, or else Und
+func casingLanguage(locale string) language.Tag {
+	base, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	switch strings.ToLower(base) {
+	case "tr":
+		return language.Turkish
+	case "az":
+		return language.Azerbaijani
+	default:
+		return language.Und
 	}
-	
-	return strings.TrimSpace(text)
 }
 
-// formatFullName creates the complete formatted ASCII name
+// isApostrophe reports whether r is an apostrophe as typed or typeset in names
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// Name formats for FullASCII
+const (
+	FormatGivenFirst  = "given-first"  // Xiaoming LI
+	FormatFamilyFirst = "family-first" // LI Xiaoming
+	FormatSortable    = "sortable"     // LI, Xiaoming (no titles, for sorting and indexing)
+)
+
+// NameFormats lists the accepted name formats
+var NameFormats = map[string]bool{
+	FormatGivenFirst: true, FormatFamilyFirst: true, FormatSortable: true,
+}
+
+// formatFullName creates a properly formatted full name in the culture's own order
 func (p *Parser) formatFullName(name *NameStructure, context CulturalContext) string {
+	return FormatName(name, context.NameOrder)
+}
+
+// FormatName renders a parsed name in the given format, regardless of the order its culture uses
+func FormatName(name *NameStructure, format string) string {
 	var parts []string
 
-	// Add titles
-	for _, title := range name.Titles {
-		parts = append(parts, title)
+	// Titles would disturb sorting
+	if format != FormatSortable {
+		parts = append(parts, name.Titles...)
+	}
+
+	var middles []string
+	for _, middle := range name.Middle {
+		if middle != "" {
+			middles = append(middles, middle)
+		}
+	}
+	var given []string
+	if name.First != "" {
+		given = append(given, name.First)
+	}
+	given = append(given, middles...)
+	if name.Patronymic != "" {
+		given = append(given, name.Patronymic)
 	}
 
-	// Add name components based on cultural order
-	if context.NameOrder == "family-first" {
+	switch {
+	case format == FormatSortable && name.Family != "" && len(given) > 0:
+		parts = append(parts, name.Family+",")
+		parts = append(parts, given...)
+	case format == FormatFamilyFirst || format == FormatSortable:
+		// Family-first names keep middle names before the given name (NGUYEN Van Minh)
 		if name.Family != "" {
 			parts = append(parts, name.Family)
 		}
+		parts = append(parts, middles...)
 		if name.First != "" {
 			parts = append(parts, name.First)
 		}
-		for _, middle := range name.Middle {
-			if middle != "" {
-				parts = append(parts, middle)
-			}
+		// A patronymic follows the given name in either order (PETROV Ivan Ivanovich)
+		if name.Patronymic != "" {
+			parts = append(parts, name.Patronymic)
 		}
-	} else {
+	default:
 		// Given-first order
-		if name.First != "" {
-			parts = append(parts, name.First)
-		}
-		for _, middle := range name.Middle {
-			if middle != "" {
-				parts = append(parts, middle)
-			}
-		}
+		parts = append(parts, given...)
 		if name.Family != "" {
 			parts = append(parts, name.Family)
 		}
 	}
 
 	// Add suffixes
-	for _, suffix := range name.Suffixes {
-		parts = append(parts, suffix)
-	}
+	parts = append(parts, name.Suffixes...)
 
 	return strings.Join(parts, " ")
 }
 
-// toTitleCase converts text to title case
+// toTitleCase converts text to title case word by word (see nameTitleCase)
 func (p *Parser) toTitleCase(text string) string {
-	return strings.Title(strings.ToLower(text))
+	words := strings.Split(text, " ")
+	for i, word := range words {
+		words[i] = nameTitleCase(word, language.Und)
+	}
+	return strings.Join(words, " ")
 }
 
 // Helper methods for cultural detection
@@ -742,26 +1058,17 @@ func (p *Parser) looksJapanese(text string) bool {
 	}
 	
 	// Check for Japanese honorifics in romanized text
-	japaneseHonorifics := []string{"-san", "-kun", "-chan", "-sama", "-sensei", "-senpai"}
-	textLower := strings.ToLower(text)
-	for _, honorific := range japaneseHonorifics {
-		if strings.Contains(textLower, honorific) {
-			return true
-		}
+	if hasRomanizedHonorific(text, "japanese") {
+		return true
 	}
+	textLower := strings.ToLower(text)
 	
 	// Check for common Japanese family names in romanized text
 	japaneseFamilyNames := []string{"tanaka", "sato", "suzuki", "yamamoto", "watanabe", "ito", "saito", "kato", "kobayashi", "oka"}
 	words := strings.Fields(textLower)
 	for _, word := range words {
-		// Remove honorifics from comparison
-		cleanWord := word
-		for _, honorific := range japaneseHonorifics {
-			cleanWord = strings.ReplaceAll(cleanWord, honorific, "")
-		}
-		
 		for _, familyName := range japaneseFamilyNames {
-			if cleanWord == familyName {
+			if word == familyName {
 				return true
 			}
 		}
@@ -776,6 +1083,16 @@ func (p *Parser) looksKorean(text string) bool {
 			return true
 		}
 	}
+	// Check for Korean honorifics in romanized text
+	return hasRomanizedHonorific(text, "korean")
+}
+
+func (p *Parser) looksCyrillic(text string) bool {
+	for _, r := range text {
+		if r >= 0x0400 && r <= 0x04FF {
+			return true
+		}
+	}
 	return false
 }
 
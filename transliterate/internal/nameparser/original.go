@@ -0,0 +1,96 @@
+package nameparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// alignOriginal fills the original-script forms of a parsed name for text not written in
+// Latin script. Chinese names in Han characters are split at the family name; other names
+// are aligned word by word with the romanized words they were parsed from, and left
+// without original forms when the word counts differ.
+func (p *Parser) alignOriginal(result *NameStructure, originalText, romanizedText string, context CulturalContext) {
+	original, _ := ExtractNativeHonorific(originalText)
+	original, _ = ExtractRomanizedHonorifics(original)
+	if !hasNonLatinLetters(original) {
+		return
+	}
+
+	if context.Culture == "chinese" {
+		// Kanji names read as Chinese; the romanized family name must have a syllable per
+		// character of the original one (田中 is Tanaka, not Tian)
+		if familyLength := ChineseSurnameLength(original); familyLength > 0 && syllableCount(result.Family) == familyLength {
+			alignChinese(result, []rune(strings.Join(strings.Fields(original), "")), familyLength)
+			return
+		}
+	}
+
+	originalWords := strings.Fields(original)
+	romanizedWords := strings.Fields(romanizedText)
+	if len(originalWords) != len(romanizedWords) {
+		return
+	}
+
+	// Each parsed part names the romanized words it came from, in any case and possibly
+	// joined with a hyphen (AL-RASHID from al Rashid)
+	used := make([]bool, len(romanizedWords))
+	originalOf := func(part string) string {
+		var words []string
+		for _, word := range strings.Fields(part) {
+			for _, piece := range append([]string{word}, strings.Split(word, "-")...) {
+				for i, romanized := range romanizedWords {
+					if !used[i] && strings.EqualFold(piece, romanized) {
+						used[i] = true
+						words = append(words, originalWords[i])
+						break
+					}
+				}
+			}
+		}
+		return strings.Join(words, " ")
+	}
+
+	result.FamilyOriginal = originalOf(result.Family)
+	result.FirstOriginal = originalOf(result.First)
+	for _, middle := range result.Middle {
+		result.MiddleOriginal = append(result.MiddleOriginal, originalOf(middle))
+	}
+}
+
+// alignChinese splits a Han-character name after its family name; a given name parsed into
+// one romanized part per character keeps that split (李小明 as Xiao and Ming)
+func alignChinese(result *NameStructure, name []rune, familyLength int) {
+	result.FamilyOriginal = string(name[:familyLength])
+	given := name[familyLength:]
+	if len(result.Middle) > 0 && len(given) == len(result.Middle)+1 {
+		for _, r := range given[:len(given)-1] {
+			result.MiddleOriginal = append(result.MiddleOriginal, string(r))
+		}
+		given = given[len(given)-1:]
+	}
+	result.FirstOriginal = string(given)
+}
+
+// hasNonLatinLetters reports whether text has letters of a script other than Latin
+func hasNonLatinLetters(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// syllableCount counts the vowel groups of a romanized word, a syllable each in pinyin
+func syllableCount(word string) int {
+	count := 0
+	inVowels := false
+	for _, r := range strings.ToLower(word) {
+		vowel := strings.ContainsRune("aeiouü", r)
+		if vowel && !inVowels {
+			count++
+		}
+		inVowels = vowel
+	}
+	return count
+}
@@ -0,0 +1,98 @@
+package nameparser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Warning codes reported by ValidateStructure
+const (
+	WarningUnexpectedTokenCount = "unexpected_token_count"
+	WarningUnexpectedCharacters = "unexpected_characters"
+)
+
+// StructureWarning flags a way a name does not fit the conventions of its culture
+type StructureWarning struct {
+	Code    string `json:"code"`    // Stable warning code, e.g. "unexpected_token_count"
+	Message string `json:"message"` // Human-readable description
+}
+
+// Cultures lists the naming cultures with conventions of their own
+var Cultures = map[string]bool{
+	"western": true, "chinese": true, "vietnamese": true, "japanese": true, "korean": true,
+	"arabic": true, "russian": true, "indonesian": true, "malaysian": true,
+}
+
+// ValidateStructure checks a name against the conventions of the declared culture: how
+// many parts it has once titles, suffixes and honorifics are set aside, and whether it
+// contains characters names do not use. Chinese names written in Han count a part per
+// character (李小明 has three).
+func (p *Parser) ValidateStructure(text, culture string) (int, []StructureWarning) {
+	// The declared culture decides, whatever the text looks like
+	context := p.getCulturalContext(culture, "", "")
+
+	nameText, _ := ExtractNativeHonorific(text)
+	nameText, _ = ExtractRomanizedHonorifics(nameText)
+	nameText = p.removeTitles(nameText, p.extractTitles(nameText))
+	nameText = p.removeSuffixes(nameText, p.extractSuffixes(nameText))
+
+	parts := 0
+	for _, word := range strings.Fields(nameText) {
+		if context.Culture == "chinese" && isHan(word) {
+			parts += len([]rune(word))
+		} else {
+			parts++
+		}
+	}
+
+	var warnings []StructureWarning
+	switch {
+	case parts < context.MinParts:
+		warnings = append(warnings, StructureWarning{
+			Code:    WarningUnexpectedTokenCount,
+			Message: fmt.Sprintf("%s names usually have at least %d parts, found %d", culture, context.MinParts, parts),
+		})
+	case parts > context.MaxParts:
+		warnings = append(warnings, StructureWarning{
+			Code:    WarningUnexpectedTokenCount,
+			Message: fmt.Sprintf("%s names usually have at most %d parts, found %d", culture, context.MaxParts, parts),
+		})
+	}
+
+	if unexpected := unexpectedNameCharacters(nameText); unexpected != "" {
+		warnings = append(warnings, StructureWarning{
+			Code:    WarningUnexpectedCharacters,
+			Message: fmt.Sprintf("name contains characters names do not use: %s", unexpected),
+		})
+	}
+
+	return parts, warnings
+}
+
+// isHan reports whether word is written entirely in Han characters
+func isHan(word string) bool {
+	for _, r := range word {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// unexpectedNameCharacters lists the distinct characters of text that are not letters,
+// combining marks, spaces or the punctuation names use (hyphens, apostrophes, periods)
+func unexpectedNameCharacters(text string) string {
+	var found []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.Is(unicode.M, r) || unicode.IsSpace(r) || isApostrophe(r) || strings.ContainsRune("-.・", r) {
+			continue
+		}
+		if !seen[r] {
+			seen[r] = true
+			found = append(found, r)
+		}
+	}
+	return string(found)
+}
@@ -0,0 +1,292 @@
+// Package numwords converts spelled-out numbers into Arabic digits.
+package numwords
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// chineseDigits maps Chinese numeral characters to their values
+var chineseDigits = map[rune]int64{
+	'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '兩': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// chineseUnits maps Chinese place-value characters to their multipliers
+var chineseUnits = map[rune]int64{
+	'十': 10, '拾': 10, '百': 100, '佰': 100, '千': 1000, '仟': 1000,
+}
+
+// chineseSections maps Chinese large-number characters that close a section
+var chineseSections = map[rune]int64{
+	'万': 10000, '萬': 10000, '亿': 100000000, '億': 100000000,
+}
+
+// russianWords maps Russian cardinal number words (nominative forms) to values
+var russianWords = map[string]int64{
+	"ноль": 0, "один": 1, "одна": 1, "одно": 1, "два": 2, "две": 2, "три": 3,
+	"четыре": 4, "пять": 5, "шесть": 6, "семь": 7, "восемь": 8, "девять": 9,
+	"десять": 10, "одиннадцать": 11, "двенадцать": 12, "тринадцать": 13,
+	"четырнадцать": 14, "пятнадцать": 15, "шестнадцать": 16, "семнадцать": 17,
+	"восемнадцать": 18, "девятнадцать": 19,
+	"двадцать": 20, "тридцать": 30, "сорок": 40, "пятьдесят": 50,
+	"шестьдесят": 60, "семьдесят": 70, "восемьдесят": 80, "девяносто": 90,
+	"сто": 100, "двести": 200, "триста": 300, "четыреста": 400, "пятьсот": 500,
+	"шестьсот": 600, "семьсот": 700, "восемьсот": 800, "девятьсот": 900,
+}
+
+// russianScales maps Russian scale words to their multipliers
+var russianScales = map[string]int64{
+	"тысяча": 1000, "тысячи": 1000, "тысяч": 1000,
+	"миллион": 1000000, "миллиона": 1000000, "миллионов": 1000000,
+}
+
+// Replace rewrites every spelled-out Chinese or Russian number in text as digits
+func Replace(text string) string {
+	return replaceRussian(replaceChinese(text))
+}
+
+// ParseChinese parses a run of Chinese numeral characters such as "三十五". Runs that aren't
+// well-formed numerals, such as a place value without its digit (千 of 千寻, the surname 万), are
+// rejected.
+func ParseChinese(s string) (int64, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+
+	hasUnit := false
+	for _, r := range runes {
+		_, isDigit := chineseDigits[r]
+		_, isUnit := chineseUnits[r]
+		_, isSection := chineseSections[r]
+		if !isDigit && !isUnit && !isSection {
+			return 0, false
+		}
+		if isUnit || isSection {
+			hasUnit = true
+		}
+	}
+
+	// Digit-by-digit form used for years and codes, e.g. "二零二五"
+	if !hasUnit {
+		var value int64
+		for _, r := range runes {
+			value = value*10 + chineseDigits[r]
+		}
+		return value, true
+	}
+
+	// Place values fall within a section (千 before 百 before 十) and sections fall (亿 before
+	// 万); a digit stands before each place value, or 零 before a digit marks a skipped place
+	var total, section, number, lastSection int64
+	lastUnit := chineseSections['万']
+	pending := false
+	for _, r := range runes {
+		if digit, ok := chineseDigits[r]; ok {
+			if pending && (number != 0 || digit == 0) {
+				return 0, false
+			}
+			number, pending = digit, true
+			continue
+		}
+		if unit, ok := chineseUnits[r]; ok {
+			switch {
+			case unit >= lastUnit:
+				return 0, false
+			case pending && number == 0:
+				return 0, false
+			case !pending:
+				// A leading "十" means "ten", e.g. "十五" is 15
+				if unit != 10 || section != 0 {
+					return 0, false
+				}
+				number = 1
+			}
+			section += number * unit
+			lastUnit, number, pending = unit, 0, false
+			continue
+		}
+		multiplier := chineseSections[r]
+		value := section + number
+		if value == 0 || lastSection != 0 && multiplier >= lastSection {
+			return 0, false
+		}
+		total += value * multiplier
+		lastSection, lastUnit = multiplier, chineseSections['万']
+		section, number, pending = 0, 0, false
+	}
+	if pending && number == 0 {
+		return 0, false
+	}
+
+	return total + section + number, true
+}
+
+// russianPlace returns the place a Russian number word starts at within a group of three
+// digits: 3 for hundreds, 2 for tens and teens, 1 for units
+func russianPlace(value int64) int {
+	switch {
+	case value >= 100:
+		return 3
+	case value >= 10:
+		return 2
+	}
+	return 1
+}
+
+// ParseRussian parses a sequence of Russian number words such as "двадцать пять". Sequences that
+// aren't a well-formed numeral, such as "пять пять" or "два двадцать", are rejected rather than
+// summed.
+func ParseRussian(words []string) (int64, bool) {
+	if len(words) == 0 {
+		return 0, false
+	}
+
+	var total, current, lastScale int64
+	place := 4 // the place the previous word filled; each word must fill a lower one
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if value, ok := russianWords[lower]; ok {
+			if value == 0 && len(words) > 1 {
+				return 0, false
+			}
+			if russianPlace(value) >= place {
+				return 0, false
+			}
+			current += value
+			place = russianPlace(value)
+			if value >= 10 && value < 20 {
+				// A teen fills the units place too
+				place = 1
+			}
+			continue
+		}
+		if scale, ok := russianScales[lower]; ok {
+			if lastScale != 0 && scale >= lastScale {
+				return 0, false
+			}
+			// "тысяча" on its own means one thousand
+			if current == 0 {
+				current = 1
+			}
+			total += current * scale
+			current, lastScale, place = 0, scale, 4
+			continue
+		}
+		return 0, false
+	}
+
+	return total + current, true
+}
+
+// replaceChinese replaces runs of Chinese numeral characters with digits
+func replaceChinese(text string) string {
+	var result strings.Builder
+	var run []rune
+
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if value, ok := ParseChinese(string(run)); ok {
+			result.WriteString(strconv.FormatInt(value, 10))
+		} else {
+			result.WriteString(string(run))
+		}
+		run = run[:0]
+	}
+
+	for _, r := range text {
+		if isChineseNumeral(r) {
+			run = append(run, r)
+			continue
+		}
+		flush()
+		result.WriteRune(r)
+	}
+	flush()
+
+	return result.String()
+}
+
+// replaceRussian replaces sequences of Russian number words with digits
+func replaceRussian(text string) string {
+	tokens := splitWords(text)
+
+	var result strings.Builder
+	for i := 0; i < len(tokens); {
+		// Collect consecutive number words separated by single spaces
+		var words []string
+		j := i
+		for j < len(tokens) && isRussianNumberWord(tokens[j]) {
+			words = append(words, tokens[j])
+			if j+2 < len(tokens) && tokens[j+1] == " " && isRussianNumberWord(tokens[j+2]) {
+				j += 2
+				continue
+			}
+			j++
+			break
+		}
+
+		if len(words) == 0 {
+			result.WriteString(tokens[i])
+			i++
+			continue
+		}
+
+		if value, ok := ParseRussian(words); ok {
+			result.WriteString(strconv.FormatInt(value, 10))
+		} else {
+			result.WriteString(strings.Join(tokens[i:j], ""))
+		}
+		i = j
+	}
+
+	return result.String()
+}
+
+// splitWords splits text into alternating letter runs and separator runs
+func splitWords(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	inWord := false
+
+	for _, r := range text {
+		letter := unicode.IsLetter(r)
+		if current.Len() > 0 && letter != inWord {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+		inWord = letter
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// isChineseNumeral reports whether r is a Chinese numeral or place-value character
+func isChineseNumeral(r rune) bool {
+	if _, ok := chineseDigits[r]; ok {
+		return true
+	}
+	if _, ok := chineseUnits[r]; ok {
+		return true
+	}
+	_, ok := chineseSections[r]
+	return ok
+}
+
+// isRussianNumberWord reports whether token is a Russian number or scale word
+func isRussianNumberWord(token string) bool {
+	lower := strings.ToLower(token)
+	if _, ok := russianWords[lower]; ok {
+		return true
+	}
+	_, ok := russianScales[lower]
+	return ok
+}
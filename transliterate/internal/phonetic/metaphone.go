@@ -0,0 +1,169 @@
+// Package phonetic computes sound-alike keys and approximate pronunciations for romanized names.
+package phonetic
+
+import "strings"
+
+// initialSilent lists word-initial pairs whose first letter is not pronounced
+var initialSilent = []string{"AE", "GN", "KN", "PN", "WR"}
+
+// Metaphone returns the Metaphone key of an ASCII word, so that spellings which sound
+// alike share a key (Catherine and Katherine are both K0RN). Letters other than A-Z are
+// ignored; "0" stands for the "th" sound and "X" for "sh".
+func Metaphone(word string) string {
+	var letters []byte
+	for _, c := range []byte(strings.ToUpper(word)) {
+		if c < 'A' || c > 'Z' {
+			continue
+		}
+		// Doubled letters sound as one, except CC (accent)
+		if n := len(letters); n > 0 && letters[n-1] == c && c != 'C' {
+			continue
+		}
+		letters = append(letters, c)
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	start := 0
+	for _, pair := range initialSilent {
+		if len(letters) >= 2 && string(letters[:2]) == pair {
+			start = 1
+		}
+	}
+	var key strings.Builder
+	switch {
+	case letters[0] == 'X':
+		key.WriteByte('S')
+		start = 1
+	case len(letters) >= 2 && letters[0] == 'W' && letters[1] == 'H':
+		key.WriteByte('W')
+		start = 2
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(letters) {
+			return 0
+		}
+		return letters[i]
+	}
+	follows := func(i int, s string) bool {
+		return i+len(s) <= len(letters) && string(letters[i:i+len(s)]) == s
+	}
+
+	for i := start; i < len(letters); i++ {
+		c, prev, next := letters[i], at(i-1), at(i+1)
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			// Only a leading vowel is kept
+			if i == start && key.Len() == 0 {
+				key.WriteByte(c)
+			}
+		case 'B':
+			// Silent in a final MB (Plumb)
+			if !(prev == 'M' && i == len(letters)-1) {
+				key.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case follows(i+1, "IA"):
+				key.WriteByte('X')
+			case next == 'H':
+				if prev == 'S' {
+					key.WriteByte('K')
+				} else {
+					key.WriteByte('X')
+				}
+				i++
+			case next == 'I' || next == 'E' || next == 'Y':
+				if prev != 'S' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'D':
+			if next == 'G' && isFrontVowel(at(i+2)) {
+				key.WriteByte('J')
+			} else {
+				key.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && i+2 < len(letters) && !isVowel(at(i+2)):
+				// Silent in GH before a consonant (Knight)
+			case next == 'N' && (i+2 == len(letters) || follows(i+1, "NED") && i+4 == len(letters)):
+				// Silent in a final GN or GNED (Sign, Signed)
+			case prev == 'D' && isFrontVowel(next):
+				// Already sounded by the D (Edge)
+			case isFrontVowel(next):
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'H':
+			if isVowel(next) && !strings.ContainsRune("CGPST", rune(prev)) {
+				key.WriteByte('H')
+			}
+		case 'K':
+			if prev != 'C' {
+				key.WriteByte('K')
+			}
+		case 'P':
+			if next == 'H' {
+				key.WriteByte('F')
+				i++
+			} else {
+				key.WriteByte('P')
+			}
+		case 'Q':
+			key.WriteByte('K')
+		case 'S':
+			switch {
+			case next == 'H':
+				key.WriteByte('X')
+				i++
+			case follows(i+1, "IO") || follows(i+1, "IA"):
+				key.WriteByte('X')
+			default:
+				key.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case follows(i+1, "IO") || follows(i+1, "IA"):
+				key.WriteByte('X')
+			case next == 'H':
+				key.WriteByte('0')
+				i++
+			case follows(i+1, "CH"):
+				// Silent before CH (Mitchell)
+			default:
+				key.WriteByte('T')
+			}
+		case 'V':
+			key.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(next) {
+				key.WriteByte(c)
+			}
+		case 'X':
+			key.WriteString("KS")
+		case 'Z':
+			key.WriteByte('S')
+		default:
+			key.WriteByte(c)
+		}
+	}
+
+	return key.String()
+}
+
+// isVowel reports whether c is an uppercase ASCII vowel
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
+
+// isFrontVowel reports whether c softens a preceding C, D or G
+func isFrontVowel(c byte) bool {
+	return c == 'E' || c == 'I' || c == 'Y'
+}
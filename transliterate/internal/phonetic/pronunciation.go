@@ -0,0 +1,213 @@
+package phonetic
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pronounce returns a coarse IPA pronunciation of a name, read from the romanized output for
+// scripts romanized by sound (Pinyin for chinese) and from the original letters for scripts
+// whose romanization loses sounds (the emphatic consonants of arabic). It is best-effort:
+// Pinyin carries no tones, and unvowelled Arabic has no short vowels to read. Other scripts
+// give "".
+func Pronounce(original, romanized, script string) string {
+	switch script {
+	case "chinese":
+		return pinyinIPA(romanized)
+	case "arabic":
+		return arabicIPA(original)
+	}
+	return ""
+}
+
+// pinyinInitials maps the Pinyin initials to IPA, longest first so zh is matched before z
+var pinyinInitials = []struct{ pinyin, ipa string }{
+	{"zh", "ʈʂ"}, {"ch", "ʈʂʰ"}, {"sh", "ʂ"},
+	{"b", "p"}, {"p", "pʰ"}, {"m", "m"}, {"f", "f"}, {"d", "t"}, {"t", "tʰ"}, {"n", "n"},
+	{"l", "l"}, {"g", "k"}, {"k", "kʰ"}, {"h", "x"}, {"j", "tɕ"}, {"q", "tɕʰ"}, {"x", "ɕ"},
+	{"r", "ʐ"}, {"z", "ts"}, {"c", "tsʰ"}, {"s", "s"}, {"y", "j"}, {"w", "w"},
+}
+
+// pinyinFinals maps the Pinyin finals to IPA, longest first
+var pinyinFinals = []struct{ pinyin, ipa string }{
+	{"iang", "jaŋ"}, {"iong", "jʊŋ"}, {"uang", "waŋ"}, {"ueng", "wəŋ"},
+	{"ang", "aŋ"}, {"eng", "əŋ"}, {"ing", "iŋ"}, {"ong", "ʊŋ"}, {"iao", "jau"}, {"ian", "jɛn"},
+	{"uai", "wai"}, {"uan", "wan"},
+	{"ai", "ai"}, {"ei", "ei"}, {"ao", "au"}, {"ou", "ou"}, {"an", "an"}, {"en", "ən"},
+	{"er", "aɚ"}, {"ia", "ja"}, {"ie", "jɛ"}, {"iu", "jou"}, {"in", "in"}, {"ua", "wa"},
+	{"uo", "wo"}, {"ui", "wei"}, {"un", "wən"}, {"ue", "ɥɛ"}, {"ve", "ɥɛ"},
+	{"a", "a"}, {"o", "o"}, {"e", "ɤ"}, {"i", "i"}, {"u", "u"}, {"v", "y"},
+}
+
+// pinyinFrontedFinals are the finals read with ü after j, q, x and y (ju is jü)
+var pinyinFrontedFinals = map[string]string{"u": "y", "uan": "ɥɛn", "un": "yn", "ue": "ɥɛ"}
+
+// pinyinFrontingInitials are the initials after which u is written for ü
+var pinyinFrontingInitials = map[string]bool{"j": true, "q": true, "x": true, "y": true}
+
+// pinyinSibilants are the initials whose i is the buzzed vowel of zhi, ci and si
+var pinyinSibilants = map[string]bool{"zh": true, "ch": true, "sh": true, "r": true, "z": true, "c": true, "s": true}
+
+// pinyinIPA reads romanized Chinese syllable by syllable, splitting words at capitals
+// (LiXiaoMing) and then by the longest initial and final that match, with syllables
+// separated by dots
+func pinyinIPA(romanized string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(romanized, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		var syllables []string
+		for _, chunk := range splitCapitals(word) {
+			syllables = append(syllables, pinyinSyllables(strings.ToLower(chunk))...)
+		}
+		if len(syllables) > 0 {
+			words = append(words, strings.Join(syllables, "."))
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitCapitals splits a word before each capital letter after the first (LiXiaoMing)
+func splitCapitals(word string) []string {
+	var chunks []string
+	start := 0
+	for i, r := range word {
+		if i > start && unicode.IsUpper(r) {
+			chunks = append(chunks, word[start:i])
+			start = i
+		}
+	}
+	return append(chunks, word[start:])
+}
+
+// pinyinSyllables reads a lowercase run of Pinyin; letters that start no syllable are skipped
+func pinyinSyllables(text string) []string {
+	text = strings.ReplaceAll(text, "ü", "v")
+	var syllables []string
+	for text != "" {
+		initial, initialIPA := "", ""
+		for _, candidate := range pinyinInitials {
+			if strings.HasPrefix(text, candidate.pinyin) {
+				initial, initialIPA = candidate.pinyin, candidate.ipa
+				break
+			}
+		}
+		rest := text[len(initial):]
+
+		final, finalIPA := "", ""
+		for _, candidate := range pinyinFinals {
+			if strings.HasPrefix(rest, candidate.pinyin) {
+				final, finalIPA = candidate.pinyin, candidate.ipa
+				break
+			}
+		}
+		if final == "" {
+			if initial == "" {
+				_, size := utf8.DecodeRuneInString(text)
+				text = text[size:]
+				continue
+			}
+			// A bare initial, as in an abbreviation, is read as written
+			syllables = append(syllables, initialIPA)
+			text = rest
+			continue
+		}
+
+		switch {
+		case pinyinFrontingInitials[initial] && pinyinFrontedFinals[final] != "":
+			finalIPA = pinyinFrontedFinals[final]
+		case final == "i" && pinyinSibilants[initial]:
+			finalIPA = "ɨ"
+		}
+		// y and w spell the glide of the final itself (yi is i, wu is u)
+		if (initial == "y" || initial == "w") && strings.IndexAny(finalIPA, "ijyɥuw") == 0 {
+			initialIPA = ""
+		}
+		syllables = append(syllables, initialIPA+finalIPA)
+		text = rest[len(final):]
+	}
+	return syllables
+}
+
+// arabicConsonants maps Arabic letters to IPA, marking the emphatic consonants (ṣ ḍ ṭ ẓ) with
+// ˤ and keeping the pharyngeal and uvular sounds the Latin romanization merges away
+var arabicConsonants = map[rune]string{
+	'ب': "b", 'ت': "t", 'ث': "θ", 'ج': "dʒ", 'ح': "ħ", 'خ': "x", 'د': "d", 'ذ': "ð",
+	'ر': "r", 'ز': "z", 'س': "s", 'ش': "ʃ", 'ص': "sˤ", 'ض': "dˤ", 'ط': "tˤ", 'ظ': "ðˤ",
+	'ع': "ʕ", 'غ': "ɣ", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n",
+	'ه': "h", 'ء': "ʔ", 'ؤ': "ʔ", 'ئ': "ʔ", 'أ': "ʔa", 'إ': "ʔi", 'آ': "ʔaː", 'ة': "a",
+	'ى': "aː",
+}
+
+// arabicVowelMarks maps the short vowel and nunation marks to IPA
+var arabicVowelMarks = map[rune]string{
+	'ً': "an", 'ٌ': "un", 'ٍ': "in",
+	'َ': "a", 'ُ': "u", 'ِ': "i",
+}
+
+// Arabic marks with a reading of their own
+const (
+	arabicShadda = 'ّ' // Doubles the consonant before it
+	arabicSukun  = 'ْ' // No vowel
+)
+
+// arabicIPA reads Arabic letters one by one: alif, waw and ya are long vowels after a
+// consonant and consonants elsewhere, and the shadda doubles a consonant. Letters outside
+// the table are skipped.
+func arabicIPA(original string) string {
+	var words []string
+	for _, word := range strings.Fields(original) {
+		var sounds []string
+		consonant := -1 // Index in sounds of the last consonant, which a shadda doubles
+		afterConsonant := false
+		for _, r := range word {
+			if sound, ok := arabicVowelMarks[r]; ok {
+				sounds = append(sounds, sound)
+				afterConsonant = false
+				continue
+			}
+			switch r {
+			case arabicShadda:
+				if consonant >= 0 {
+					sounds = slices.Insert(sounds, consonant+1, sounds[consonant])
+				}
+				continue
+			case arabicSukun:
+				continue
+			case 'ا':
+				if afterConsonant {
+					sounds = append(sounds, "aː")
+				} else {
+					sounds = append(sounds, "a")
+				}
+				afterConsonant = false
+				continue
+			case 'و', 'ي':
+				long, glide := "uː", "w"
+				if r == 'ي' {
+					long, glide = "iː", "j"
+				}
+				if afterConsonant {
+					sounds = append(sounds, long)
+					afterConsonant = false
+				} else {
+					sounds = append(sounds, glide)
+					consonant, afterConsonant = len(sounds)-1, true
+				}
+				continue
+			}
+			if sound, ok := arabicConsonants[r]; ok {
+				sounds = append(sounds, sound)
+				if !strings.ContainsAny(sound, "aiuː") {
+					consonant, afterConsonant = len(sounds)-1, true
+				} else {
+					afterConsonant = false
+				}
+			}
+		}
+		if len(sounds) > 0 {
+			words = append(words, strings.Join(sounds, ""))
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,150 @@
+package transliteration
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Alternative is a candidate output with the frequency weight of the reading it uses
+type Alternative struct {
+	Text   string
+	Weight float64
+}
+
+// alternativeClosenessWeight is how much an alternative's closeness to the primary output
+// adds to its frequency weight when ranking; a spelling that differs from the primary in one
+// letter is a likelier slip than one that differs throughout
+const alternativeClosenessWeight = 0.25
+
+// Alternatives returns other plausible conversions of text than primary, its conversion. Each
+// replaces one character's database mapping with a competing mapping from character_mappings,
+// and they are ranked by RankAlternatives. Characters read with their neighbours keep their
+// reading, and text whose segments don't give primary (surname readings) has none.
+func (e *Engine) Alternatives(ctx context.Context, primary, text, fromScript, toScript, locale string) ([]string, error) {
+	if !e.config.UseDatabase || e.db == nil {
+		return nil, nil
+	}
+
+	segments, err := e.Segments(ctx, text, fromScript, toScript, locale)
+	if err != nil {
+		return nil, err
+	}
+	var rebuilt strings.Builder
+	for _, segment := range segments {
+		rebuilt.WriteString(segment.Output)
+	}
+	if rebuilt.String() != primary {
+		return nil, nil
+	}
+
+	competing := make(map[string][]Alternative)
+	var candidates []Alternative
+	for i, segment := range segments {
+		if segment.Method != "database" || segment.Contextual {
+			continue
+		}
+
+		key := segment.Script + ":" + segment.Source
+		mappings, ok := competing[key]
+		if !ok {
+			mappings, err = e.competingMappings(ctx, segment.Source, segment.Script, toScript, segment.Output, locale)
+			if err != nil {
+				return nil, err
+			}
+			competing[key] = mappings
+		}
+
+		for _, mapping := range mappings {
+			var spelling strings.Builder
+			for j, other := range segments {
+				if j == i {
+					spelling.WriteString(mapping.Text)
+				} else {
+					spelling.WriteString(other.Output)
+				}
+			}
+			candidates = append(candidates, Alternative{Text: spelling.String(), Weight: mapping.Weight})
+		}
+	}
+
+	return RankAlternatives(primary, candidates), nil
+}
+
+// competingMappings returns the database mappings for sourceChar other than the one used, as
+// alternatives holding the mapped text
+func (e *Engine) competingMappings(ctx context.Context, sourceChar, fromScript, toScript, used, locale string) ([]Alternative, error) {
+	rows, err := e.db.Query(ctx, `
+		SELECT target_char, MAX(frequency_weight)
+		FROM character_mappings
+		WHERE source_char = $1
+			AND source_script = $2
+			AND target_script = $3
+			AND target_char <> $4
+			AND ($5::text IS NULL OR locale = $5 OR locale IS NULL)
+		GROUP BY target_char
+	`, sourceChar, fromScript, toScript, used, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mappings []Alternative
+	for rows.Next() {
+		var mapping Alternative
+		if err := rows.Scan(&mapping.Text, &mapping.Weight); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, rows.Err()
+}
+
+// RankAlternatives orders candidate outputs by frequency weight plus closeness to the primary
+// output, most likely first. Candidates that differ from the primary output or an earlier
+// candidate only in case are dropped.
+func RankAlternatives(primary string, candidates []Alternative) []string {
+	type scored struct {
+		text  string
+		score float64
+	}
+	ranked := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, scored{candidate.Text, candidate.Weight + alternativeClosenessWeight*closeness(primary, candidate.Text)})
+	}
+	sort.SliceStable(ranked, func(a, b int) bool { return ranked[a].score > ranked[b].score })
+
+	seen := map[string]bool{strings.ToLower(primary): true}
+	var alternatives []string
+	for _, candidate := range ranked {
+		key := strings.ToLower(candidate.text)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		alternatives = append(alternatives, candidate.text)
+	}
+	return alternatives
+}
+
+// closeness is the share of character positions at which two outputs agree, ignoring case:
+// 1 for the same text, 0 for texts with nothing in common
+func closeness(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	length := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if length == 0 {
+		return 1
+	}
+
+	same := 0
+	for len(a) > 0 && len(b) > 0 {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if ra == rb {
+			same++
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	return float64(same) / float64(length)
+}
@@ -0,0 +1,88 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+)
+
+// armenianLetters romanizes Eastern Armenian lowercase letters; capitals are folded onto them
+var armenianLetters = map[rune]string{
+	'ա': "a", 'բ': "b", 'գ': "g", 'դ': "d", 'ե': "e", 'զ': "z", 'է': "e",
+	'ը': "y", 'թ': "t", 'ժ': "zh", 'ի': "i", 'լ': "l", 'խ': "kh", 'ծ': "ts",
+	'կ': "k", 'հ': "h", 'ձ': "dz", 'ղ': "gh", 'ճ': "ch", 'մ': "m", 'յ': "y",
+	'ն': "n", 'շ': "sh", 'ո': "o", 'չ': "ch", 'պ': "p", 'ջ': "j", 'ռ': "r",
+	'ս': "s", 'վ': "v", 'տ': "t", 'ր': "r", 'ց': "ts", 'ւ': "v", 'փ': "p",
+	'ք': "k", 'օ': "o", 'ֆ': "f",
+
+	// Ligature of ե and ւ
+	'և': "ev",
+
+	// Punctuation
+	'։': ".", '՝': ",", '՞': "?", '՜': "!", '՛': "'",
+}
+
+// armenianWestern holds the Western Armenian readings, where the stops and affricates
+// are devoiced or voiced relative to Eastern Armenian (Պետրոս Bedros, not Petros)
+var armenianWestern = map[rune]string{
+	'բ': "p", 'պ': "b", 'գ': "k", 'կ': "g", 'դ': "t", 'տ': "d",
+	'ձ': "ts", 'ծ': "dz", 'ջ': "ch", 'ճ': "j",
+}
+
+// armenianInitial holds the readings of vowels that gain a glide at the start of a word
+var armenianInitial = map[rune]string{'ե': "ye", 'ո': "vo", 'և': "yev"}
+
+// transliterateArmenian handles Armenian to Latin conversion
+func (e *Engine) transliterateArmenian(r rune) string {
+	lower := unicode.ToLower(r)
+	if lower == r {
+		return armenianLetters[r]
+	}
+	return capitalizeFirst(armenianLetters[lower])
+}
+
+// armenianDigraph reads ո followed by ւ as the single vowel u (Ղուկաս Ghukas)
+func (e *Engine) armenianDigraph(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "armenian" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+	if unicode.ToLower(r) != 'ո' || next != 'ւ' {
+		return nil, false
+	}
+
+	output := "u"
+	if unicode.IsUpper(r) {
+		output = "U"
+	}
+	return &RuneResult{Output: output, Confidence: 0.85, Method: "builtin"}, true
+}
+
+// armenianContextual applies readings that depend on position or dialect: ե, ո and և
+// take a glide at the start of a word (Երևան Yerevan), and Western Armenian locales
+// ("hyw") use the Western consonant values
+func (e *Engine) armenianContextual(r, prev rune, fromScript, toScript, locale string) (*RuneResult, bool) {
+	if fromScript != "armenian" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	lower := unicode.ToLower(r)
+	output, ok := "", false
+	if !isArmenianLetter(prev) {
+		output, ok = armenianInitial[lower]
+	}
+	if !ok && strings.HasPrefix(locale, "hyw") {
+		output, ok = armenianWestern[lower]
+	}
+	if !ok {
+		return nil, false
+	}
+
+	if lower != r {
+		output = capitalizeFirst(output)
+	}
+	return &RuneResult{Output: output, Confidence: 0.85, Method: "builtin"}, true
+}
+
+// isArmenianLetter reports whether r is an Armenian letter
+func isArmenianLetter(r rune) bool {
+	return unicode.Is(unicode.Armenian, r) && unicode.IsLetter(r)
+}
@@ -0,0 +1,31 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// applySourceCase recases the multi-letter output of an uppercase source letter: title
+// case inside a capitalized word (Щукин Shchukin) and full upper case when the
+// surrounding letters are capitals too (ЩУКИН SHCHUKIN)
+func applySourceCase(output string, r rune, allCaps bool) string {
+	if !unicode.IsUpper(r) || utf8.RuneCountInString(output) < 2 {
+		return output
+	}
+	if allCaps {
+		return strings.ToUpper(output)
+	}
+
+	first, size := utf8.DecodeRuneInString(output)
+	return string(unicode.ToUpper(first)) + strings.ToLower(output[size:])
+}
+
+// inCapsWord reports whether a letter sits in an all-caps word: the next letter is a
+// capital, or it ends the word after one
+func inCapsWord(next rune, prevUpper bool) bool {
+	if unicode.IsLetter(next) {
+		return unicode.IsUpper(next)
+	}
+	return prevUpper
+}
@@ -0,0 +1,71 @@
+package transliteration
+
+import "unicode/utf8"
+
+// chineseWords holds the readings of words containing polyphonic characters, whose reading
+// depends on the word (长 is cháng in 长城 but zhǎng in 校长; 行 is háng in 银行 but xíng
+// in 行人), along with common place names. Each reading is written as one ASCII word.
+var chineseWords = map[string]string{
+	// 长 cháng / zhǎng
+	"长城": "Changcheng", "長城": "Changcheng",
+	"长江": "Changjiang", "長江": "Changjiang",
+	"长沙": "Changsha", "長沙": "Changsha",
+	"长春": "Changchun", "長春": "Changchun",
+	"长安": "Chang'an", "長安": "Chang'an",
+	"校长": "Xiaozhang", "校長": "Xiaozhang",
+	"市长": "Shizhang", "市長": "Shizhang",
+	"成长": "Chengzhang", "成長": "Chengzhang",
+	"长大": "Zhangda", "長大": "Zhangda",
+
+	// 行 háng / xíng
+	"银行": "Yinhang", "銀行": "Yinhang",
+	"行长": "Hangzhang", "行長": "Hangzhang",
+	"行业": "Hangye", "行業": "Hangye",
+	"行人": "Xingren",
+	"旅行": "Luxing",
+
+	// 重 chóng / zhòng
+	"重庆": "Chongqing", "重慶": "Chongqing",
+	"重复": "Chongfu", "重複": "Chongfu",
+	"重要": "Zhongyao",
+
+	// 乐 lè / yuè
+	"音乐": "Yinyue", "音樂": "Yinyue",
+	"快乐": "Kuaile", "快樂": "Kuaile",
+	"乐山": "Leshan", "樂山": "Leshan",
+
+	// 朝 cháo / zhāo
+	"朝阳": "Chaoyang", "朝陽": "Chaoyang",
+	"朝鲜": "Chaoxian", "朝鮮": "Chaoxian",
+
+	// Other place names with polyphonic characters
+	"厦门": "Xiamen", "廈門": "Xiamen",
+	"西藏": "Xizang",
+	"蚌埠": "Bengbu",
+	"六安": "Lu'an",
+}
+
+// maxChineseWordRunes is the length of the longest word in chineseWords, in characters
+const maxChineseWordRunes = 2
+
+// chineseWordReading returns the reading of the longest known word at the start of text
+// and its length in bytes
+func (e *Engine) chineseWordReading(text, fromScript, toScript string) (*RuneResult, int, bool) {
+	if fromScript != "chinese" || (toScript != "latin" && toScript != "ascii") {
+		return nil, 0, false
+	}
+
+	// Collect the end offsets of the first few characters, then try the longest first
+	var ends []int
+	for offset := 0; offset < len(text) && len(ends) < maxChineseWordRunes; {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+		ends = append(ends, offset)
+	}
+	for n := len(ends) - 1; n >= 1; n-- {
+		if reading, ok := chineseWords[text[:ends[n]]]; ok {
+			return &RuneResult{Output: reading, Confidence: 0.95, Method: "dictionary"}, ends[n], true
+		}
+	}
+	return nil, 0, false
+}
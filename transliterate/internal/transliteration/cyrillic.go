@@ -0,0 +1,108 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// serbianLetters holds the Serbian Latin (Gaj's alphabet) readings of the letters that differ
+// from the Russian scheme, except đ, which is written dj as in passports and the press
+// (Ђоковић Djoković); capitals are folded onto them
+var serbianLetters = map[rune]string{
+	'ђ': "dj", 'ж': "ž", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "ć",
+	'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+}
+
+// macedonianLetters holds the official Macedonian romanization used in passports, which
+// keeps to ASCII (Ѓорѓи Gjorgji, Ќосевски Kjosevski); capitals are folded onto them
+var macedonianLetters = map[rune]string{
+	'ѓ': "gj", 'ж': "zh", 'ѕ': "dz", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ќ': "kj",
+	'х': "h", 'ц': "c", 'ч': "ch", 'џ': "dzh", 'ш': "sh",
+}
+
+// cyrillicNational applies the national scheme selected by a Serbian ("sr") or Macedonian
+// ("mk") locale. Serbian diacritics are dropped for ASCII output (ć c, š s).
+func cyrillicNational(r rune, fromScript, toScript, locale string) (*RuneResult, bool) {
+	if fromScript != "cyrillic" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	var letters map[rune]string
+	switch {
+	case strings.HasPrefix(locale, "sr"):
+		letters = serbianLetters
+	case strings.HasPrefix(locale, "mk"):
+		letters = macedonianLetters
+	default:
+		return nil, false
+	}
+
+	lower := unicode.ToLower(r)
+	output, ok := letters[lower]
+	if !ok {
+		return nil, false
+	}
+
+	if toScript == "ascii" {
+		output = stripMarks(output)
+	}
+	if lower != r {
+		output = capitalizeFirst(output)
+	}
+	return &RuneResult{Output: output, Confidence: 0.9, Method: "builtin"}, true
+}
+
+// cyrillicVowels are the vowel letters after which Russian е is iotated (Сергеевич Sergeyevich)
+const cyrillicVowels = "аеёиоуыэюяіїє"
+
+// cyrillicContextual converts the letters whose reading depends on their neighbours. In
+// Russian and by default, е is ye at the start of a word, after a vowel and after ь or ъ
+// (Евгений Yevgeniy, Григорьев Grigoryev), ь is y before о and и (бульон bulyon) and
+// otherwise silent with ъ (Соловьёв Solovyov). Ukrainian keeps е as e; Bulgarian keeps е as
+// e and writes ъ as the vowel a (България Balgariya). Serbian and Macedonian have none of
+// these letters and are left to cyrillicNational.
+func cyrillicContextual(r, prev, next rune, fromScript, toScript, locale string) (*RuneResult, bool) {
+	if fromScript != "cyrillic" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+	if strings.HasPrefix(locale, "sr") || strings.HasPrefix(locale, "mk") {
+		return nil, false
+	}
+
+	lower := unicode.ToLower(r)
+	prevLower := unicode.ToLower(prev)
+	nextLower := unicode.ToLower(next)
+	russian := !strings.HasPrefix(locale, "uk") && !strings.HasPrefix(locale, "bg")
+
+	var output string
+	switch {
+	case lower == 'е' && russian && (!unicode.IsLetter(prev) || strings.ContainsRune(cyrillicVowels+"ьъ", prevLower)):
+		output = "ye"
+	case lower == 'ъ' && strings.HasPrefix(locale, "bg"):
+		output = "a"
+	case lower == 'ь' && (nextLower == 'о' || (russian && nextLower == 'и')):
+		output = "y"
+	case lower == 'ь' || lower == 'ъ':
+		output = ""
+	default:
+		return nil, false
+	}
+
+	if lower != r {
+		output = capitalizeFirst(output)
+	}
+	return &RuneResult{Output: output, Confidence: 0.85, Method: "builtin"}, true
+}
+
+// stripMarks removes combining marks from Latin text (dž dz)
+func stripMarks(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// decomposedRune converts an accented letter with no rule of its own (έ, ϊ) through its base
+// letter. The accents are dropped, or with PreserveDiacritics and Latin output carried over
+// to the converted letter (Σοφία Sophía). Latin input is left to the passthrough rules.
+func (e *Engine) decomposedRune(r rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript == "latin" || fromScript == "ascii" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	decomposed := norm.NFD.String(string(r))
+	base, size := utf8.DecodeRuneInString(decomposed)
+	marks := decomposed[size:]
+	if marks == "" || strings.IndexFunc(marks, func(m rune) bool { return !unicode.Is(unicode.Mn, m) }) >= 0 {
+		return nil, false
+	}
+
+	output, ok := e.standardTable(fromScript)[base]
+	if !ok {
+		output = e.applyBuiltinRules(base, fromScript, toScript)
+	}
+	if output == "" {
+		return nil, false
+	}
+
+	if toScript == "ascii" {
+		output = stripMarks(output)
+	} else if e.config.PreserveDiacritics {
+		// Marks go on the first letter, where the accent sat in the source
+		_, first := utf8.DecodeRuneInString(output)
+		output = norm.NFC.String(output[:first] + marks + output[first:])
+	}
+
+	return &RuneResult{
+		Output:     output,
+		Confidence: 0.8,
+		Method:     "builtin",
+	}, true
+}
@@ -0,0 +1,60 @@
+package transliteration
+
+import "unicode"
+
+// ethiopicBase is the first code point of the Ethiopic block. Syllables are laid out in rows
+// of eight, one row per consonant, with the vowel order as the offset within the row.
+const ethiopicBase = 0x1200
+
+// ethiopicConsonants romanizes the consonant of each row, keyed by the row's first syllable.
+// The glottal and pharyngeal rows (አ, ዐ) have no consonant in ASCII.
+var ethiopicConsonants = map[rune]string{
+	'ሀ': "h", 'ለ': "l", 'ሐ': "h", 'መ': "m", 'ሠ': "s", 'ረ': "r", 'ሰ': "s", 'ሸ': "sh",
+	'ቀ': "q", 'ቈ': "qw", 'ቐ': "qh", 'ቘ': "qhw", 'በ': "b", 'ቨ': "v", 'ተ': "t", 'ቸ': "ch",
+	'ኀ': "h", 'ኈ': "hw", 'ነ': "n", 'ኘ': "ny", 'አ': "", 'ከ': "k", 'ኰ': "kw", 'ኸ': "kh",
+	'ዀ': "khw", 'ወ': "w", 'ዐ': "", 'ዘ': "z", 'ዠ': "zh", 'የ': "y", 'ደ': "d", 'ዸ': "dd",
+	'ጀ': "j", 'ገ': "g", 'ጐ': "gw", 'ጘ': "gg", 'ጠ': "t'", 'ጨ': "ch'", 'ጰ': "p'", 'ጸ': "ts'",
+	'ፀ': "ts'", 'ፈ': "f", 'ፐ': "p",
+}
+
+// ethiopicVowels holds the vowel of each order: ä, u, i, a, e, the sixth order (ə or no
+// vowel, so ም is m), o and wa. The labialized rows (ቈ ኰ ጐ) use the same offsets.
+var ethiopicVowels = [8]string{"e", "u", "i", "a", "e", "", "o", "wa"}
+
+// ethiopicLaryngeals are the rows whose first order is read a rather than e (ሀ ha, አ a)
+var ethiopicLaryngeals = map[rune]bool{'ሀ': true, 'ሐ': true, 'ኀ': true, 'አ': true, 'ዐ': true}
+
+// ethiopicSpecial holds the syllables outside the regular rows and the Ethiopic punctuation
+var ethiopicSpecial = map[rune]string{
+	'ፘ': "mya", 'ፙ': "rya", 'ፚ': "fya",
+
+	// Punctuation; the wordspace ፡ separates words in older writing
+	'፡': " ", '።': ".", '፣': ",", '፤': ";", '፥': ":", '፦': ":", '፧': "?", '፨': "",
+}
+
+// transliterateEthiopic handles Ge'ez (Amharic, Tigrinya) to Latin conversion by splitting a
+// syllable into its consonant row and vowel order (ሰ se, ላ la, ም m)
+func (e *Engine) transliterateEthiopic(r rune) string {
+	if output, ok := ethiopicSpecial[r]; ok {
+		return output
+	}
+	if !unicode.Is(unicode.Ethiopic, r) || !unicode.IsLetter(r) || r < ethiopicBase {
+		return ""
+	}
+
+	order := (r - ethiopicBase) % 8
+	row := r - order
+	consonant, ok := ethiopicConsonants[row]
+	if !ok {
+		return ""
+	}
+
+	switch {
+	case order == 0 && ethiopicLaryngeals[row]:
+		return consonant + "a"
+	case order == 5 && consonant == "":
+		// A bare glottal stop is written for a word-initial i (እንጀራ injera)
+		return "i"
+	}
+	return consonant + ethiopicVowels[order]
+}
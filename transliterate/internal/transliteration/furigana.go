@@ -0,0 +1,73 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// furiganaClose maps the ASCII and full-width opening parentheses around a reading to their closers
+var furiganaClose = map[rune]rune{'(': ')', '（': '）'}
+
+// replaceReadings swaps kanji followed by a parenthesized kana reading, e.g. "田中(たなか)",
+// for the reading itself, since furigana is the authoritative pronunciation. With kanjiNames
+// set, kanji without furigana that spell a known name are replaced by its reading too. It
+// returns the new text and the byte offsets where each substituted reading starts.
+func replaceReadings(text string, kanjiNames bool) (string, map[int]bool) {
+	if !kanjiNames && !strings.ContainsAny(text, "(（") {
+		return text, nil
+	}
+
+	runes := []rune(text)
+	var result strings.Builder
+	readings := make(map[int]bool)
+
+	for i := 0; i < len(runes); {
+		// A run of kanji...
+		end := i
+		for end < len(runes) && unicode.Is(unicode.Han, runes[end]) {
+			end++
+		}
+
+		// ...directly followed by a parenthesized kana reading
+		if end > i && end < len(runes) {
+			if closing, ok := furiganaClose[runes[end]]; ok {
+				readingEnd := end + 1
+				for readingEnd < len(runes) && isKana(runes[readingEnd]) {
+					readingEnd++
+				}
+				if readingEnd > end+1 && readingEnd < len(runes) && runes[readingEnd] == closing {
+					readings[result.Len()] = true
+					result.WriteString(string(runes[end+1 : readingEnd]))
+					i = readingEnd + 1
+					continue
+				}
+			}
+		}
+
+		if end == i {
+			end = i + 1
+		} else if kanjiNames {
+			writeKanjiNames(&result, runes[i:end], readings)
+			i = end
+			continue
+		}
+		result.WriteString(string(runes[i:end]))
+		i = end
+	}
+
+	return result.String(), readings
+}
+
+// isKana reports whether r is hiragana, katakana or the prolonged sound mark
+func isKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// capitalizeFirst upper-cases the first letter of a romanized word
+func capitalizeFirst(word string) string {
+	for i, r := range word {
+		return word[:i] + string(unicode.ToUpper(r)) + word[i+utf8.RuneLen(r):]
+	}
+	return word
+}
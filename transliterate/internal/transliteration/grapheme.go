@@ -0,0 +1,66 @@
+package transliteration
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// asciiApproximationConfidence is the confidence of ASCII output that approximates a letter
+// by dropping its accents or by a look-alike
+const asciiApproximationConfidence = 0.3
+
+// unmappedMarks returns the length of the combining marks at the start of text that have no
+// mapping of their own (Arabic harakat, a tilde on q). They extend the grapheme cluster of the
+// character before them, and are converted with it rather than one by one; marks that do map
+// (Thai vowel signs, Devanagari matras in ASCII output) are left to convert as usual.
+func (e *Engine) unmappedMarks(text, fromScript, toScript, locale string, mappings map[runeKey]string, memo map[runeKey]*RuneResult) int {
+	length := 0
+	for length < len(text) {
+		r, size := utf8.DecodeRuneInString(text[length:])
+		if !unicode.Is(unicode.M, r) {
+			break
+		}
+
+		key := runeKey{r: r, script: fromScript}
+		result, ok := memo[key]
+		if !ok {
+			result = e.transliterateRune(r, fromScript, toScript, locale, mappings)
+			memo[key] = result
+		}
+		if !result.Unmapped {
+			break
+		}
+		length += size
+	}
+	return length
+}
+
+// keepsMarks reports whether unmapped combining marks stay on the output of the character they
+// follow: on a character left as written (q̃ in Latin output), and Latin accents on a converted
+// letter when diacritics are preserved. ASCII output drops them.
+func (e *Engine) keepsMarks(source, output, marks, toScript string) bool {
+	if marks == "" || toScript == "ascii" {
+		return false
+	}
+	if output == source {
+		return true
+	}
+	if !e.config.PreserveDiacritics || toScript != "latin" {
+		return false
+	}
+	for _, r := range marks {
+		// Combining Diacritical Marks, the accents Latin letters carry
+		if r < 0x0300 || r > 0x036F {
+			return false
+		}
+	}
+	return true
+}
+
+// withMarks puts kept marks on the first character of output, where they sat in the source
+func withMarks(output, marks string) string {
+	_, first := utf8.DecodeRuneInString(output)
+	return norm.NFC.String(output[:first] + marks + output[first:])
+}
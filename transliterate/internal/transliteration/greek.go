@@ -0,0 +1,139 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// latinGreekLetters maps Latin letters to Greek. Where several Greek letters share a Latin
+// spelling the plain one is the default: e ε and o ο (η and ω need ē and ō), i and j ι, u
+// and y υ, c, k and q κ, v β. A lone h marks rough breathing and is dropped.
+var latinGreekLetters = map[rune]rune{
+	'a': 'α', 'b': 'β', 'c': 'κ', 'd': 'δ', 'e': 'ε', 'ē': 'η', 'f': 'φ',
+	'g': 'γ', 'i': 'ι', 'j': 'ι', 'k': 'κ', 'l': 'λ', 'm': 'μ', 'n': 'ν',
+	'o': 'ο', 'ō': 'ω', 'p': 'π', 'q': 'κ', 'r': 'ρ', 's': 'σ', 't': 'τ',
+	'u': 'υ', 'v': 'β', 'x': 'ξ', 'y': 'υ', 'z': 'ζ',
+}
+
+// latinGreekDigraphs are the two-letter spellings of single Greek letters
+var latinGreekDigraphs = map[[2]rune]rune{
+	{'t', 'h'}: 'θ', {'p', 'h'}: 'φ', {'c', 'h'}: 'χ', {'k', 'h'}: 'χ', {'p', 's'}: 'ψ',
+}
+
+// transliterateLatinToGreek handles single Latin letters; digraphs, final sigma and h are
+// resolved with their neighbours by latinGreekDigraph and latinGreekContextual
+func (e *Engine) transliterateLatinToGreek(r rune) string {
+	greek, ok := latinGreekLetters[unicode.ToLower(r)]
+	if !ok {
+		return ""
+	}
+	if unicode.IsUpper(r) {
+		greek = unicode.ToUpper(greek)
+	}
+	return string(greek)
+}
+
+// latinGreekDigraph reads th, ph, ch, kh and ps as one Greek letter (Philosophia Φιλοσοφια),
+// taking its case from the first letter. A capital H passes its case to the following
+// letter (Homeros Ομερος).
+func (e *Engine) latinGreekDigraph(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "latin" || toScript != "greek" {
+		return nil, false
+	}
+
+	greek, ok := latinGreekDigraphs[[2]rune{unicode.ToLower(r), unicode.ToLower(next)}]
+	if !ok && r == 'H' {
+		greek, ok = latinGreekLetters[unicode.ToLower(next)]
+	}
+	if !ok {
+		return nil, false
+	}
+	if unicode.IsUpper(r) {
+		greek = unicode.ToUpper(greek)
+	}
+	return &RuneResult{Output: string(greek), Confidence: 0.85, Method: "builtin"}, true
+}
+
+// latinGreekContextual writes s as final sigma at the end of a word (Logos Λογος) and drops
+// an h that isn't part of a digraph
+func latinGreekContextual(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "latin" || toScript != "greek" {
+		return nil, false
+	}
+
+	switch {
+	case r == 's' && !unicode.IsLetter(next):
+		return &RuneResult{Output: "ς", Confidence: 0.85, Method: "builtin"}, true
+	case r == 'h' || r == 'H':
+		return &RuneResult{Output: "", Confidence: 0.7, Method: "builtin"}, true
+	}
+	return nil, false
+}
+
+// greekNasals are the letters before which γ is the nasal n (άγγελος angelos, σφίγξ sfinx).
+// Classical romanization also writes γκ as nk, where modern standards keep gk (Αγκυρα Ankyra,
+// Agkyra).
+var greekNasals = map[rune]bool{'γ': true, 'ξ': true, 'χ': true}
+
+// greekVoiceless are the letters before which ELOT 743 writes the υ of αυ, ευ and ηυ as f
+var greekVoiceless = map[rune]bool{
+	'θ': true, 'κ': true, 'ξ': true, 'π': true, 'σ': true, 'ς': true, 'τ': true, 'φ': true,
+	'χ': true, 'ψ': true,
+}
+
+// greekContextual resolves Greek letters that depend on their neighbours: a γ before γ, ξ
+// or χ is n, and under a selected standard υ after α, ε, η or ο is part of a diphthong (ου
+// ou; αυ av or af in ELOT 743, au in classical). A diaeresis (ϋ) or an accent on the first
+// vowel keeps the vowels apart.
+func (e *Engine) greekContextual(r, prev, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "greek" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	base, marks := greekBase(r)
+	nextBase, _ := greekBase(next)
+	standard := e.standardTable(fromScript) != nil
+	var output string
+	switch {
+	case base == 'γ' && (greekNasals[nextBase] || (nextBase == 'κ' && e.config.Standard == StandardClassical)):
+		output = "n"
+	case base == 'υ' && standard && !strings.ContainsRune(marks, '\u0308'):
+		prevBase, prevMarks := greekBase(prev)
+		if prevMarks != "" {
+			return nil, false
+		}
+		switch {
+		case prevBase == 'ο' || (e.config.Standard == StandardClassical && strings.ContainsRune("αεη", prevBase)):
+			output = "u"
+			if e.config.PreserveDiacritics && toScript == "latin" {
+				output = norm.NFC.String(output + marks)
+			}
+		case strings.ContainsRune("αεη", prevBase) && (greekVoiceless[nextBase] || !unicode.IsLetter(next)):
+			output = "f"
+		case strings.ContainsRune("αεη", prevBase):
+			output = "v"
+		default:
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	if unicode.IsUpper(r) {
+		output = capitalizeFirst(output)
+	}
+	if standard {
+		return &RuneResult{Output: output, Confidence: 0.95, Method: "standard"}, true
+	}
+	return &RuneResult{Output: output, Confidence: 0.85, Method: "builtin"}, true
+}
+
+// greekBase splits a Greek letter into its lower-case base letter and accents (ύ υ)
+func greekBase(r rune) (rune, string) {
+	decomposed := norm.NFD.String(string(unicode.ToLower(r)))
+	base, size := utf8.DecodeRuneInString(decomposed)
+	return base, decomposed[size:]
+}
@@ -0,0 +1,29 @@
+package transliteration
+
+import "unicode"
+
+// hebrewVowelLetter reads a vav between two other letters as the vowel o, as in unpointed
+// text it usually marks a vowel there rather than the consonant v (שלום shlom); a
+// word-initial or doubled vav stays v
+func hebrewVowelLetter(r, prev, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "hebrew" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+	if r != 'ו' || prev == 'ו' || next == 'ו' {
+		return nil, false
+	}
+	if !isHebrewLetter(prev) || !isHebrewLetter(next) {
+		return nil, false
+	}
+
+	return &RuneResult{
+		Output:     "o",
+		Confidence: 0.7,
+		Method:     "builtin",
+	}, true
+}
+
+// isHebrewLetter reports whether r is a Hebrew letter, excluding points and punctuation
+func isHebrewLetter(r rune) bool {
+	return unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r)
+}
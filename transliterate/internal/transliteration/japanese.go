@@ -0,0 +1,138 @@
+package transliteration
+
+import (
+	"strings"
+)
+
+// japaneseSmallY maps the small ya/yu/yo kana (hiragana and katakana) to their vowel glide
+var japaneseSmallY = map[rune]string{
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo",
+	'ャ': "ya", 'ュ': "yu", 'ョ': "yo",
+}
+
+// japaneseMacrons maps romaji vowels to their long, macron forms
+var japaneseMacrons = map[byte]string{'a': "ā", 'i': "ī", 'u': "ū", 'e': "ē", 'o': "ō"}
+
+// japaneseNameReadings maps common kanji family and given names to their kana readings
+var japaneseNameReadings = map[string]string{
+	// Family names
+	"佐藤": "さとう", "鈴木": "すずき", "高橋": "たかはし", "田中": "たなか",
+	"伊藤": "いとう", "渡辺": "わたなべ", "山本": "やまもと", "中村": "なかむら",
+	"小林": "こばやし", "加藤": "かとう", "吉田": "よしだ", "山田": "やまだ",
+	"佐々木": "ささき", "山口": "やまぐち", "松本": "まつもと", "井上": "いのうえ",
+	"木村": "きむら", "林": "はやし", "斎藤": "さいとう", "清水": "しみず",
+	"山崎": "やまざき", "森": "もり", "池田": "いけだ", "橋本": "はしもと",
+	"阿部": "あべ", "石川": "いしかわ", "前田": "まえだ", "藤田": "ふじた",
+
+	// Given names
+	"太郎": "たろう", "次郎": "じろう", "一郎": "いちろう", "健": "けん",
+	"翔": "しょう", "大輔": "だいすけ", "花子": "はなこ", "陽子": "ようこ",
+	"恵子": "けいこ", "美咲": "みさき", "愛": "あい",
+}
+
+// japaneseNameMaxLength is the longest entry in japaneseNameReadings, in runes
+const japaneseNameMaxLength = 3
+
+// writeKanjiNames writes a run of kanji, replacing known names (longest match first) with
+// their kana readings and recording where each reading starts
+func writeKanjiNames(result *strings.Builder, kanji []rune, readings map[int]bool) {
+	for i := 0; i < len(kanji); {
+		matched := false
+		for length := min(japaneseNameMaxLength, len(kanji)-i); length > 0; length-- {
+			if reading, ok := japaneseNameReadings[string(kanji[i:i+length])]; ok {
+				readings[result.Len()] = true
+				result.WriteString(reading)
+				i += length
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			result.WriteRune(kanji[i])
+			i++
+		}
+	}
+}
+
+// japaneseDigraph combines kana followed by a small ya/yu/yo into one syllable, e.g.
+// きゃ kya, しゃ sha, ちょ cho
+func (e *Engine) japaneseDigraph(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "japanese" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	glide, ok := japaneseSmallY[next]
+	if !ok {
+		return nil, false
+	}
+
+	base := e.transliterateJapanese(r)
+	if len(base) < 2 || !strings.HasSuffix(base, "i") {
+		return nil, false
+	}
+
+	// shi, chi and ji absorb the y: sha, chu, jo
+	stem := strings.TrimSuffix(base, "i")
+	if strings.HasSuffix(stem, "sh") || strings.HasSuffix(stem, "ch") || stem == "j" {
+		glide = glide[1:]
+	}
+
+	return &RuneResult{
+		Output:     stem + glide,
+		Confidence: 0.85,
+		Method:     "builtin",
+	}, true
+}
+
+// japaneseSokuon renders a small っ/ッ as the doubled initial consonant of the following
+// kana (がっこう gakkou, ニッポン nippon); ch becomes tch per Hepburn (まっちゃ matcha)
+func (e *Engine) japaneseSokuon(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "japanese" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+	if r != 'っ' && r != 'ッ' {
+		return nil, false
+	}
+
+	following := e.transliterateJapanese(next)
+	if following == "" || strings.ContainsAny(following[:1], "aiueon") {
+		return nil, false
+	}
+
+	consonant := following[:1]
+	if strings.HasPrefix(following, "ch") {
+		consonant = "t"
+	}
+
+	return &RuneResult{
+		Output:     consonant,
+		Confidence: 0.85,
+		Method:     "builtin",
+	}, true
+}
+
+// japaneseLongVowel lengthens a syllable followed by the prolonged sound mark ー
+// (トーキョー tookyoo). In macron style the vowel takes a macron instead (tōkyō), and
+// おう/うう after an o or u syllable are written as ō/ū too (がっこう gakkō).
+func japaneseLongVowel(syllable string, next rune, style, toScript string) (string, bool) {
+	if syllable == "" || (toScript != "latin" && toScript != "ascii") {
+		return syllable, false
+	}
+
+	vowel := syllable[len(syllable)-1]
+	macron, isVowel := japaneseMacrons[vowel]
+	if !isVowel {
+		return syllable, false
+	}
+
+	useMacron := style == LongVowelsMacron && toScript == "latin"
+	switch {
+	case next == 'ー' && useMacron:
+		return syllable[:len(syllable)-1] + macron, true
+	case next == 'ー':
+		return syllable + string(vowel), true
+	case useMacron && (next == 'う' || next == 'ウ') && (vowel == 'o' || vowel == 'u'):
+		return syllable[:len(syllable)-1] + macron, true
+	}
+	return syllable, false
+}
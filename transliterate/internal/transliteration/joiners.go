@@ -0,0 +1,81 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The zero-width joiners, which change how the letters around them are shaped
+const (
+	zeroWidthNonJoiner = '\u200C' // Keeps letters apart: a Persian morpheme break, an explicit Indic virama
+	zeroWidthJoiner    = '\u200D' // Joins letters: an Indic half form, the emoji of a sequence
+)
+
+// shapingScripts are the scripts whose letters joiners shape: the cursive Arabic-script
+// family and the Indic scripts with conjuncts
+var shapingScripts = []*unicode.RangeTable{
+	unicode.Arabic, unicode.Syriac, unicode.Nko, unicode.Mongolian,
+	unicode.Devanagari, unicode.Bengali, unicode.Gurmukhi, unicode.Gujarati, unicode.Oriya,
+	unicode.Tamil, unicode.Telugu, unicode.Kannada, unicode.Malayalam, unicode.Sinhala,
+}
+
+// isJoiner reports whether r is a zero-width joiner or non-joiner
+func isJoiner(r rune) bool {
+	return r == zeroWidthNonJoiner || r == zeroWidthJoiner
+}
+
+// shapesWithJoiners reports whether r is a letter or mark of a script joiners shape
+func shapesWithJoiners(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsMark(r)) && unicode.In(r, shapingScripts...)
+}
+
+// stripStrayJoiners removes joiners that shape nothing, such as one pasted between Latin
+// letters, which would otherwise become a "?" in ASCII output. A joiner is kept between
+// letters of a script it shapes, and a zero-width joiner between the emoji of a sequence.
+func stripStrayJoiners(text string) string {
+	if !strings.ContainsRune(text, zeroWidthNonJoiner) && !strings.ContainsRune(text, zeroWidthJoiner) {
+		return text
+	}
+
+	var result strings.Builder
+	result.Grow(len(text))
+	prev := rune(-1)
+	for i, r := range text {
+		if !isJoiner(r) {
+			result.WriteRune(r)
+			prev = r
+			continue
+		}
+		next, size := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+		switch {
+		case size == 0:
+			// Nothing follows to shape
+		case shapesWithJoiners(prev) && shapesWithJoiners(next):
+			result.WriteRune(r)
+		case r == zeroWidthJoiner && unicode.Is(unicode.So, next):
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// joinerReading converts a joiner after a letter it shapes. Beside text kept as written the
+// joiner is kept too, so the text still shapes the same; in a romanization a non-joiner in
+// Arabic script is the hyphen of a morpheme break (namh-ha for نامه and ها), and otherwise a
+// joiner has no sound and converts to nothing. Joiners of emoji sequences are left to the
+// emoji rules.
+func (e *Engine) joinerReading(r, prev rune, prevKept bool, fromScript, toScript string) (*RuneResult, bool) {
+	if !isJoiner(r) || !shapesWithJoiners(prev) {
+		return nil, false
+	}
+
+	output := ""
+	switch {
+	case prevKept:
+		output = string(r)
+	case r == zeroWidthNonJoiner && fromScript == "arabic" && (toScript == "latin" || toScript == "ascii"):
+		output = "-"
+	}
+	return &RuneResult{Output: output, Confidence: 1.0, Method: "builtin"}, true
+}
@@ -0,0 +1,50 @@
+package transliteration
+
+// kanaCombinations romanizes a kana followed by a small vowel, the spellings katakana uses
+// for sounds borrowed with foreign names (ファ fa, ティ ti, ウィ wi). Keys are written in
+// hiragana; katakana are folded onto them first.
+var kanaCombinations = map[string]string{
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "ふゅ": "fyu",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du", "てゅ": "tyu", "でゅ": "dyu",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo", "ゔゅ": "vyu",
+	"しぇ": "she", "じぇ": "je", "ちぇ": "che",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+	"いぇ": "ye", "すぃ": "si", "ずぃ": "zi",
+	"くぁ": "kwa", "くぃ": "kwi", "くぇ": "kwe", "くぉ": "kwo",
+	"ぐぁ": "gwa", "ぐぃ": "gwi", "ぐぇ": "gwe", "ぐぉ": "gwo",
+}
+
+// katakanaOnly romanizes the katakana without a hiragana counterpart
+var katakanaOnly = map[rune]string{
+	'ヷ': "va", 'ヸ': "vi", 'ヹ': "ve", 'ヺ': "vo",
+	'・': " ", // Separates the parts of a foreign name (ジョン・スミス)
+}
+
+// foldKatakana returns the hiragana sharing a katakana's reading, or r itself
+func foldKatakana(r rune) rune {
+	// Katakana share their readings with the hiragana 0x60 code points below
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 0x60
+	}
+	return r
+}
+
+// japaneseSmallVowel combines kana followed by a small vowel into one syllable
+// (ファ fa, ティ ti, ヴィ vi)
+func japaneseSmallVowel(r, next rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "japanese" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	syllable, ok := kanaCombinations[string([]rune{foldKatakana(r), foldKatakana(next)})]
+	if !ok {
+		return nil, false
+	}
+
+	return &RuneResult{
+		Output:     syllable,
+		Confidence: 0.85,
+		Method:     "builtin",
+	}, true
+}
@@ -0,0 +1,73 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Mongolian format characters that select positional letter forms
+const (
+	mongolianFVS1 = '\u180B' // Free variation selectors pick an alternate initial, medial or final form
+	mongolianFVS2 = '\u180C'
+	mongolianFVS3 = '\u180D'
+	mongolianMVS  = '\u180E' // Vowel separator before a detached final a or e
+	mongolianFVS4 = '\u180F'
+	narrowNBSP    = '\u202F' // Joins grammatical suffixes to their stem
+)
+
+// mongolianLetters romanizes Traditional Mongolian base letters. Unicode encodes each letter
+// once, so initial, medial and final forms all reach this table as the same code point.
+var mongolianLetters = map[rune]string{
+	// Vowels
+	'ᠠ': "a", 'ᠡ': "e", 'ᠢ': "i", 'ᠣ': "o", 'ᠤ': "u", 'ᠥ': "oe", 'ᠦ': "ue", 'ᠧ': "ee",
+
+	// Consonants
+	'ᠨ': "n", 'ᠩ': "ng", 'ᠪ': "b", 'ᠫ': "p", 'ᠬ': "kh", 'ᠭ': "g", 'ᠮ': "m",
+	'ᠯ': "l", 'ᠰ': "s", 'ᠱ': "sh", 'ᠲ': "t", 'ᠳ': "d", 'ᠴ': "ch", 'ᠵ': "j",
+	'ᠶ': "y", 'ᠷ': "r", 'ᠸ': "w",
+
+	// Letters for loanwords
+	'ᠹ': "f", 'ᠺ': "k", 'ᠻ': "kh", 'ᠼ': "ts", 'ᠽ': "z", 'ᠾ': "h", 'ᠿ': "zr",
+	'ᡀ': "lh", 'ᡁ': "zh", 'ᡂ': "ch",
+
+	// Punctuation
+	'᠂': ",", '᠃': ".", '᠄': ":",
+}
+
+// transliterateMongolian handles Traditional Mongolian to Latin conversion
+func (e *Engine) transliterateMongolian(r rune) string {
+	if r >= '᠐' && r <= '᠙' {
+		return string('0' + (r - '᠐'))
+	}
+	return mongolianLetters[r]
+}
+
+// normalizeMongolianForms collapses positional variants to their base letters by dropping
+// variation selectors and the vowel separator, and marks suffixes joined by a narrow
+// no-break space with a hyphen
+func normalizeMongolianForms(text string) string {
+	if !strings.ContainsFunc(text, isMongolian) {
+		return text
+	}
+
+	var result strings.Builder
+	var prev rune
+	for _, r := range text {
+		switch r {
+		case mongolianFVS1, mongolianFVS2, mongolianFVS3, mongolianFVS4, mongolianMVS:
+			continue
+		case narrowNBSP:
+			if isMongolian(prev) {
+				r = '-'
+			}
+		}
+		result.WriteRune(r)
+		prev = r
+	}
+	return result.String()
+}
+
+// isMongolian reports whether r is in the Mongolian block
+func isMongolian(r rune) bool {
+	return unicode.Is(unicode.Mongolian, r)
+}
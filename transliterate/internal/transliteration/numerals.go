@@ -0,0 +1,63 @@
+package transliteration
+
+// digitZeros are the zero digits of the decimal numeral systems likely in names, addresses
+// and identifiers; each system's digits 0-9 are consecutive code points from its zero
+var digitZeros = []rune{
+	'٠', // Arabic-Indic
+	'۰', // Extended Arabic-Indic (Persian, Urdu)
+	'०', // Devanagari
+	'০', // Bengali
+	'੦', // Gurmukhi
+	'૦', // Gujarati
+	'୦', // Oriya
+	'௦', // Tamil
+	'౦', // Telugu
+	'೦', // Kannada
+	'൦', // Malayalam
+	'๐', // Thai
+	'໐', // Lao
+	'༠', // Tibetan
+	'၀', // Myanmar
+	'០', // Khmer
+	'᠐', // Mongolian
+	'０', // Full-width
+}
+
+// currencyCodes are the ISO 4217 codes written for currency symbols in ASCII output
+var currencyCodes = map[rune]string{
+	'€': "EUR",
+	'£': "GBP",
+	'¥': "JPY",
+	'₹': "INR",
+	'₽': "RUB",
+	'₩': "KRW",
+	'₪': "ILS",
+	'₫': "VND",
+	'₺': "TRY",
+	'₴': "UAH",
+	'₱': "PHP",
+	'₦': "NGN",
+	'₸': "KZT",
+	'₮': "MNT",
+	'₼': "AZN",
+	'₾': "GEL",
+	'₡': "CRC",
+	'৳': "BDT",
+	'฿': "THB",
+	'¢': "c",
+	'＄': "$",
+	'￡': "GBP",
+	'￥': "JPY",
+	'￦': "KRW",
+}
+
+// asciiDigit returns the ASCII digit for a digit of another decimal numeral system
+// (١٢٣, १२३, １２３ are all 123)
+func asciiDigit(r rune) (string, bool) {
+	for _, zero := range digitZeros {
+		if r >= zero && r <= zero+9 {
+			return string('0' + (r - zero)), true
+		}
+	}
+	return "", false
+}
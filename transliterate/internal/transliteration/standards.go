@@ -0,0 +1,109 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Romanization standards selectable through Config.Standard
+const (
+	StandardBuckwalter = "buckwalter" // Arabic, 1:1 ASCII-safe and reversible
+	StandardELOT743    = "elot743"    // Modern Greek, the Greek national standard (η i, β v, φ f)
+	StandardClassical  = "classical"  // Ancient Greek, the Latin spelling of classical names (η ē, β b, φ ph)
+)
+
+// StandardScripts maps each romanization standard to the input script it applies to
+var StandardScripts = map[string]string{
+	StandardBuckwalter: "arabic",
+	StandardELOT743:    "greek",
+	StandardClassical:  "greek",
+}
+
+// buckwalterTable is the Buckwalter transliteration, including the extended letters
+var buckwalterTable = map[rune]string{
+	// Hamza and its carriers
+	'ء': "'", 'آ': "|", 'أ': ">", 'ؤ': "&", 'إ': "<", 'ئ': "}", 'ٱ': "{",
+
+	// Letters
+	'ا': "A", 'ب': "b", 'ة': "p", 'ت': "t", 'ث': "v", 'ج': "j", 'ح': "H",
+	'خ': "x", 'د': "d", 'ذ': "*", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "$",
+	'ص': "S", 'ض': "D", 'ط': "T", 'ظ': "Z", 'ع': "E", 'غ': "g", 'ف': "f",
+	'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n", 'ه': "h", 'و': "w",
+	'ى': "Y", 'ي': "y", 'ـ': "_",
+
+	// Diacritics
+	'ً': "F", 'ٌ': "N", 'ٍ': "K", 'َ': "a", 'ُ': "u", 'ِ': "i", 'ّ': "~",
+	'ْ': "o", 'ٰ': "`",
+
+	// Extended letters for Persian and Urdu loans
+	'پ': "P", 'چ': "J", 'ڤ': "V", 'گ': "G",
+}
+
+// buckwalterReverse maps Buckwalter ASCII back to Arabic
+var buckwalterReverse = func() map[rune]rune {
+	reverse := make(map[rune]rune, len(buckwalterTable))
+	for arabic, ascii := range buckwalterTable {
+		reverse[rune(ascii[0])] = arabic
+	}
+	return reverse
+}()
+
+// elot743Table is ELOT 743 (ISO 843 type 2) for single letters; υ in diphthongs and the
+// nasal γ are resolved with their neighbours by greekContextual
+var elot743Table = withCapitals(map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+})
+
+// classicalTable is the traditional romanization of Ancient Greek, with macrons marking
+// the long vowels η and ω
+var classicalTable = withCapitals(map[rune]string{
+	'α': "a", 'β': "b", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "ē", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "ph", 'χ': "ch", 'ψ': "ps",
+	'ω': "ō",
+})
+
+// withCapitals adds the capital of each lower-case letter, romanized with a capital initial
+func withCapitals(lower map[rune]string) map[rune]string {
+	table := make(map[rune]string, 2*len(lower))
+	for r, output := range lower {
+		table[r] = output
+		if upper := unicode.ToUpper(r); upper != r {
+			table[upper] = capitalizeFirst(output)
+		}
+	}
+	return table
+}
+
+// standardTable returns the mapping for the configured standard when it applies to fromScript
+func (e *Engine) standardTable(fromScript string) map[rune]string {
+	if e.config.Standard == "" || StandardScripts[e.config.Standard] != fromScript {
+		return nil
+	}
+
+	switch e.config.Standard {
+	case StandardBuckwalter:
+		return buckwalterTable
+	case StandardELOT743:
+		return elot743Table
+	case StandardClassical:
+		return classicalTable
+	}
+	return nil
+}
+
+// BuckwalterToArabic reverses a Buckwalter transliteration; other characters pass through
+func BuckwalterToArabic(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		if arabic, ok := buckwalterReverse[r]; ok {
+			result.WriteRune(arabic)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,108 @@
+package transliteration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// streamChunkSize is the largest piece of a stream converted at once, in bytes
+const streamChunkSize = 64 * 1024
+
+// TransliterateStream converts text read from r chunk by chunk and writes the output to w as
+// it goes, so documents need not fit in memory. Chunks end after whitespace where possible,
+// so rules that look at neighbouring letters see whole words. The returned Result has an
+// empty Output and a confidence averaged over all characters.
+func (e *Engine) TransliterateStream(ctx context.Context, w io.Writer, r io.Reader, fromScript, toScript, locale string) (*Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 2*streamChunkSize), 2*streamChunkSize)
+	scanner.Split(splitStreamChunks)
+
+	var notes []string
+	seenNotes := make(map[string]bool)
+	var confidenceSum float64
+	var charCount, queries, fallbacks int
+	var mappingErr error
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		chunk := scanner.Text()
+		result, err := e.TransliterateTo(ctx, w, chunk, fromScript, toScript, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		chars := utf8.RuneCountInString(chunk)
+		confidenceSum += result.Confidence * float64(chars)
+		charCount += chars
+		queries += result.Queries
+		fallbacks += result.Fallbacks
+		if mappingErr == nil {
+			mappingErr = result.MappingError
+		}
+		for _, note := range result.Notes {
+			if !seenNotes[note] {
+				seenNotes[note] = true
+				notes = append(notes, note)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	confidence := 1.0
+	if charCount > 0 {
+		confidence = confidenceSum / float64(charCount)
+	}
+	method := "mixed"
+	if len(notes) == 0 {
+		method = "builtin"
+	}
+
+	return &Result{Confidence: confidence, Notes: notes, Method: method, Queries: queries, Fallbacks: fallbacks, MappingError: mappingErr}, nil
+}
+
+// splitStreamChunks is a bufio.SplitFunc yielding chunks of at most streamChunkSize bytes
+// that end after the last whitespace, or failing that on a character boundary that does
+// not separate a letter from its combining marks
+func splitStreamChunks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+	if len(data) <= streamChunkSize {
+		if atEOF {
+			return len(data), data, nil
+		}
+		// Wait for more than a chunk, so the character after a cut is known
+		return 0, nil, nil
+	}
+
+	window := data[:streamChunkSize]
+	if i := bytes.LastIndexFunc(window, unicode.IsSpace); i >= 0 {
+		_, size := utf8.DecodeRune(window[i:])
+		return i + size, window[:i+size], nil
+	}
+
+	cut := len(window)
+	for cut > 0 {
+		if utf8.RuneStart(data[cut]) {
+			r, _ := utf8.DecodeRune(data[cut:])
+			if !unicode.Is(unicode.M, r) {
+				break
+			}
+		}
+		cut--
+	}
+	if cut == 0 {
+		// A single character with over 64 KB of combining marks; cut anywhere
+		cut = len(window)
+	}
+	return cut, window[:cut], nil
+}
@@ -0,0 +1,38 @@
+package transliteration
+
+// chineseSurnameReadings lists characters read differently when used as a surname,
+// e.g. 单 is usually dān but the surname is Shàn
+var chineseSurnameReadings = map[rune]string{
+	'单': "Shan", '單': "Shan",
+	'解': "Xie",
+	'仇': "Qiu",
+	'区': "Ou", '區': "Ou",
+	'查': "Zha",
+	'朴': "Piao",
+	'盖': "Ge", '蓋': "Ge",
+	'种': "Chong", '種': "Chong",
+	'覃': "Qin",
+	'召': "Shao",
+	'乐': "Yue", '樂': "Yue",
+	'员': "Yun", '員': "Yun",
+	'秘': "Bi",
+	'繁': "Po",
+}
+
+// surnameReading returns the surname-specific reading of r, if it has one
+func (e *Engine) surnameReading(r rune, fromScript, toScript string) (*RuneResult, bool) {
+	if fromScript != "chinese" || (toScript != "latin" && toScript != "ascii") {
+		return nil, false
+	}
+
+	reading, ok := chineseSurnameReadings[r]
+	if !ok {
+		return nil, false
+	}
+
+	return &RuneResult{
+		Output:     reading,
+		Confidence: 0.9,
+		Method:     "surname",
+	}, true
+}
@@ -0,0 +1,46 @@
+package transliteration
+
+import "unicode"
+
+// emojiRanges are the code points emoji are built from: pictographs and dingbats, and the
+// skin tone modifiers, joiner, presentation selector, keycap and tags that combine them
+// into sequences (👍🏽, 👩‍💻, 1️⃣, 🏴󠁧󠁢󠁳󠁣󠁴󠁿)
+var emojiRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x200D, Hi: 0x200D, Stride: 1}, // Zero width joiner
+		{Lo: 0x20E3, Hi: 0x20E3, Stride: 1}, // Combining enclosing keycap
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1}, // ⌚ ⌛
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1}, // ⌨
+		{Lo: 0x23CF, Hi: 0x23CF, Stride: 1}, // ⏏
+		{Lo: 0x23E9, Hi: 0x23F3, Stride: 1}, // ⏩ to ⏳
+		{Lo: 0x23F8, Hi: 0x23FA, Stride: 1}, // ⏸ ⏹ ⏺
+		{Lo: 0x2600, Hi: 0x27BF, Stride: 1}, // Miscellaneous Symbols and Dingbats (☀ ✓ ❤)
+		{Lo: 0x2B05, Hi: 0x2B07, Stride: 1}, // ⬅ ⬆ ⬇
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1}, // ⬛ ⬜
+		{Lo: 0x2B50, Hi: 0x2B50, Stride: 1}, // ⭐
+		{Lo: 0x2B55, Hi: 0x2B55, Stride: 1}, // ⭕
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1}, // 〰
+		{Lo: 0x303D, Hi: 0x303D, Stride: 1}, // 〽
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1}, // ㊗
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1}, // ㊙
+		{Lo: 0xFE0F, Hi: 0xFE0F, Stride: 1}, // Emoji presentation selector
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F000, Hi: 0x1FAFF, Stride: 1}, // Mahjong tiles to Symbols and Pictographs Extended-A, with flags and skin tones
+		{Lo: 0xE0020, Hi: 0xE007F, Stride: 1}, // Tags of subdivision flags
+	},
+}
+
+// isEmoji reports whether r is an emoji or one of the characters joining emoji into a sequence
+func isEmoji(r rune) bool {
+	return unicode.Is(emojiRanges, r)
+}
+
+// preservesSymbol reports whether r is copied to the output as written: emoji with
+// PreserveEmoji, and emoji and other symbols (© ™ €) with PreserveSymbols
+func (e *Engine) preservesSymbol(r rune) bool {
+	if e.config.PreserveSymbols && (unicode.IsSymbol(r) || isEmoji(r)) {
+		return true
+	}
+	return e.config.PreserveEmoji && isEmoji(r)
+}
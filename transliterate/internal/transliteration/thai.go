@@ -0,0 +1,56 @@
+package transliteration
+
+import (
+	"strings"
+)
+
+// thaiLeadingVowels are written before the consonant they are pronounced after
+var thaiLeadingVowels = map[rune]bool{'เ': true, 'แ': true, 'โ': true, 'ใ': true, 'ไ': true}
+
+// thaiSilentMarks are tone marks and the shortening mark, which RTGS romanization doesn't write
+var thaiSilentMarks = map[rune]bool{'่': true, '้': true, '๊': true, '๋': true, '็': true}
+
+// thaiClusterInitials can start a two-consonant cluster with ร, ล or ว (เพลง phleng, เกวียน kwian)
+var thaiClusterInitials = map[rune]bool{
+	'ก': true, 'ข': true, 'ค': true, 'ต': true, 'ป': true, 'ผ': true, 'พ': true,
+}
+
+// reorderThaiVowels moves each leading vowel after the consonant (or initial cluster) it
+// follows phonetically, so เก reads ke rather than ek, and drops silent tone marks
+func reorderThaiVowels(text string) string {
+	if !strings.ContainsFunc(text, isThai) {
+		return text
+	}
+
+	runes := []rune(text)
+	result := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if thaiSilentMarks[r] {
+			continue
+		}
+		if !thaiLeadingVowels[r] || i+1 >= len(runes) || !isThaiConsonant(runes[i+1]) {
+			result = append(result, r)
+			continue
+		}
+
+		end := i + 2
+		if end < len(runes) && thaiClusterInitials[runes[i+1]] && strings.ContainsRune("รลว", runes[end]) {
+			end++
+		}
+		result = append(result, runes[i+1:end]...)
+		result = append(result, r)
+		i = end - 1
+	}
+	return string(result)
+}
+
+// isThai reports whether r is in the Thai block
+func isThai(r rune) bool {
+	return r >= 0x0E00 && r <= 0x0E7F
+}
+
+// isThaiConsonant reports whether r is a Thai consonant letter (ก to ฮ)
+func isThaiConsonant(r rune) bool {
+	return r >= 'ก' && r <= 'ฮ'
+}
@@ -3,14 +3,21 @@
 package transliteration
 
 import (
+	"bufio"
 	"context"
-	"database/sql"
+	"io"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 	"errors"
+	"slices"
 
 	"github.com/mozillazg/go-unidecode"
 	"encore.dev/storage/sqldb"
+
+	"encore.app/transliterate/internal/detection"
+	"encore.app/transliterate/internal/numwords"
+	textnorm "encore.app/transliterate/internal/unicode"
 )
 
 // Config holds transliteration configuration
@@ -19,8 +26,31 @@ type Config struct {
 	FallbackToASCII bool
 	PreserveSpacing bool
 	CaseSensitive  bool
+	NumberWords    bool   // Convert spelled-out numbers (Chinese, Russian) to digits
+	BoundarySpacing string // Space insertion at script boundaries: "always", "never" or "smart"
+	Standard       string // Romanization standard, e.g. "buckwalter" (empty for the default scheme)
+	LongVowels     string // Japanese long vowel style: "doubled" or "macron"
+	PreserveDiacritics bool // Keep source accents on Latin output (έ é) instead of dropping them
+	UmlautExpansion bool   // Expand umlauts for ASCII output per German convention (ü ue) instead of folding them (ü u)
+	Trace          bool   // Record the segment behind each piece of output in Result.Segments
+	UnmappedPolicy string // Output for characters without a mapping: "question", "drop", "keep" or "unicode-name" (empty for "?" in ASCII output and the original character otherwise)
+	PreserveEmoji  bool   // Copy emoji to the output unchanged, even in ASCII output
+	PreserveSymbols bool  // Copy emoji and all other symbols (© ™ € →) unchanged, even in ASCII output
 }
 
+// Boundary spacing modes for mixed-script input
+const (
+	BoundarySpacingAlways = "always" // Space at every transition between letter scripts
+	BoundarySpacingNever  = "never"  // Concatenate output as-is
+	BoundarySpacingSmart  = "smart"  // Space only between CJK-derived and Latin tokens
+)
+
+// Japanese long vowel styles
+const (
+	LongVowelsDoubled = "doubled" // Repeat the vowel: tookyoo
+	LongVowelsMacron  = "macron"  // Mark the vowel: tōkyō (ASCII output stays doubled)
+)
+
 // DefaultConfig returns sensible defaults
 func DefaultConfig() Config {
 	return Config{
@@ -28,6 +58,9 @@ func DefaultConfig() Config {
 		FallbackToASCII: true,
 		PreserveSpacing: true,
 		CaseSensitive:  false,
+		BoundarySpacing: BoundarySpacingSmart,
+		LongVowels:     LongVowelsDoubled,
+		UmlautExpansion: true,
 	}
 }
 
@@ -36,9 +69,18 @@ type Result struct {
 	Output     string
 	Confidence float64
 	Notes      []string
-	Method     string // "database", "builtin", "fallback"
+	Method     string // "database", "builtin", "fallback"; "empty" when nothing was output
+	Segments   []Segment // Per-character provenance, only when Config.Trace is set
+	Alternatives []string // Other plausible outputs, most likely first, when the conversion had to guess
+	Queries    int       // Database queries made to look up character mappings
+	Fallbacks  int       // Characters approximated or left as written because no rule covers them
+	MappingError error   // Why the database mappings couldn't be read, when the built-in rules stood in for them
 }
 
+// emptyOutputConfidence is the highest confidence of a conversion whose every character
+// converts to nothing
+const emptyOutputConfidence = 0.2
+
 // Engine handles transliteration operations
 type Engine struct {
 	config Config
@@ -55,6 +97,42 @@ func NewEngine(config Config, db *sqldb.Database) *Engine {
 
 // Transliterate converts text from one script to another
 func (e *Engine) Transliterate(ctx context.Context, text, fromScript, toScript, locale string) (*Result, error) {
+	var output strings.Builder
+	output.Grow(len(text))
+
+	result, err := e.TransliterateTo(ctx, &output, text, fromScript, toScript, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Output = output.String()
+	return result, nil
+}
+
+// TransliterateName converts a name whose first surnameRunes letters are the family name,
+// so surnames with special readings (单 Shan, 解 Xie) romanize correctly
+func (e *Engine) TransliterateName(ctx context.Context, text, fromScript, toScript, locale string, surnameRunes int) (*Result, error) {
+	var output strings.Builder
+	output.Grow(len(text))
+
+	result, err := e.transliterate(ctx, &output, text, fromScript, toScript, locale, surnameRunes)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Output = output.String()
+	return result, nil
+}
+
+// TransliterateTo streams the converted text to w as it is produced, so large documents
+// need not be held in memory twice; the returned Result has an empty Output
+func (e *Engine) TransliterateTo(ctx context.Context, w io.Writer, text, fromScript, toScript, locale string) (*Result, error) {
+	return e.transliterate(ctx, w, text, fromScript, toScript, locale, 0)
+}
+
+// transliterate streams the conversion of text to w, applying surname readings to the
+// first surnameRunes letters
+func (e *Engine) transliterate(ctx context.Context, w io.Writer, text, fromScript, toScript, locale string, surnameRunes int) (*Result, error) {
 	if !utf8.ValidString(text) {
 		return nil, ErrInvalidUTF8
 	}
@@ -63,24 +141,218 @@ func (e *Engine) Transliterate(ctx context.Context, text, fromScript, toScript,
 		return &Result{Output: "", Confidence: 1.0, Method: "empty"}, nil
 	}
 
-	var result strings.Builder
+	// Decomposed input converts exactly like its precomposed form
+	text = textnorm.ComposeNFC(text)
+
+	// Romanized Vietnamese gets its diacritics back word by word rather than letter by letter
+	if restoresVietnamese(fromScript, toScript) {
+		return e.restoreVietnamese(w, text)
+	}
+
+	if e.config.NumberWords {
+		text = numwords.Replace(text)
+	}
+
+	// Mongolian positional variant selectors carry no sound of their own
+	text = normalizeMongolianForms(text)
+
+	// Joiners pasted where they shape nothing would otherwise be unmapped characters
+	text = stripStrayJoiners(text)
+
+	// Thai leading vowels are written before the consonant they follow
+	text = reorderThaiVowels(text)
+
+	// Furigana and known kanji names are read as kana, each starting a capitalized word
+	var readings map[int]bool
+	if toScript == "latin" || toScript == "ascii" {
+		text, readings = replaceReadings(text, fromScript == "japanese")
+	}
+
+	out := bufio.NewWriter(w)
 	var notes []string
+	seenNotes := make(map[string]bool)
 	var confidenceSum float64
-	var charCount int
+	var charCount, letterCount, written int
+	prevFamily := ""
+	prevUpper := false
+	prevKept := false
+	var prevRune rune
+	var segments []Segment
 
-	// Process character by character
-	for _, r := range text {
-		charResult, err := e.transliterateRune(ctx, r, fromScript, toScript, locale)
-		if err != nil {
-			return nil, err
-		}
+	// Repeated characters are resolved once per call rather than once per occurrence
+	memo := make(map[runeKey]*RuneResult)
+
+	runs := SplitRuns(text, fromScript)
+	mappings, queries, mappingErr, err := e.prefetchMappings(ctx, runs, toScript, locale)
+	if err != nil {
+		return nil, err
+	}
+	fallbacks := 0
+
+	// Convert mixed-script text run by run, each with its own script's rules
+	offset := 0
+	for _, run := range runs {
+		for i := 0; i < len(run.Text); {
+			r, size := utf8.DecodeRuneInString(run.Text[i:])
+			readingStart := readings[offset]
+
+			// Insert a space where adjacent letters switch script family
+			if unicode.IsLetter(r) {
+				family := scriptFamily(detection.ClassifyRune(r))
+				if e.needsBoundarySpace(prevFamily, family) {
+					out.WriteString(" ")
+					written++
+					if e.config.Trace {
+						segments = append(segments, Segment{Output: " ", Method: "boundary", Confidence: 1.0})
+					}
+				}
+				prevFamily = family
+			} else {
+				prevFamily = ""
+			}
 
-		result.WriteString(charResult.Output)
-		if charResult.Note != "" {
-			notes = append(notes, charResult.Note)
+			key := runeKey{r: r, script: run.Script}
+			charResult, ok := memo[key]
+			inSurname := letterCount < surnameRunes && unicode.IsLetter(r)
+			if inSurname {
+				// Surname readings depend on position, so they bypass the memo
+				charResult, ok = e.surnameReading(r, key.script, toScript)
+			} else if word, wordSize, found := e.chineseWordReading(run.Text[i:], key.script, toScript); found {
+				// Polyphonic characters take the reading of the word they are in (银行 Yinhang)
+				charResult, ok = word, true
+				size = wordSize
+			}
+			// Armenian word-initial glides and Western readings depend on position and locale
+			if contextual, found := e.armenianContextual(r, prevRune, key.script, toScript, locale); found {
+				charResult, ok = contextual, true
+			}
+			// Latin s ending a word is a final sigma; a lone h is rough breathing
+			following, _ := utf8.DecodeRuneInString(run.Text[i+size:])
+			if contextual, found := latinGreekContextual(r, following, key.script, toScript); found {
+				charResult, ok = contextual, true
+			}
+			// Cyrillic е is iotated after vowels and signs; ь and ъ are silent or a glide
+			if contextual, found := cyrillicContextual(r, prevRune, following, key.script, toScript, locale); found {
+				charResult, ok = contextual, true
+			}
+			// Greek γ before γ, ξ or χ is nasal; υ after a vowel may form a diphthong
+			if contextual, found := e.greekContextual(r, prevRune, following, key.script, toScript); found {
+				charResult, ok = contextual, true
+			}
+			if next, nextSize := utf8.DecodeRuneInString(run.Text[i+size:]); nextSize > 0 {
+				// Kana followed by a small ゃ/ゅ/ょ form one syllable (きゃ kya)
+				if digraph, found := e.japaneseDigraph(r, next, key.script, toScript); found {
+					charResult, ok = digraph, true
+					size += nextSize
+				}
+				// Katakana followed by a small vowel spell foreign sounds (ファ fa, ティ ti)
+				if combined, found := japaneseSmallVowel(r, next, key.script, toScript); found {
+					charResult, ok = combined, true
+					size += nextSize
+				}
+				// A small っ doubles the next consonant (がっこう gakkou)
+				if sokuon, found := e.japaneseSokuon(r, next, key.script, toScript); found {
+					charResult, ok = sokuon, true
+				}
+				// Armenian ու is the single vowel u
+				if digraph, found := e.armenianDigraph(r, next, key.script, toScript); found {
+					charResult, ok = digraph, true
+					size += nextSize
+				}
+				// th, ph, ch and ps are single Greek letters
+				if digraph, found := e.latinGreekDigraph(r, next, key.script, toScript); found {
+					charResult, ok = digraph, true
+					size += nextSize
+				}
+				// A vav between consonants is a vowel (שלום shlom)
+				if vowel, found := hebrewVowelLetter(r, prevRune, next, key.script, toScript); found {
+					charResult, ok = vowel, true
+				}
+			}
+			// A joiner only shapes the letters around it
+			if joiner, found := e.joinerReading(r, prevRune, prevKept, key.script, toScript); found {
+				charResult, ok = joiner, true
+			}
+			// Anything but the character's own mapping depends on where it stands
+			contextual := ok && charResult != memo[key]
+			if !ok {
+				charResult = e.transliterateRune(r, key.script, toScript, locale, mappings)
+				if !inSurname {
+					memo[key] = charResult
+				}
+			}
+
+			// The character and the combining marks after it form one grapheme cluster; marks
+			// without a mapping of their own are accents on it, not characters to convert
+			marksSize := e.unmappedMarks(run.Text[i+size:], key.script, toScript, locale, mappings, memo)
+			marks := run.Text[i+size : i+size+marksSize]
+			keepMarks := e.keepsMarks(run.Text[i:i+size], charResult.Output, marks, toScript)
+			confidence := charResult.Confidence
+			if marks != "" && toScript == "ascii" {
+				// Folding away an accent is an approximation, as for precomposed letters (é e)
+				confidence = min(confidence, asciiApproximationConfidence)
+			}
+			size += marksSize
+
+			output := charResult.Output
+			next, _ := utf8.DecodeRuneInString(run.Text[i+size:])
+			output = applySourceCase(output, r, inCapsWord(next, prevUpper))
+			if keepMarks {
+				output = withMarks(output, marks)
+			}
+			if unicode.IsLetter(r) {
+				prevUpper = unicode.IsUpper(r)
+			} else {
+				prevUpper = false
+			}
+			if key.script == "japanese" {
+				// A following ー (or おう in macron style) lengthens the syllable's vowel
+				if next, nextSize := utf8.DecodeRuneInString(run.Text[i+size:]); nextSize > 0 {
+					if long, found := japaneseLongVowel(output, next, e.config.LongVowels, toScript); found {
+						output = long
+						size += nextSize
+					}
+				}
+			}
+			if readingStart {
+				output = capitalizeFirst(output)
+			}
+			n, err := out.WriteString(output)
+			if err != nil {
+				return nil, err
+			}
+			written += n
+			if e.config.Trace {
+				segments = append(segments, Segment{
+					Source:     run.Text[i : i+size],
+					Script:     run.Script,
+					Output:     output,
+					Method:     charResult.Method,
+					Confidence: confidence,
+					Contextual: contextual,
+				})
+			}
+			if charResult.Note != "" && !seenNotes[charResult.Note] {
+				seenNotes[charResult.Note] = true
+				notes = append(notes, charResult.Note)
+			}
+			if charResult.Method == "fallback" || charResult.Unmapped {
+				fallbacks++
+			}
+			confidenceSum += confidence
+			charCount++
+			if unicode.IsLetter(r) {
+				letterCount += utf8.RuneCountInString(run.Text[i : i+size])
+			}
+			prevRune = r
+			prevKept = output != "" && output == run.Text[i:i+size]
+			i += size
+			offset += size
 		}
-		confidenceSum += charResult.Confidence
-		charCount++
+	}
+
+	if err := out.Flush(); err != nil {
+		return nil, err
 	}
 
 	// Calculate average confidence
@@ -95,34 +367,211 @@ func (e *Engine) Transliterate(ctx context.Context, text, fromScript, toScript,
 		method = "builtin"
 	}
 
+	// Text made only of characters that convert to nothing (Cyrillic ъ and ь) converts
+	// successfully to empty output, which says little about the input
+	if written == 0 && charCount > 0 {
+		confidence = min(confidence, emptyOutputConfidence)
+		notes = append(notes, "Every character converts to nothing; the output is empty")
+		method = "empty"
+	}
+
 	return &Result{
-		Output:     result.String(),
 		Confidence: confidence,
 		Notes:      notes,
 		Method:     method,
+		Segments:   segments,
+		Queries:    queries,
+		Fallbacks:  fallbacks,
+		MappingError: mappingErr,
 	}, nil
 }
 
+// Segment is the output produced for a single source character, or for the few characters
+// a digraph or long vowel consumes together
+type Segment struct {
+	Source     string  // Source character(s); empty for an inserted boundary space
+	Script     string  // Script whose rules converted it
+	Output     string  // Converted text, possibly empty
+	Method     string  // "passthrough", "standard", "database", "builtin", "surname", "fallback", "unchanged" or "boundary"
+	Confidence float64 // Confidence of this segment alone
+	Contextual bool    // Output depends on neighbouring characters or the word (っ, е after a vowel, 银行), not the character alone
+}
+
+// Segments converts text as Transliterate does and returns the segment behind each piece of
+// its output, so callers can align the output back to its source characters
+func (e *Engine) Segments(ctx context.Context, text, fromScript, toScript, locale string) ([]Segment, error) {
+	traced := *e
+	traced.config.Trace = true
+
+	result, err := traced.TransliterateTo(ctx, io.Discard, text, fromScript, toScript, locale)
+	if err != nil {
+		return nil, err
+	}
+	return result.Segments, nil
+}
+
+// Run is a stretch of text converted with a single script's rules
+type Run struct {
+	Script string // Script whose rules apply to the run
+	Text   string // Text of the run, a substring of the input
+}
+
+// SplitRuns groups consecutive letters by script; characters without a script (spaces,
+// digits, punctuation) stay with the run they follow
+func SplitRuns(text, fromScript string) []Run {
+	var runs []Run
+	script := fromScript
+	start := 0
+
+	for i, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letterScript := runeScript(r, fromScript)
+		if letterScript == script {
+			continue
+		}
+		if i > start {
+			runs = append(runs, Run{Script: script, Text: text[start:i]})
+		}
+		script = letterScript
+		start = i
+	}
+
+	if start < len(text) {
+		runs = append(runs, Run{Script: script, Text: text[start:]})
+	}
+	return runs
+}
+
+// runeKey identifies a memoized rune conversion
+type runeKey struct {
+	r      rune
+	script string
+}
+
+// needsBoundarySpace reports whether a space belongs between letters of two script families
+func (e *Engine) needsBoundarySpace(prevFamily, family string) bool {
+	if prevFamily == "" || family == "" || prevFamily == family {
+		return false
+	}
+
+	switch e.config.BoundarySpacing {
+	case BoundarySpacingAlways:
+		return true
+	case BoundarySpacingSmart:
+		return (prevFamily == "cjk" && family == "latin") || (prevFamily == "latin" && family == "cjk")
+	default:
+		return false
+	}
+}
+
+// scriptFamily groups detected scripts for boundary spacing decisions
+func scriptFamily(script string) string {
+	switch script {
+	case "latin", "german", "vietnamese":
+		return "latin"
+	case "chinese", "japanese", "korean":
+		return "cjk"
+	case "unknown":
+		return ""
+	default:
+		return script
+	}
+}
+
+// runeScript picks the rules to apply for a rune embedded in text of another script
+func runeScript(r rune, fromScript string) string {
+	if !unicode.IsLetter(r) {
+		return fromScript
+	}
+
+	script := detection.ClassifyRune(r)
+	switch {
+	case script == "unknown" || script == fromScript:
+		return fromScript
+	case scriptFamily(script) == "latin" && scriptFamily(fromScript) == "latin":
+		return fromScript
+	case script == "chinese" && fromScript == "japanese":
+		// Kanji inside Japanese text follow Japanese rules
+		return fromScript
+	case scriptFamily(script) == "latin":
+		return "latin"
+	default:
+		return script
+	}
+}
+
 // RuneResult represents the result of transliterating a single rune
 type RuneResult struct {
 	Output     string
 	Confidence float64
 	Note       string
 	Method     string
+	Unmapped   bool // No mapping was found; Output follows the unmapped policy
 }
 
-// transliterateRune converts a single rune
-func (e *Engine) transliterateRune(ctx context.Context, r rune, fromScript, toScript, locale string) (*RuneResult, error) {
+// transliterateRune converts a single rune, consulting the database mappings prefetched for
+// the text before the built-in rules
+func (e *Engine) transliterateRune(r rune, fromScript, toScript, locale string, mappings map[runeKey]string) *RuneResult {
 	sourceChar := string(r)
 
-	// Try database lookup first
-	if e.config.UseDatabase {
-		if dbResult, err := e.lookupInDatabase(ctx, sourceChar, fromScript, toScript, locale); err == nil && dbResult != "" {
+	// ASCII (spaces, digits, punctuation, Latin letters in mixed text) needs no conversion;
+	// only letters change when the output is another script
+	if passesThrough(r, toScript) {
+		return &RuneResult{
+			Output:     sourceChar,
+			Confidence: 1.0,
+			Method:     "passthrough",
+		}
+	}
+
+	// Emoji and symbols the caller keeps are copied as written, even into ASCII output
+	if e.preservesSymbol(r) {
+		return &RuneResult{
+			Output:     sourceChar,
+			Confidence: 1.0,
+			Method:     "passthrough",
+		}
+	}
+
+	// Digits of other numeral systems (١٢٣, १२३, １２３) are the same numbers in ASCII
+	if toScript == "latin" || toScript == "ascii" {
+		if digit, ok := asciiDigit(r); ok {
 			return &RuneResult{
-				Output:     dbResult,
-				Confidence: 0.95,
-				Method:     "database",
-			}, nil
+				Output:     digit,
+				Confidence: 1.0,
+				Method:     "builtin",
+			}
+		}
+	}
+
+	// An explicitly selected standard takes precedence over learned database mappings
+	table := e.standardTable(fromScript)
+	if output, ok := table[r]; ok {
+		if toScript == "ascii" {
+			// Macrons of the classical Greek standard (ē ō)
+			output = stripMarks(output)
+		}
+		return &RuneResult{
+			Output:     output,
+			Confidence: 0.95,
+			Method:     "standard",
+		}
+	}
+
+	// Serbian and Macedonian locales select their own national scheme (ј j, not й y)
+	if national, ok := cyrillicNational(r, fromScript, toScript, locale); ok {
+		return national
+	}
+
+	// Try database mappings first
+	if dbResult := mappings[runeKey{r: r, script: fromScript}]; dbResult != "" {
+		return &RuneResult{
+			Output:     dbResult,
+			Confidence: 0.95,
+			Method:     "database",
 		}
 	}
 
@@ -132,65 +581,134 @@ func (e *Engine) transliterateRune(ctx context.Context, r rune, fromScript, toSc
 			Output:     builtinResult,
 			Confidence: 0.85,
 			Method:     "builtin",
-		}, nil
+		}
+	}
+
+	// Accented letters without a rule of their own convert through their base letter
+	if decomposed, ok := e.decomposedRune(r, fromScript, toScript); ok {
+		return decomposed
 	}
 
 	// Fallback to ASCII approximation
 	if e.config.FallbackToASCII && toScript == "ascii" {
 		asciiResult := e.approximateToASCII(r)
-		confidence := 0.3
-		note := ""
-		if asciiResult == "?" {
-			note = "Unknown character approximated"
-			confidence = 0.1
+		if asciiResult != sourceChar {
+			return &RuneResult{
+				Output:     asciiResult,
+				Confidence: asciiApproximationConfidence,
+				Method:     "fallback",
+			}
 		}
+
+		// Nothing to approximate it with
 		return &RuneResult{
-			Output:     asciiResult,
-			Confidence: confidence,
-			Note:       note,
+			Output:     e.unmappedOutput(r, toScript),
+			Confidence: 0.1,
+			Note:       "Unknown character approximated",
 			Method:     "fallback",
-		}, nil
+			Unmapped:   true,
+		}
 	}
 
-	// Keep original character as last resort
+	// No mapping; by default the original character is kept
 	return &RuneResult{
-		Output:     sourceChar,
+		Output:     e.unmappedOutput(r, toScript),
 		Confidence: 0.1,
 		Note:       "Character unchanged",
 		Method:     "unchanged",
-	}, nil
+		Unmapped:   true,
+	}
 }
 
-// lookupInDatabase performs database lookup for character mapping
-func (e *Engine) lookupInDatabase(ctx context.Context, sourceChar, fromScript, toScript, locale string) (string, error) {
-	var targetChar string
-	
-	err := e.db.QueryRow(ctx, `
-		SELECT target_char 
-		FROM character_mappings 
-		WHERE source_char = $1 
-			AND source_script = $2 
-			AND target_script = $3 
+// passesThrough reports whether r is copied to the output unchanged: ASCII needs no
+// conversion, except for letters when the output is another script
+func passesThrough(r rune, toScript string) bool {
+	return r <= unicode.MaxASCII && (toScript == "latin" || toScript == "ascii" || !unicode.IsLetter(r))
+}
+
+// prefetchMappings loads the database mappings for every distinct character of the runs in a
+// single query, so a conversion costs one round trip however long the text is. A failed
+// lookup falls back to the built-in rules unless the request itself was cancelled, and is
+// returned as unavailable for the caller to report. It also returns the number of queries made.
+func (e *Engine) prefetchMappings(ctx context.Context, runs []Run, toScript, locale string) (mappings map[runeKey]string, queries int, unavailable, err error) {
+	if !e.config.UseDatabase || e.db == nil {
+		return nil, 0, nil, nil
+	}
+
+	wanted := make(map[runeKey]bool)
+	var chars, scripts []string
+	for _, run := range runs {
+		// An explicitly selected standard takes precedence over learned database mappings
+		if e.standardTable(run.Script) != nil {
+			continue
+		}
+		if !slices.Contains(scripts, run.Script) {
+			scripts = append(scripts, run.Script)
+		}
+		for _, r := range run.Text {
+			key := runeKey{r: r, script: run.Script}
+			if passesThrough(r, toScript) || wanted[key] {
+				continue
+			}
+			wanted[key] = true
+			chars = append(chars, string(r))
+		}
+	}
+	if len(chars) == 0 {
+		return nil, 0, nil, nil
+	}
+
+	mappings, err = e.lookupMappings(ctx, chars, scripts, toScript, locale, wanted)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 1, nil, ctxErr
+		}
+		return nil, 1, err, nil
+	}
+	return mappings, 1, nil, nil
+}
+
+// lookupMappings reads the preferred mapping for each wanted character, favouring the
+// locale's own mapping and then the most frequent one
+func (e *Engine) lookupMappings(ctx context.Context, chars, scripts []string, toScript, locale string, wanted map[runeKey]bool) (map[runeKey]string, error) {
+	rows, err := e.db.Query(ctx, `
+		SELECT DISTINCT ON (source_char, source_script) source_char, source_script, target_char
+		FROM character_mappings
+		WHERE source_char = ANY($1)
+			AND source_script = ANY($2)
+			AND target_script = $3
 			AND ($4::text IS NULL OR locale = $4 OR locale IS NULL)
-		ORDER BY 
+		ORDER BY
+			source_char, source_script,
 			CASE WHEN locale = $4 THEN 1 ELSE 2 END,
 			frequency_weight DESC
-		LIMIT 1
-	`, sourceChar, fromScript, toScript, locale).Scan(&targetChar)
-
-	if err == sql.ErrNoRows {
-		return "", nil
-	}
+	`, chars, scripts, toScript, locale)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer rows.Close()
 
-	return targetChar, nil
+	mappings := make(map[runeKey]string)
+	for rows.Next() {
+		var sourceChar, sourceScript, targetChar string
+		if err := rows.Scan(&sourceChar, &sourceScript, &targetChar); err != nil {
+			return nil, err
+		}
+		r, _ := utf8.DecodeRuneInString(sourceChar)
+		if key := (runeKey{r: r, script: sourceScript}); wanted[key] {
+			mappings[key] = targetChar
+		}
+	}
+	return mappings, rows.Err()
 }
 
 // applyBuiltinRules applies hardcoded transliteration rules
 func (e *Engine) applyBuiltinRules(r rune, fromScript, toScript string) string {
 	switch fromScript {
+	case "latin":
+		if toScript == "greek" {
+			return e.transliterateLatinToGreek(r)
+		}
 	case "cyrillic":
 		if toScript == "latin" || toScript == "ascii" {
 			return e.transliterateCyrillic(r)
@@ -223,136 +741,171 @@ func (e *Engine) applyBuiltinRules(r rune, fromScript, toScript string) string {
 		if toScript == "latin" || toScript == "ascii" {
 			return e.transliterateThai(r)
 		}
+	case "mongolian":
+		if toScript == "latin" || toScript == "ascii" {
+			return e.transliterateMongolian(r)
+		}
+	case "armenian":
+		if toScript == "latin" || toScript == "ascii" {
+			return e.transliterateArmenian(r)
+		}
+	case "ethiopic":
+		if toScript == "latin" || toScript == "ascii" {
+			return e.transliterateEthiopic(r)
+		}
 	}
 	return ""
 }
 
+// cyrillicLetters romanizes Russian Cyrillic letters, with ASCII defaults for the Serbian and
+// Macedonian letters when no locale selects their national scheme
+var cyrillicLetters = map[rune]string{
+	// Uppercase
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo",
+	'Ж': "Zh", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
+	'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
+	'Ф': "F", 'Х': "Kh", 'Ц': "Ts", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch",
+	'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu", 'Я': "Ya",
+	
+	// Lowercase
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+
+	// Serbian and Macedonian
+	'Ђ': "Dj", 'Ј': "J", 'Љ': "Lj", 'Њ': "Nj", 'Ћ': "C", 'Џ': "Dz",
+	'Ѓ': "Gj", 'Ѕ': "Dz", 'Ќ': "Kj",
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+	'ѓ': "gj", 'ѕ': "dz", 'ќ': "kj",
+}
+
 // transliterateCyrillic handles Cyrillic to Latin conversion
 func (e *Engine) transliterateCyrillic(r rune) string {
-	mapping := map[rune]string{
-		// Uppercase
-		'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo",
-		'Ж': "Zh", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
-		'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
-		'Ф': "F", 'Х': "Kh", 'Ц': "Ts", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch",
-		'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu", 'Я': "Ya",
-		
-		// Lowercase
-		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
-		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
-		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
-		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
-		'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
-	}
+	return cyrillicLetters[r]
+}
+
+// chineseReadings romanizes common Chinese characters
+var chineseReadings = map[rune]string{
+	// Numbers
+	'一': "Yi", '二': "Er", '三': "San", '四': "Si", '五': "Wu",
+	'六': "Liu", '七': "Qi", '八': "Ba", '九': "Jiu", '十': "Shi",
+	
+	// Common surnames
+	'李': "Li", '王': "Wang", '张': "Zhang", '刘': "Liu", '陈': "Chen",
+	'杨': "Yang", '赵': "Zhao", '黄': "Huang", '周': "Zhou", '吴': "Wu",
+	'徐': "Xu", '孙': "Sun", '胡': "Hu", '朱': "Zhu", '高': "Gao",
+	'林': "Lin", '何': "He", '郭': "Guo", '马': "Ma", '罗': "Luo",
+	'梁': "Liang", '宋': "Song", '郑': "Zheng", '谢': "Xie", '韩': "Han",
+	'唐': "Tang", '冯': "Feng", '于': "Yu", '董': "Dong", '萧': "Xiao",
+	'程': "Cheng", '曹': "Cao", '袁': "Yuan", '邓': "Deng", '许': "Xu",
+	'傅': "Fu", '沈': "Shen", '曾': "Zeng", '彭': "Peng", '吕': "Lu",
 	
-	return mapping[r]
+	// Common given names
+	'小': "Xiao", '大': "Da", '中': "Zhong", '文': "Wen", '明': "Ming",
+	'华': "Hua", '建': "Jian", '国': "Guo", '民': "Min", '伟': "Wei",
+	'龍': "Long", '龙': "Long", '凤': "Feng", '鳳': "Feng", '玉': "Yu",
+	'金': "Jin", '春': "Chun", '红': "Hong", '军': "Jun", '强': "Qiang",
+	'云': "Yun", '平': "Ping", '志': "Zhi", '刚': "Gang", '勇': "Yong",
+	'磊': "Lei", '娜': "Na", '静': "Jing", '丽': "Li", '敏': "Min",
+	'秀': "Xiu", '英': "Ying", '芳': "Fang", '燕': "Yan", '雪': "Xue",
+	'琴': "Qin", '梅': "Mei", '莉': "Li", '兰': "Lan", '翠': "Cui",
+	
+	// Common words
+	'你': "ni", '好': "hao", '是': "shi", '的': "de", '我': "wo",
+	'他': "ta", '她': "ta", '们': "men", '有': "you", '在': "zai",
+	'了': "le", '不': "bu", '就': "jiu", '人': "ren", '都': "dou",
+	
+	// Directions
+	'东': "Dong", '南': "Nan", '西': "Xi", '北': "Bei",
+	'上': "Shang", '下': "Xia", '左': "Zuo", '右': "You",
+	'前': "Qian", '后': "Hou",
+	
+	// Time/descriptors
+	'新': "Xin", '老': "Lao", '长': "Chang", '短': "Duan",
+	'低': "Di", '快': "Kuai", '慢': "Man",
+	'早': "Zao", '晚': "Wan",
+	
+	// Common readings of characters with distinct surname readings
+	'单': "Dan", '單': "Dan", '解': "Jie", '仇': "Chou", '区': "Qu",
+	'區': "Qu", '查': "Cha", '朴': "Pu", '盖': "Gai", '蓋': "Gai",
+	'种': "Zhong", '種': "Zhong", '覃': "Tan", '召': "Zhao", '乐': "Le",
+	'樂': "Le", '员': "Yuan", '員': "Yuan", '秘': "Mi", '繁': "Fan",
 }
 
 // transliterateChinese handles Chinese to Latin conversion
 func (e *Engine) transliterateChinese(r rune) string {
-	// Comprehensive mapping for common Chinese characters
-	mapping := map[rune]string{
-		// Numbers
-		'一': "Yi", '二': "Er", '三': "San", '四': "Si", '五': "Wu",
-		'六': "Liu", '七': "Qi", '八': "Ba", '九': "Jiu", '十': "Shi",
-		
-		// Common surnames
-		'李': "Li", '王': "Wang", '张': "Zhang", '刘': "Liu", '陈': "Chen",
-		'杨': "Yang", '赵': "Zhao", '黄': "Huang", '周': "Zhou", '吴': "Wu",
-		'徐': "Xu", '孙': "Sun", '胡': "Hu", '朱': "Zhu", '高': "Gao",
-		'林': "Lin", '何': "He", '郭': "Guo", '马': "Ma", '罗': "Luo",
-		'梁': "Liang", '宋': "Song", '郑': "Zheng", '谢': "Xie", '韩': "Han",
-		'唐': "Tang", '冯': "Feng", '于': "Yu", '董': "Dong", '萧': "Xiao",
-		'程': "Cheng", '曹': "Cao", '袁': "Yuan", '邓': "Deng", '许': "Xu",
-		'傅': "Fu", '沈': "Shen", '曾': "Zeng", '彭': "Peng", '吕': "Lu",
-		
-		// Common given names
-		'小': "Xiao", '大': "Da", '中': "Zhong", '文': "Wen", '明': "Ming",
-		'华': "Hua", '建': "Jian", '国': "Guo", '民': "Min", '伟': "Wei",
-		'龍': "Long", '龙': "Long", '凤': "Feng", '鳳': "Feng", '玉': "Yu",
-		'金': "Jin", '春': "Chun", '红': "Hong", '军': "Jun", '强': "Qiang",
-		'云': "Yun", '平': "Ping", '志': "Zhi", '刚': "Gang", '勇': "Yong",
-		'磊': "Lei", '娜': "Na", '静': "Jing", '丽': "Li", '敏': "Min",
-		'秀': "Xiu", '英': "Ying", '芳': "Fang", '燕': "Yan", '雪': "Xue",
-		'琴': "Qin", '梅': "Mei", '莉': "Li", '兰': "Lan", '翠': "Cui",
-		
-		// Common words
-		'你': "ni", '好': "hao", '是': "shi", '的': "de", '我': "wo",
-		'他': "ta", '她': "ta", '们': "men", '有': "you", '在': "zai",
-		'了': "le", '不': "bu", '就': "jiu", '人': "ren", '都': "dou",
-		
-		// Directions
-		'东': "Dong", '南': "Nan", '西': "Xi", '北': "Bei",
-		'上': "Shang", '下': "Xia", '左': "Zuo", '右': "You",
-		'前': "Qian", '后': "Hou",
-		
-		// Time/descriptors
-		'新': "Xin", '老': "Lao", '长': "Chang", '短': "Duan",
-		'低': "Di", '快': "Kuai", '慢': "Man",
-		'早': "Zao", '晚': "Wan",
-	}
+	return chineseReadings[r]
+}
+
+// arabicLetters romanizes Arabic letters
+var arabicLetters = map[rune]string{
+	'ا': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h",
+	'خ': "kh", 'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s",
+	'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z", 'ع': "'",
+	'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m",
+	'ن': "n", 'ه': "h", 'و': "w", 'ي': "y",
 	
-	return mapping[r]
+	// Additional Arabic letters
+	'ء': "'", 'آ': "aa", 'أ': "a", 'إ': "i", 'ؤ': "u", 'ئ': "i",
+	'ة': "h", 'ى': "a",
 }
 
 // transliterateArabic handles Arabic to Latin conversion
 func (e *Engine) transliterateArabic(r rune) string {
-	mapping := map[rune]string{
-		'ا': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h",
-		'خ': "kh", 'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s",
-		'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z", 'ع': "'",
-		'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m",
-		'ن': "n", 'ه': "h", 'و': "w", 'ي': "y",
-		
-		// Additional Arabic letters
-		'ء': "'", 'آ': "aa", 'أ': "a", 'إ': "i", 'ؤ': "u", 'ئ': "i",
-		'ة': "h", 'ى': "a",
-	}
+	return arabicLetters[r]
+}
+
+// greekLetters romanizes Greek letters
+var greekLetters = map[rune]string{
+	// Uppercase
+	'Α': "A", 'Β': "B", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z",
+	'Η': "H", 'Θ': "Th", 'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M",
+	'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P", 'Ρ': "R", 'Σ': "S",
+	'Τ': "T", 'Υ': "Y", 'Φ': "Ph", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
 	
-	return mapping[r]
+	// Lowercase
+	'α': "a", 'β': "b", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z",
+	'η': "h", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m",
+	'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s",
+	'τ': "t", 'υ': "y", 'φ': "ph", 'χ': "ch", 'ψ': "ps", 'ω': "o",
 }
 
 // transliterateGreek handles Greek to Latin conversion
 func (e *Engine) transliterateGreek(r rune) string {
-	mapping := map[rune]string{
-		// Uppercase
-		'Α': "A", 'Β': "B", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z",
-		'Η': "H", 'Θ': "Th", 'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M",
-		'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P", 'Ρ': "R", 'Σ': "S",
-		'Τ': "T", 'Υ': "Y", 'Φ': "Ph", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
-		
-		// Lowercase
-		'α': "a", 'β': "b", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z",
-		'η': "h", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m",
-		'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s",
-		'τ': "t", 'υ': "y", 'φ': "ph", 'χ': "ch", 'ψ': "ps", 'ω': "o",
-	}
-	
-	return mapping[r]
+	return greekLetters[r]
+}
+
+// hiraganaSyllables romanizes hiragana; katakana are folded onto it first
+var hiraganaSyllables = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'を': "wo", 'ん': "n",
+	'ゔ': "vu",
+
+	// Small vowels, when they do not combine with the kana before them
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o", 'ゎ': "wa",
 }
 
 // transliterateJapanese handles Japanese to Latin conversion (basic)
 func (e *Engine) transliterateJapanese(r rune) string {
-	// Basic Hiragana mappings
-	hiragana := map[rune]string{
-		'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
-		'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
-		'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
-		'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
-		'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
-		'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
-		'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
-		'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
-		'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
-		'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
-		'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
-		'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
-		'や': "ya", 'ゆ': "yu", 'よ': "yo",
-		'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
-		'わ': "wa", 'を': "wo", 'ん': "n",
+	if output, ok := katakanaOnly[r]; ok {
+		return output
 	}
-	
-	return hiragana[r]
+	return hiraganaSyllables[foldKatakana(r)]
 }
 
 // transliterateKorean handles Korean to Latin conversion (basic)
@@ -362,35 +915,96 @@ func (e *Engine) transliterateKorean(r rune) string {
 	return ""
 }
 
+// hebrewLetters romanizes Hebrew consonants
+var hebrewLetters = map[rune]string{
+	'א': "'", 'ב': "b", 'ג': "g", 'ד': "d", 'ה': "h", 'ו': "v",
+	'ז': "z", 'ח': "ch", 'ט': "t", 'י': "y", 'כ': "kh", 'ל': "l",
+	'מ': "m", 'נ': "n", 'ס': "s", 'ע': "'", 'פ': "p", 'צ': "ts",
+	'ק': "q", 'ר': "r", 'ש': "sh", 'ת': "t",
+
+	// Final forms read as their non-final letters
+	'ך': "kh", 'ם': "m", 'ן': "n", 'ף': "p", 'ץ': "ts",
+}
+
 // transliterateHebrew handles Hebrew to Latin conversion
 func (e *Engine) transliterateHebrew(r rune) string {
-	mapping := map[rune]string{
-		'א': "'", 'ב': "b", 'ג': "g", 'ד': "d", 'ה': "h", 'ו': "v",
-		'ז': "z", 'ח': "ch", 'ט': "t", 'י': "y", 'כ': "kh", 'ל': "l",
-		'מ': "m", 'נ': "n", 'ס': "s", 'ע': "'", 'פ': "p", 'צ': "ts",
-		'ק': "q", 'ר': "r", 'ש': "sh", 'ת': "t",
-	}
+	return hebrewLetters[r]
+}
+
+// thaiLetters romanizes basic Thai consonants and vowels
+var thaiLetters = map[rune]string{
+	'ก': "k", 'ข': "kh", 'ค': "kh", 'ง': "ng", 'จ': "ch", 'ฉ': "ch",
+	'ช': "ch", 'ซ': "s", 'ญ': "y", 'ด': "d", 'ต': "t", 'ถ': "th",
+	'ท': "th", 'น': "n", 'บ': "b", 'ป': "p", 'ผ': "ph", 'ฝ': "f",
+	'พ': "ph", 'ฟ': "f", 'ภ': "ph", 'ม': "m", 'ย': "y", 'ร': "r",
+	'ล': "l", 'ว': "w", 'ศ': "s", 'ษ': "s", 'ส': "s", 'ห': "h",
+	'อ': "'", 'ฮ': "h",
 	
-	return mapping[r]
+	// Vowels
+	'า': "a", 'ั': "a", 'ิ': "i", 'ี': "i", 'ึ': "ue", 'ื': "ue", 'ุ': "u", 'ู': "u",
+	'เ': "e", 'แ': "ae", 'โ': "o", 'ใ': "ai", 'ไ': "ai",
 }
 
 // transliterateThai handles Thai to Latin conversion
 func (e *Engine) transliterateThai(r rune) string {
-	// Basic Thai consonants
-	mapping := map[rune]string{
-		'ก': "k", 'ข': "kh", 'ค': "kh", 'ง': "ng", 'จ': "j", 'ฉ': "ch",
-		'ช': "ch", 'ซ': "s", 'ญ': "y", 'ด': "d", 'ต': "t", 'ถ': "th",
-		'ท': "th", 'น': "n", 'บ': "b", 'ป': "p", 'ผ': "ph", 'ฝ': "f",
-		'พ': "ph", 'ฟ': "f", 'ภ': "ph", 'ม': "m", 'ย': "y", 'ร': "r",
-		'ล': "l", 'ว': "w", 'ศ': "s", 'ษ': "s", 'ส': "s", 'ห': "h",
-		'อ': "'", 'ฮ': "h",
-		
-		// Vowels
-		'า': "a", 'ิ': "i", 'ี': "i", 'ึ': "ue", 'ื': "ue", 'ุ': "u", 'ู': "u",
-		'เ': "e", 'แ': "ae", 'โ': "o", 'ใ': "ai", 'ไ': "ai",
-	}
+	return thaiLetters[r]
+}
+
+// asciiApproximations folds common accented Latin letters to ASCII
+var asciiApproximations = map[rune]string{
+	// Basic accented vowels (non-Vietnamese and non-Germanic)
+	'â': "a",
+	'ê': "e", 'ë': "e",
+	'î': "i", 'ï': "i",
+	'ô': "o",
+	'û': "u",
+	'ŷ': "y", 'ÿ': "y",
+	
+	// Vietnamese diacritics - comprehensive mapping
+	'ă': "a", 'Ă': "A", 'đ': "d", 'Đ': "D",
+	'ư': "u", 'Ư': "U", 'ơ': "o", 'Ơ': "O",
+	
+	// Vietnamese tone marks on A
+	'à': "a", 'À': "A", 'á': "a", 'Á': "A", 'ả': "a", 'Ả': "A",
+	'ã': "a", 'Ã': "A", 'ạ': "a", 'Ạ': "A",
+	'ầ': "a", 'Ầ': "A", 'ấ': "a", 'Ấ': "A", 'ẩ': "a", 'Ẩ': "A",
+	'ẫ': "a", 'Ẫ': "A", 'ậ': "a", 'Ậ': "A",
+	'ằ': "a", 'Ằ': "A", 'ắ': "a", 'Ắ': "A", 'ẳ': "a", 'Ẳ': "A",
+	'ẵ': "a", 'Ẵ': "A", 'ặ': "a", 'Ặ': "A",
 	
-	return mapping[r]
+	// Vietnamese tone marks on E
+	'è': "e", 'È': "E", 'é': "e", 'É': "E", 'ẻ': "e", 'Ẻ': "E",
+	'ẽ': "e", 'Ẽ': "E", 'ẹ': "e", 'Ẹ': "E",
+	'ề': "e", 'Ề': "E", 'ế': "e", 'Ế': "E", 'ể': "e", 'Ể': "E",
+	'ễ': "e", 'Ễ': "E", 'ệ': "e", 'Ệ': "E",
+	
+	// Vietnamese tone marks on I
+	'ì': "i", 'Ì': "I", 'í': "i", 'Í': "I", 'ỉ': "i", 'Ỉ': "I",
+	'ĩ': "i", 'Ĩ': "I", 'ị': "i", 'Ị': "I",
+	
+	// Vietnamese tone marks on O
+	'ò': "o", 'Ò': "O", 'ó': "o", 'Ó': "O", 'ỏ': "o", 'Ỏ': "O",
+	'õ': "o", 'Õ': "O", 'ọ': "o", 'Ọ': "O",
+	'ồ': "o", 'Ồ': "O", 'ố': "o", 'Ố': "O", 'ổ': "o", 'Ổ': "O",
+	'ỗ': "o", 'Ỗ': "O", 'ộ': "o", 'Ộ': "O",
+	'ờ': "o", 'Ờ': "O", 'ớ': "o", 'Ớ': "O", 'ở': "o", 'Ở': "O",
+	'ỡ': "o", 'Ỡ': "O", 'ợ': "o", 'Ợ': "O",
+	
+	// Vietnamese tone marks on U
+	'ù': "u", 'Ù': "U", 'ú': "u", 'Ú': "U", 'ủ': "u", 'Ủ': "U",
+	'ũ': "u", 'Ũ': "U", 'ụ': "u", 'Ụ': "U",
+	'ừ': "u", 'Ừ': "U", 'ứ': "u", 'Ứ': "U", 'ử': "u", 'Ử': "U",
+	'ữ': "u", 'Ữ': "U", 'ự': "u", 'Ự': "U",
+	
+	// Vietnamese tone marks on Y
+	'ỳ': "y", 'Ỳ': "Y", 'ý': "y", 'Ý': "Y", 'ỷ': "y", 'Ỷ': "Y",
+	'ỹ': "y", 'Ỹ': "Y", 'ỵ': "y", 'Ỵ': "Y",
+	
+	// Other common characters
+	'ç': "c", 'Ç': "C", 'ñ': "n", 'Ñ': "N", 'ß': "ss",
+	
+	// Scandinavian
+	'å': "aa", 'Å': "AA", 'ø': "oe", 'Ø': "OE", 'æ': "ae", 'Æ': "AE",
 }
 
 // approximateToASCII provides fallback ASCII approximation
@@ -403,72 +1017,25 @@ func (e *Engine) approximateToASCII(r rune) string {
 	// Use our Unicode normalization for ASCII conversion
 	// This is a simplified version - would integrate with unicode package
 	
-	// Common approximations
-	approximations := map[rune]string{
-		// Basic accented vowels (non-Vietnamese and non-Germanic)
-		'â': "a",
-		'ê': "e", 'ë': "e",
-		'î': "i", 'ï': "i",
-		'ô': "o",
-		'û': "u",
-		'ŷ': "y", 'ÿ': "y",
-		
-		// Vietnamese diacritics - comprehensive mapping
-		'ă': "a", 'Ă': "A", 'đ': "d", 'Đ': "D",
-		'ư': "u", 'Ư': "U", 'ơ': "o", 'Ơ': "O",
-		
-		// Vietnamese tone marks on A
-		'à': "a", 'À': "A", 'á': "a", 'Á': "A", 'ả': "a", 'Ả': "A",
-		'ã': "a", 'Ã': "A", 'ạ': "a", 'Ạ': "A",
-		'ầ': "a", 'Ầ': "A", 'ấ': "a", 'Ấ': "A", 'ẩ': "a", 'Ẩ': "A",
-		'ẫ': "a", 'Ẫ': "A", 'ậ': "a", 'Ậ': "A",
-		'ằ': "a", 'Ằ': "A", 'ắ': "a", 'Ắ': "A", 'ẳ': "a", 'Ẳ': "A",
-		'ẵ': "a", 'Ẵ': "A", 'ặ': "a", 'Ặ': "A",
-		
-		// Vietnamese tone marks on E
-		'è': "e", 'È': "E", 'é': "e", 'É': "E", 'ẻ': "e", 'Ẻ': "E",
-		'ẽ': "e", 'Ẽ': "E", 'ẹ': "e", 'Ẹ': "E",
-		'ề': "e", 'Ề': "E", 'ế': "e", 'Ế': "E", 'ể': "e", 'Ể': "E",
-		'ễ': "e", 'Ễ': "E", 'ệ': "e", 'Ệ': "E",
-		
-		// Vietnamese tone marks on I
-		'ì': "i", 'Ì': "I", 'í': "i", 'Í': "I", 'ỉ': "i", 'Ỉ': "I",
-		'ĩ': "i", 'Ĩ': "I", 'ị': "i", 'Ị': "I",
-		
-		// Vietnamese tone marks on O
-		'ò': "o", 'Ò': "O", 'ó': "o", 'Ó': "O", 'ỏ': "o", 'Ỏ': "O",
-		'õ': "o", 'Õ': "O", 'ọ': "o", 'Ọ': "O",
-		'ồ': "o", 'Ồ': "O", 'ố': "o", 'Ố': "O", 'ổ': "o", 'Ổ': "O",
-		'ỗ': "o", 'Ỗ': "O", 'ộ': "o", 'Ộ': "O",
-		'ờ': "o", 'Ờ': "O", 'ớ': "o", 'Ớ': "O", 'ở': "o", 'Ở': "O",
-		'ỡ': "o", 'Ỡ': "O", 'ợ': "o", 'Ợ': "O",
-		
-		// Vietnamese tone marks on U
-		'ù': "u", 'Ù': "U", 'ú': "u", 'Ú': "U", 'ủ': "u", 'Ủ': "U",
-		'ũ': "u", 'Ũ': "U", 'ụ': "u", 'Ụ': "U",
-		'ừ': "u", 'Ừ': "U", 'ứ': "u", 'Ứ': "U", 'ử': "u", 'Ử': "U",
-		'ữ': "u", 'Ữ': "U", 'ự': "u", 'Ự': "U",
-		
-		// Vietnamese tone marks on Y
-		'ỳ': "y", 'Ỳ': "Y", 'ý': "y", 'Ý': "Y", 'ỷ': "y", 'Ỷ': "Y",
-		'ỹ': "y", 'Ỹ': "Y", 'ỵ': "y", 'Ỵ': "Y",
-		
-		// Other common characters
-		'ç': "c", 'Ç': "C", 'ñ': "n", 'Ñ': "N", 'ß': "ss",
-		
-		// German umlauts
-		'ä': "ae", 'Ä': "AE", 'ö': "oe", 'Ö': "OE", 'ü': "ue", 'Ü': "UE",
-		
-		// Scandinavian
-		'å': "aa", 'Å': "AA", 'ø': "oe", 'Ø': "OE", 'æ': "ae", 'Æ': "AE",
+	if umlaut, ok := textnorm.UmlautToASCII(r, e.config.UmlautExpansion); ok {
+		return umlaut
 	}
-	
-	if approx, exists := approximations[r]; exists {
+
+	if approx, exists := asciiApproximations[r]; exists {
 		return approx
 	}
+
+	// Currency symbols become their ISO codes (€100 EUR100) rather than unidecode's guesses (£ PS)
+	if code, exists := currencyCodes[r]; exists {
+		return code
+	}
 	
 	// Use unidecode for comprehensive Unicode to ASCII conversion
 	ascii := unidecode.Unidecode(string(r))
+	// unidecode pads CJK syllables with a trailing space; spacing is decided by the caller
+	if trimmed := strings.TrimSpace(ascii); trimmed != "" {
+		ascii = trimmed
+	}
 	if ascii == "" {
 		// If unidecode returns empty, try to get the base character
 		return string(r)
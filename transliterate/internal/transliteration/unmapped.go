@@ -0,0 +1,58 @@
+package transliteration
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/runenames"
+)
+
+// Policies for characters without a mapping
+const (
+	UnmappedQuestion    = "question"     // Replace with "?" (the default for ASCII output)
+	UnmappedDrop        = "drop"         // Leave out of the output
+	UnmappedKeep        = "keep"         // Copy the original character (the default otherwise)
+	UnmappedUnicodeName = "unicode-name" // Replace with the character's Unicode name in brackets
+)
+
+// DefaultUnmappedPolicy returns the policy used when none is configured: ASCII output must
+// stay ASCII, so it gets "?", while other scripts keep the original character
+func DefaultUnmappedPolicy(toScript string) string {
+	if toScript == "ascii" {
+		return UnmappedQuestion
+	}
+	return UnmappedKeep
+}
+
+// unmappedOutput writes a character that has no mapping according to the configured policy
+func (e *Engine) unmappedOutput(r rune, toScript string) string {
+	policy := e.config.UnmappedPolicy
+	if policy == "" {
+		policy = DefaultUnmappedPolicy(toScript)
+	}
+
+	switch policy {
+	case UnmappedQuestion:
+		return "?"
+	case UnmappedDrop:
+		return ""
+	case UnmappedUnicodeName:
+		return "[" + unicodeName(r) + "]"
+	default:
+		return string(r)
+	}
+}
+
+// unicodeName returns the Unicode name of r. Ideographs are named by code point
+// (CJK UNIFIED IDEOGRAPH-2A6A5); characters with no name of their own get U+XXXX.
+func unicodeName(r rune) string {
+	name := runenames.Name(r)
+	switch {
+	case strings.HasPrefix(name, "<CJK Ideograph"):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", r)
+	case name == "" || strings.HasPrefix(name, "<"):
+		return fmt.Sprintf("U+%04X", r)
+	default:
+		return name
+	}
+}
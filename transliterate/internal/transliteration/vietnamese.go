@@ -0,0 +1,229 @@
+package transliteration
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	textnorm "encore.app/transliterate/internal/unicode"
+)
+
+// vietnameseForm is one diacritical spelling of a stripped Vietnamese word
+type vietnameseForm struct {
+	Word   string  // Spelling with diacritics, lowercase
+	Weight float64 // Relative frequency among the word's spellings in names
+}
+
+// vietnameseWords maps stripped name words to their likely spellings, most frequent first.
+// Restoration is a best guess: the same stripped word can be several different names.
+var vietnameseWords = map[string][]vietnameseForm{
+	// Family names
+	"nguyen": {{"nguyễn", 1.0}},
+	"tran":   {{"trần", 1.0}},
+	"le":     {{"lê", 0.95}, {"lệ", 0.05}},
+	"pham":   {{"phạm", 1.0}},
+	"hoang":  {{"hoàng", 1.0}},
+	"huynh":  {{"huỳnh", 1.0}},
+	"phan":   {{"phan", 1.0}},
+	"vu":     {{"vũ", 0.9}, {"vư", 0.1}},
+	"vo":     {{"võ", 1.0}},
+	"dang":   {{"đặng", 0.7}, {"đăng", 0.3}},
+	"bui":    {{"bùi", 1.0}},
+	"do":     {{"đỗ", 0.9}, {"đồ", 0.1}},
+	"ho":     {{"hồ", 1.0}},
+	"ngo":    {{"ngô", 1.0}},
+	"duong":  {{"dương", 1.0}},
+	"ly":     {{"lý", 0.9}, {"ly", 0.1}},
+	"dinh":   {{"đinh", 0.8}, {"định", 0.2}},
+	"dao":    {{"đào", 1.0}},
+	"trinh":  {{"trịnh", 0.7}, {"trinh", 0.3}},
+	"truong": {{"trương", 0.7}, {"trường", 0.3}},
+
+	// Middle names and gender markers
+	"van":   {{"văn", 0.8}, {"vân", 0.2}},
+	"thi":   {{"thị", 0.9}, {"thi", 0.1}},
+	"duc":   {{"đức", 1.0}},
+	"huu":   {{"hữu", 1.0}},
+	"quoc":  {{"quốc", 1.0}},
+	"ngoc":  {{"ngọc", 1.0}},
+	"xuan":  {{"xuân", 1.0}},
+	"hong":  {{"hồng", 0.8}, {"hông", 0.2}},
+	"thanh": {{"thanh", 0.6}, {"thành", 0.4}},
+	"kim":   {{"kim", 1.0}},
+	"minh":  {{"minh", 1.0}},
+
+	// Given names
+	"anh":    {{"anh", 0.7}, {"ánh", 0.3}},
+	"binh":   {{"bình", 1.0}},
+	"cuong":  {{"cường", 1.0}},
+	"dung":   {{"dũng", 0.55}, {"dung", 0.45}},
+	"ha":     {{"hà", 0.8}, {"hạ", 0.2}},
+	"hai":    {{"hải", 1.0}},
+	"hanh":   {{"hạnh", 0.6}, {"hành", 0.4}},
+	"hien":   {{"hiền", 0.7}, {"hiến", 0.3}},
+	"hieu":   {{"hiếu", 1.0}},
+	"hoa":    {{"hoa", 0.6}, {"hòa", 0.4}},
+	"hung":   {{"hùng", 0.6}, {"hưng", 0.4}},
+	"huong":  {{"hương", 1.0}},
+	"khanh":  {{"khánh", 1.0}},
+	"lan":    {{"lan", 1.0}},
+	"linh":   {{"linh", 1.0}},
+	"loan":   {{"loan", 1.0}},
+	"long":   {{"long", 1.0}},
+	"mai":    {{"mai", 1.0}},
+	"my":     {{"my", 0.5}, {"mỹ", 0.5}},
+	"nam":    {{"nam", 1.0}},
+	"phuc":   {{"phúc", 1.0}},
+	"phuong": {{"phương", 1.0}},
+	"quang":  {{"quang", 1.0}},
+	"son":    {{"sơn", 1.0}},
+	"tam":    {{"tâm", 0.8}, {"tám", 0.2}},
+	"thang":  {{"thắng", 1.0}},
+	"thao":   {{"thảo", 1.0}},
+	"thu":    {{"thu", 0.6}, {"thư", 0.4}},
+	"tien":   {{"tiến", 0.6}, {"tiên", 0.4}},
+	"trang":  {{"trang", 1.0}},
+	"tuan":   {{"tuấn", 1.0}},
+	"vinh":   {{"vinh", 0.7}, {"vĩnh", 0.3}},
+	"yen":    {{"yến", 0.7}, {"yên", 0.3}},
+}
+
+// Confidence of restored diacritics; even an unambiguous table entry is only a guess
+const (
+	vietnameseRestoredConfidence = 0.5 // Scaled by the chosen spelling's weight
+	vietnameseUnknownConfidence  = 0.1 // Words not in the table are left as written
+)
+
+// vietnameseNote flags every restoration as a best guess
+const vietnameseNote = "Vietnamese diacritics restored from a name frequency table; verify before use"
+
+// restoresVietnamese reports whether the conversion adds diacritics to romanized Vietnamese
+func restoresVietnamese(fromScript, toScript string) bool {
+	return toScript == "vietnamese" && (fromScript == "latin" || fromScript == "ascii")
+}
+
+// vietnameseWord is a word of the input with its candidate spellings, in source case
+type vietnameseWord struct {
+	Source string
+	Forms  []string
+}
+
+// splitVietnameseWords breaks text into words and the separators between them; separators
+// and words not in the table have no alternative forms
+func splitVietnameseWords(text string) []vietnameseWord {
+	var words []vietnameseWord
+	start := 0
+	for start < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[start:])
+		isLetter := unicode.IsLetter(r)
+		end := start
+		for end < len(text) {
+			next, size := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsLetter(next) != isLetter {
+				break
+			}
+			end += size
+		}
+
+		word := vietnameseWord{Source: text[start:end]}
+		if isLetter {
+			for _, form := range vietnameseWords[strings.ToLower(word.Source)] {
+				word.Forms = append(word.Forms, matchVietnameseCase(form.Word, word.Source))
+			}
+		}
+		words = append(words, word)
+		start = end
+	}
+	return words
+}
+
+// matchVietnameseCase writes a restored spelling in the case of the source word
+func matchVietnameseCase(form, source string) string {
+	switch {
+	case source == strings.ToUpper(source):
+		return strings.ToUpper(form)
+	case unicode.IsUpper([]rune(source)[0]):
+		return capitalizeFirst(form)
+	default:
+		return form
+	}
+}
+
+// restoreVietnamese writes text with the most likely diacritics restored, returning the
+// confidence and the alternatives obtained by changing one ambiguous word at a time
+func (e *Engine) restoreVietnamese(w io.Writer, text string) (*Result, error) {
+	words := splitVietnameseWords(text)
+
+	var output strings.Builder
+	var segments []Segment
+	var confidenceSum float64
+	var wordCount int
+	for _, word := range words {
+		chosen, confidence, method := word.Source, 1.0, "passthrough"
+		if len(word.Forms) > 0 {
+			chosen = word.Forms[0]
+			confidence = vietnameseRestoredConfidence * vietnameseWords[strings.ToLower(word.Source)][0].Weight
+			method = "dictionary"
+		} else if unicode.IsLetter([]rune(word.Source)[0]) {
+			confidence, method = vietnameseUnknownConfidence, "unchanged"
+		}
+		if method != "passthrough" {
+			confidenceSum += confidence
+			wordCount++
+		}
+		output.WriteString(chosen)
+		if e.config.Trace {
+			segments = append(segments, Segment{Source: word.Source, Script: "latin", Output: chosen, Method: method, Confidence: confidence})
+		}
+	}
+
+	var candidates []Alternative
+	for i, word := range words {
+		forms := vietnameseWords[strings.ToLower(word.Source)]
+		for k, form := range word.Forms[min(1, len(word.Forms)):] {
+			var spelling strings.Builder
+			for j, other := range words {
+				switch {
+				case j == i:
+					spelling.WriteString(form)
+				case len(other.Forms) > 0:
+					spelling.WriteString(other.Forms[0])
+				default:
+					spelling.WriteString(other.Source)
+				}
+			}
+			candidates = append(candidates, Alternative{Text: spelling.String(), Weight: forms[k+1].Weight})
+		}
+	}
+	alternatives := RankAlternatives(output.String(), candidates)
+
+	if _, err := io.WriteString(w, output.String()); err != nil {
+		return nil, err
+	}
+
+	confidence := 1.0
+	if wordCount > 0 {
+		confidence = confidenceSum / float64(wordCount)
+	}
+	return &Result{
+		Confidence:   confidence,
+		Notes:        []string{vietnameseNote},
+		Method:       "dictionary",
+		Segments:     segments,
+		Alternatives: alternatives,
+	}, nil
+}
+
+// GuessedAlternatives returns other plausible outputs for text, most likely first, for
+// conversions that have to guess; only Vietnamese diacritic restoration currently does
+func (e *Engine) GuessedAlternatives(text, fromScript, toScript string) []string {
+	if !restoresVietnamese(fromScript, toScript) {
+		return nil
+	}
+	result, err := e.restoreVietnamese(io.Discard, textnorm.ComposeNFC(text))
+	if err != nil {
+		return nil
+	}
+	return result.Alternatives
+}
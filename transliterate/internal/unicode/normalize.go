@@ -54,9 +54,9 @@ func NormalizeText(text string, opts NormalizeOptions) (string, error) {
 		transformations = append(transformations, runes.Map(unicode.ToLower))
 	}
 
-	// ASCII conversion if requested
+	// ASCII conversion if requested; it maps precomposed letters (ü ue), so recompose first
 	if opts.ASCIIOnly {
-		transformations = append(transformations, NewASCIITransformer())
+		transformations = append(transformations, norm.NFC, NewASCIITransformer())
 	}
 
 	// Apply all transformations
@@ -72,6 +72,12 @@ func NormalizeText(text string, opts NormalizeOptions) (string, error) {
 	return result, nil
 }
 
+// ComposeNFC returns text in Normalization Form C, so decomposed input (e + ̂ + ̃) matches
+// mappings keyed on precomposed characters (ễ)
+func ComposeNFC(text string) string {
+	return norm.NFC.String(text)
+}
+
 // StripDiacritics removes diacritical marks while preserving base characters
 func StripDiacritics(text string) (string, error) {
 	opts := NormalizeOptions{
@@ -153,102 +159,121 @@ func mapToASCII(r rune) string {
 	return ""
 }
 
+// languageSpecificASCII holds culturally-aware ASCII mappings
+var languageSpecificASCII = map[rune]string{
+	// German ß; umlauts are in germanUmlauts
+	'ß': "ss",
+
+	// Scandinavian
+	'Å': "AA", 'å': "aa",
+	'Æ': "AE", 'æ': "ae",
+	'Ø': "OE", 'ø': "oe",
+
+	// Dutch/Flemish
+	'ĳ': "ij", 'Ĳ': "IJ",
+
+	// French ligatures
+	'œ': "oe", 'Œ': "OE",
+
+	// Spanish
+	'Ñ': "N", 'ñ': "n",
+
+	// Portuguese
+	'ã': "a", 'Ã': "A",
+	'õ': "o", 'Õ': "O",
+
+	// Czech/Slovak
+	'č': "c", 'Č': "C",
+	'š': "s", 'Š': "S", 
+	'ž': "z", 'Ž': "Z",
+	'ř': "r", 'Ř': "R",
+
+	// Polish
+	'ł': "l", 'Ł': "L",
+	'ą': "a", 'Ą': "A",
+	'ę': "e", 'Ę': "E",
+	'ć': "c", 'Ć': "C",
+	'ń': "n", 'Ń': "N",
+	'ś': "s", 'Ś': "S",
+	'ź': "z", 'Ź': "Z",
+	'ż': "z", 'Ż': "Z",
+
+	// Vietnamese (preserve base characters)
+	'Đ': "D", 'đ': "d",
+
+	// Turkish
+	'ı': "i", 'İ': "I",
+	'ğ': "g", 'Ğ': "G",
+	'ş': "s", 'Ş': "S",
+	'ç': "c", 'Ç': "C",
+
+	// Romanian
+	'ă': "a", 'Ă': "A",
+	'â': "a", 'Â': "A", 
+	'î': "i", 'Î': "I",
+	'ș': "s", 'Ș': "S",
+	'ț': "t", 'Ț': "T",
+}
+
+// germanUmlauts expands umlauts per German convention. It is the single table for umlauts:
+// the transliteration engine uses it through UmlautToASCII.
+var germanUmlauts = map[rune]string{
+	'Ä': "AE", 'ä': "ae",
+	'Ö': "OE", 'ö': "oe",
+	'Ü': "UE", 'ü': "ue",
+}
+
+// UmlautToASCII returns the ASCII form of an umlaut, either expanded per German convention
+// (ü ue) or folded to its base letter (ü u); ok is false for any other rune
+func UmlautToASCII(r rune, expand bool) (ascii string, ok bool) {
+	expanded, ok := germanUmlauts[r]
+	if !ok || expand {
+		return expanded, ok
+	}
+	return expanded[:1], true
+}
+
 // getLanguageSpecificASCII provides culturally-aware ASCII mappings
 func getLanguageSpecificASCII(r rune) string {
-	mappings := map[rune]string{
-		// German umlauts and ß
-		'Ä': "AE", 'ä': "ae",
-		'Ö': "OE", 'ö': "oe", 
-		'Ü': "UE", 'ü': "ue",
-		'ß': "ss",
-
-		// Scandinavian
-		'Å': "AA", 'å': "aa",
-		'Æ': "AE", 'æ': "ae",
-		'Ø': "OE", 'ø': "oe",
-
-		// Dutch/Flemish
-		'ĳ': "ij", 'Ĳ': "IJ",
-
-		// French ligatures
-		'œ': "oe", 'Œ': "OE",
-
-		// Spanish
-		'Ñ': "N", 'ñ': "n",
-
-		// Portuguese
-		'ã': "a", 'Ã': "A",
-		'õ': "o", 'Õ': "O",
-
-		// Czech/Slovak
-		'č': "c", 'Č': "C",
-		'š': "s", 'Š': "S", 
-		'ž': "z", 'Ž': "Z",
-		'ř': "r", 'Ř': "R",
-
-		// Polish
-		'ł': "l", 'Ł': "L",
-		'ą': "a", 'Ą': "A",
-		'ę': "e", 'Ę': "E",
-		'ć': "c", 'Ć': "C",
-		'ń': "n", 'Ń': "N",
-		'ś': "s", 'Ś': "S",
-		'ź': "z", 'Ź': "Z",
-		'ż': "z", 'Ż': "Z",
-
-		// Vietnamese (preserve base characters)
-		'Đ': "D", 'đ': "d",
-
-		// Turkish
-		'ı': "i", 'İ': "I",
-		'ğ': "g", 'Ğ': "G",
-		'ş': "s", 'Ş': "S",
-		'ç': "c", 'Ç': "C",
-
-		// Romanian
-		'ă': "a", 'Ă': "A",
-		'â': "a", 'Â': "A", 
-		'î': "i", 'Î': "I",
-		'ș': "s", 'Ș': "S",
-		'ț': "t", 'Ț': "T",
+	if expanded, ok := UmlautToASCII(r, true); ok {
+		return expanded
 	}
+	return languageSpecificASCII[r]
+}
 
-	return mappings[r]
+// diacriticalMappings folds common accented letters to their base letter
+var diacriticalMappings = map[rune]string{
+	// A variants
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ā': "A", 'Ă': "A",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'ă': "a",
+
+	// E variants  
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ĕ': "E",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e",
+
+	// I variants
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I", 'Ĭ': "I",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ĭ': "i",
+
+	// O variants
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ō': "O", 'Ŏ': "O",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ō': "o", 'ŏ': "o",
+
+	// U variants
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ū': "U", 'Ŭ': "U",
+	'ù': "u", 'ú': "u", 'û': "u", 'ū': "u", 'ŭ': "u",
+
+	// Y variants
+	'Ỳ': "Y", 'Ý': "Y", 'Ŷ': "Y", 'Ÿ': "Y",
+	'ỳ': "y", 'ý': "y", 'ŷ': "y", 'ÿ': "y",
+
+	// Other common accented characters
+	'Ç': "C", 'ç': "c",
 }
 
 // getDiacriticalMapping handles basic diacritical marks
 func getDiacriticalMapping(r rune) string {
-	// Common diacritical mappings
-	mappings := map[rune]string{
-		// A variants
-		'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ā': "A", 'Ă': "A",
-		'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'ă': "a",
-
-		// E variants  
-		'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ĕ': "E",
-		'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e",
-
-		// I variants
-		'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I", 'Ĭ': "I",
-		'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ĭ': "i",
-
-		// O variants
-		'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ō': "O", 'Ŏ': "O",
-		'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ō': "o", 'ŏ': "o",
-
-		// U variants
-		'Ù': "U", 'Ú': "U", 'Û': "U", 'Ū': "U", 'Ŭ': "U",
-		'ù': "u", 'ú': "u", 'û': "u", 'ū': "u", 'ŭ': "u",
-
-		// Y variants
-		'Ỳ': "Y", 'Ý': "Y", 'Ŷ': "Y", 'Ÿ': "Y",
-		'ỳ': "y", 'ý': "y", 'ŷ': "y", 'ÿ': "y",
-
-		// Other common accented characters
-		'Ç': "C", 'ç': "c",
-	}
-
-	return mappings[r]
+	return diacriticalMappings[r]
 }
 
 // getScriptMapping handles script-to-Latin conversions
@@ -258,22 +283,23 @@ func getScriptMapping(r rune) string {
 	return ""
 }
 
+// punctuationMappings maps typographic punctuation to ASCII equivalents
+var punctuationMappings = map[rune]string{
+	0x201C: "\"", 0x201D: "\"", // Smart quotes
+	0x2018: "'", 0x2019: "'",   // Smart apostrophes
+	0x2026: "...",              // Ellipsis
+	0x2013: "-", 0x2014: "-",   // En dash, em dash
+	0x00AB: "\"", 0x00BB: "\"", // Guillemets
+	0x2039: "'", 0x203A: "'",   // Single guillemets
+	0x2022: "*",                // Bullet
+	0x00B7: ".",                // Middle dot
+	0x00A1: "!",                // Inverted exclamation
+	0x00BF: "?",                // Inverted question
+}
+
 // getPunctuationMapping maps punctuation to ASCII equivalents
 func getPunctuationMapping(r rune) string {
-	mappings := map[rune]string{
-		0x201C: "\"", 0x201D: "\"", // Smart quotes
-		0x2018: "'", 0x2019: "'",   // Smart apostrophes
-		0x2026: "...",              // Ellipsis
-		0x2013: "-", 0x2014: "-",   // En dash, em dash
-		0x00AB: "\"", 0x00BB: "\"", // Guillemets
-		0x2039: "'", 0x203A: "'",   // Single guillemets
-		0x2022: "*",                // Bullet
-		0x00B7: ".",                // Middle dot
-		0x00A1: "!",                // Inverted exclamation
-		0x00BF: "?",                // Inverted question
-	}
-
-	if mapped, exists := mappings[r]; exists {
+	if mapped, exists := punctuationMappings[r]; exists {
 		return mapped
 	}
 
@@ -319,6 +345,68 @@ func (t *asciiTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc in
 
 func (t *asciiTransformer) Reset() {}
 
+// IsPrivateOrUnassigned reports whether r is a Private Use Area or unassigned code point
+func IsPrivateOrUnassigned(r rune) bool {
+	if unicode.Is(unicode.Co, r) {
+		return true
+	}
+	// Every assigned code point belongs to one of these general categories
+	return !unicode.IsGraphic(r) && !unicode.In(r, unicode.Cc, unicode.Cf, unicode.Cs, unicode.Zl, unicode.Zp)
+}
+
+// FindPrivateOrUnassigned returns the distinct private-use or unassigned code points in text
+func FindPrivateOrUnassigned(text string) []rune {
+	var found []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if IsPrivateOrUnassigned(r) && !seen[r] {
+			seen[r] = true
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// StripPrivateOrUnassigned removes private-use and unassigned code points, returning how many were removed
+func StripPrivateOrUnassigned(text string) (string, int) {
+	removed := 0
+	stripped := strings.Map(func(r rune) rune {
+		if IsPrivateOrUnassigned(r) {
+			removed++
+			return -1
+		}
+		return r
+	}, text)
+	return stripped, removed
+}
+
+// IsBidiControl reports whether r is an invisible bidirectional formatting character:
+// the LRM/RLM/ALM marks, embeddings and overrides (U+202A–U+202E) or isolates (U+2066–U+2069)
+func IsBidiControl(r rune) bool {
+	switch {
+	case r == '\u200E', r == '\u200F', r == '\u061C':
+		return true
+	case r >= '\u202A' && r <= '\u202E':
+		return true
+	case r >= '\u2066' && r <= '\u2069':
+		return true
+	}
+	return false
+}
+
+// StripBidiControls removes bidirectional formatting characters, returning how many were removed
+func StripBidiControls(text string) (string, int) {
+	removed := 0
+	stripped := strings.Map(func(r rune) rune {
+		if IsBidiControl(r) {
+			removed++
+			return -1
+		}
+		return r
+	}, text)
+	return stripped, removed
+}
+
 // Custom errors
 var (
 	ErrInvalidUTF8 = transform.ErrShortSrc
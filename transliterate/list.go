@@ -0,0 +1,129 @@
+package transliterate
+
+import (
+	"context"
+	"time"
+)
+
+// Limits for the page size of ListTransliterations
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// ListTransliterationsParams selects a page of stored transliterations and filters them
+type ListTransliterationsParams struct {
+	Limit         int     `query:"limit"`          // Page size (default 50, maximum 200)
+	Offset        int     `query:"offset"`         // Number of transliterations to skip
+	InputScript   string  `query:"input_script"`   // Only list transliterations from this script (optional)
+	OutputScript  string  `query:"output_script"`  // Only list transliterations to this script (optional)
+	MinConfidence float64 `query:"min_confidence"` // Only list transliterations scoring at least this (0-1); unscored rows are left out when set
+}
+
+// TransliterationPage is a page of stored transliterations, most recently updated first
+type TransliterationPage struct {
+	Transliterations []StoredTransliteration `json:"transliterations"`
+	Total            int64                   `json:"total"`  // Transliterations matching the filters, across all pages
+	Limit            int                     `json:"limit"`  // Page size applied
+	Offset           int                     `json:"offset"` // Transliterations skipped before this page
+}
+
+// StoredTransliteration is a stored transliteration as listed for administrators
+type StoredTransliteration struct {
+	ID              string    `json:"id"`
+	InputText       string    `json:"input_text"`
+	OutputText      string    `json:"output_text"`                // Computed output
+	PreferredOutput *string   `json:"preferred_output,omitempty"` // Output users preferred, served in place of output_text
+	InputScript     string    `json:"input_script"`
+	OutputScript    string    `json:"output_script"`
+	InputLocale     *string   `json:"input_locale,omitempty"`
+	ConfidenceScore *float64  `json:"confidence_score"`
+	UsageCount      int64     `json:"usage_count"`
+	NeedsReview     bool      `json:"needs_review"` // Rescoring found the current rules give a different output
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ListTransliterations pages through stored transliterations, most recently updated first
+//
+//encore:api auth method=GET path=/api/transliterate
+func ListTransliterations(ctx context.Context, params *ListTransliterationsParams) (*TransliterationPage, error) {
+	if params == nil {
+		params = &ListTransliterationsParams{}
+	}
+	if err := validateListTransliterationsParams(params); err != nil {
+		return nil, err
+	}
+
+	limit := defaultListLimit
+	if params.Limit != 0 {
+		limit = params.Limit
+	}
+	resp := &TransliterationPage{
+		Transliterations: []StoredTransliteration{},
+		Limit:            limit,
+		Offset:           params.Offset,
+	}
+
+	// An empty script or zero confidence leaves that filter off. The confidence is cast, since
+	// comparing it with 0 would otherwise type it as an integer and drop its fraction.
+	const filters = `
+		WHERE ($1 = '' OR input_script = $1)
+			AND ($2 = '' OR output_script = $2)
+			AND ($3::float8 = 0 OR confidence_score >= $3::float8)
+	`
+	err := db.QueryRow(ctx, `SELECT COUNT(*) FROM transliterations`+filters,
+		params.InputScript, params.OutputScript, params.MinConfidence).Scan(&resp.Total)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to count transliterations")
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, input_text, output_text, preferred_output, input_script, output_script, input_locale,
+			confidence_score, usage_count, needs_review, updated_at
+		FROM transliterations`+filters+`
+		ORDER BY updated_at DESC, id
+		LIMIT $4 OFFSET $5
+	`, params.InputScript, params.OutputScript, params.MinConfidence, limit, params.Offset)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to list transliterations")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item StoredTransliteration
+		if err := rows.Scan(&item.ID, &item.InputText, &item.OutputText, &item.PreferredOutput, &item.InputScript, &item.OutputScript, &item.InputLocale,
+			&item.ConfidenceScore, &item.UsageCount, &item.NeedsReview, &item.UpdatedAt); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read transliterations")
+		}
+		resp.Transliterations = append(resp.Transliterations, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read transliterations")
+	}
+
+	return resp, nil
+}
+
+// validateListTransliterationsParams validates the paging and filters of a listing, reporting
+// every invalid parameter at once
+func validateListTransliterationsParams(params *ListTransliterationsParams) error {
+	var problems validationErrors
+
+	if params.Limit < 0 || params.Limit > maxListLimit {
+		problems.add("limit", ReasonInvalidLimit, "limit must be between 1 and %d", maxListLimit)
+	}
+	if params.Offset < 0 {
+		problems.add("offset", ReasonInvalidOffset, "offset cannot be negative")
+	}
+	if params.InputScript != "" && !validScripts[params.InputScript] {
+		problems.add("input_script", ReasonUnsupportedInputScript, "unsupported input script: %s", params.InputScript)
+	}
+	if params.OutputScript != "" && !validScripts[params.OutputScript] {
+		problems.add("output_script", ReasonUnsupportedOutputScript, "unsupported output script: %s", params.OutputScript)
+	}
+	if params.MinConfidence < 0 || params.MinConfidence > 1 {
+		problems.add("min_confidence", ReasonInvalidMinConfidence, "min_confidence must be between 0 and 1")
+	}
+
+	return problems.err()
+}
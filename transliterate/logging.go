@@ -0,0 +1,104 @@
+package transliterate
+
+import (
+	"database/sql"
+	"errors"
+
+	"encore.app/transliterate/internal/detection"
+	"encore.app/transliterate/internal/transliteration"
+
+	"encore.dev"
+	"encore.dev/rlog"
+)
+
+// serviceLogger records the decisions the service makes and the errors it recovers from
+// without failing the request. Each entry is a message with key-value pairs, as for rlog.
+type serviceLogger interface {
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// rlogLogger writes to Encore's structured log
+type rlogLogger struct{}
+
+func (rlogLogger) Info(msg string, keysAndValues ...any)  { rlog.Info(msg, keysAndValues...) }
+func (rlogLogger) Warn(msg string, keysAndValues ...any)  { rlog.Warn(msg, keysAndValues...) }
+func (rlogLogger) Error(msg string, keysAndValues ...any) { rlog.Error(msg, keysAndValues...) }
+
+// logger is where the service logs; tests replace it to see what was logged
+var logger serviceLogger = rlogLogger{}
+
+// requestIDHeader carries a client's own ID for a request, so its log entries can be found
+// from the client's side
+const requestIDHeader = "X-Request-ID"
+
+// requestID identifies the request being handled in log entries: the client's X-Request-ID,
+// or else the trace's correlation or trace ID. Outside a request it is "".
+func requestID() string {
+	req := encore.CurrentRequest()
+	if req == nil {
+		return ""
+	}
+	if id := req.Headers.Get(requestIDHeader); id != "" {
+		return id
+	}
+	if req.Trace == nil {
+		return ""
+	}
+	if req.Trace.ExtCorrelationID != "" {
+		return req.Trace.ExtCorrelationID
+	}
+	return req.Trace.TraceID
+}
+
+// withRequestID prefixes log key-value pairs with the request ID
+func withRequestID(keysAndValues []any) []any {
+	return append([]any{"request_id", requestID()}, keysAndValues...)
+}
+
+// logInfo logs a decision made for the current request
+func logInfo(msg string, keysAndValues ...any) {
+	logger.Info(msg, withRequestID(keysAndValues)...)
+}
+
+// logWarn logs a degraded path the current request took, such as a fallback
+func logWarn(msg string, keysAndValues ...any) {
+	logger.Warn(msg, withRequestID(keysAndValues)...)
+}
+
+// logError logs an error the current request recovered from
+func logError(msg string, keysAndValues ...any) {
+	logger.Error(msg, withRequestID(keysAndValues)...)
+}
+
+// logDetection logs the script detected in the input and the script converted from
+func logDetection(info detection.ScriptInfo, inputScript string) {
+	logInfo("script detected", "script", info.Script, "confidence", info.Confidence, "input_script", inputScript)
+}
+
+// logConversion logs how much of a conversion fell back from the database mappings and the
+// script's rules
+func logConversion(result *transliteration.Result, inputScript, outputScript string) {
+	if result.MappingError != nil {
+		logWarn("character mappings unavailable, using built-in rules", "input_script", inputScript, "output_script", outputScript, "err", result.MappingError)
+	}
+	if result.Fallbacks > 0 {
+		logInfo("characters converted by fallback", "input_script", inputScript, "output_script", outputScript, "count", result.Fallbacks)
+	}
+}
+
+// logCacheLookup logs whether a transliteration was found in the cache. A failed lookup is
+// treated as a miss, so the conversion runs afresh.
+func logCacheLookup(cached *TransliterationResponse, stale bool, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		logInfo("cache miss")
+	case err != nil:
+		logError("cache lookup failed", "err", err)
+	case stale:
+		logInfo("cache entry stale", "transliteration_id", cached.ID)
+	default:
+		logInfo("cache hit", "transliteration_id", cached.ID)
+	}
+}
@@ -0,0 +1,99 @@
+package transliterate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"encore.app/transliterate/internal/detection"
+	textnorm "encore.app/transliterate/internal/unicode"
+)
+
+// LookupParams identifies a transliteration by its input rather than its ID
+type LookupParams struct {
+	Text            string `query:"text"`           // Text that was transliterated
+	InputScript     string `query:"input_script"`   // Script of the text (optional - can auto-detect)
+	OutputScript    string `query:"output_script"`  // Script it was transliterated to
+	Locale          string `query:"locale"`         // Locale the text was transliterated with (optional)
+	APIVersion      string `header:"X-API-Version"` // Response shape, as for POST /transliterate (optional)
+	APIVersionQuery string `query:"api_version"`    // Response shape, for clients that can't set headers (optional)
+}
+
+// LookupTransliteration returns the stored transliteration POST /transliterate would serve from
+// its cache for default options, without creating one or counting a use
+//
+//encore:api public method=GET path=/api/transliterate/lookup
+func LookupTransliteration(ctx context.Context, params *LookupParams) (*TransliterationResponse, error) {
+	if err := validateLookupParams(params); err != nil {
+		return nil, err
+	}
+
+	// Match the normalization applied before results are stored
+	text, err := stripBidiControls(params.Text)
+	if err != nil {
+		return nil, err
+	}
+	text = textnorm.ComposeNFC(text)
+
+	inputScript := params.InputScript
+	if inputScript == "" {
+		inputScript, err = detectInputScript(detection.DetectScript(text), 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var locale *string
+	if params.Locale != "" {
+		locale = &params.Locale
+	}
+
+	// A stale row would be recomputed by POST /transliterate rather than served
+	result, stale, err := getCachedTransliteration(ctx, text, inputScript, params.OutputScript, locale, "")
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && stale) {
+		return nil, notFound(ReasonNotFound, "transliteration not found")
+	}
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "database error")
+	}
+
+	annotateStoredTransliteration(result)
+	result.FromCache = true
+	applyAPIVersion(result, requestedAPIVersion(params.APIVersion, params.APIVersionQuery))
+	return result, nil
+}
+
+// validateLookupParams validates the cache lookup parameters
+func validateLookupParams(params *LookupParams) error {
+	if params == nil {
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
+	}
+
+	var problems validationErrors
+
+	if strings.TrimSpace(params.Text) == "" {
+		problems.add("text", ReasonTextEmpty, "text cannot be empty")
+	} else if utf8.RuneCountInString(params.Text) > maxCachedTextLength {
+		problems.add("text", ReasonTextTooLong, "text too long (maximum %d characters)", maxCachedTextLength)
+	}
+
+	if params.InputScript != "" && !validScripts[params.InputScript] {
+		problems.add("input_script", ReasonUnsupportedInputScript, "unsupported input script: %s", params.InputScript)
+	}
+
+	if params.OutputScript == "" {
+		problems.add("output_script", ReasonOutputScriptRequired, "output_script is required")
+	} else if !validScripts[params.OutputScript] {
+		problems.add("output_script", ReasonUnsupportedOutputScript, "unsupported output script: %s", params.OutputScript)
+	}
+
+	if params.Locale != "" && !isValidLocale(params.Locale) {
+		problems.add("locale", ReasonInvalidLocale, "invalid locale format: %s", params.Locale)
+	}
+
+	validateAPIVersion(&problems, params.APIVersion, params.APIVersionQuery)
+
+	return problems.err()
+}
@@ -0,0 +1,133 @@
+package transliterate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits for mapping imports
+const (
+	maxImportMappings      = 1000 // Rows accepted in one import
+	maxMappingTargetLength = 50   // Matches character_mappings.target_char
+	defaultMappingWeight   = 0.50 // Matches the character_mappings.frequency_weight default
+)
+
+// ImportMappingsRequest is a batch of character mappings to load
+type ImportMappingsRequest struct {
+	Mappings []CharacterMapping `json:"mappings"`
+}
+
+// CharacterMapping is one row of the character_mappings table
+type CharacterMapping struct {
+	SourceChar      string   `json:"source_char"`                // A single character, e.g. 'ѣ'
+	SourceScript    string   `json:"source_script"`              // e.g. 'cyrillic'
+	TargetChar      string   `json:"target_char"`                // Its transliteration, e.g. 'ie'
+	TargetScript    string   `json:"target_script"`              // e.g. 'latin'
+	Locale          *string  `json:"locale,omitempty"`           // Applies only to this locale, e.g. 'ru-RU' (optional)
+	FrequencyWeight *float64 `json:"frequency_weight,omitempty"` // 0.0 to 1.0; the highest weight wins (default 0.5)
+}
+
+// ImportMappingsResponse counts what happened to each imported row
+type ImportMappingsResponse struct {
+	Inserted int          `json:"inserted"`         // New mappings
+	Updated  int          `json:"updated"`          // Existing mappings whose weight was replaced
+	Rejected int          `json:"rejected"`         // Invalid rows, which are skipped
+	Errors   []FieldError `json:"errors,omitempty"` // Why each rejected row was rejected
+}
+
+// ImportMappings upserts character mappings, skipping invalid rows. A mapping is identified by
+// its source and target characters, scripts and locale; importing it again replaces its weight.
+//
+//encore:api auth method=POST path=/api/mappings/import
+func ImportMappings(ctx context.Context, req *ImportMappingsRequest) (*ImportMappingsResponse, error) {
+	if req == nil || len(req.Mappings) == 0 {
+		return nil, invalidArgument(ReasonRequestMissing, "mappings cannot be empty")
+	}
+	if len(req.Mappings) > maxImportMappings {
+		return nil, invalidArgument(ReasonTooManyMappings, "too many mappings (maximum %d per import)", maxImportMappings)
+	}
+
+	resp := &ImportMappingsResponse{}
+	var problems validationErrors
+	valid := make([]CharacterMapping, 0, len(req.Mappings))
+	for i, mapping := range req.Mappings {
+		before := len(problems)
+		validateMapping(&problems, fmt.Sprintf("mappings[%d]", i), mapping)
+		if len(problems) > before {
+			resp.Rejected++
+			continue
+		}
+		valid = append(valid, mapping)
+	}
+	resp.Errors = problems
+	if len(valid) == 0 {
+		return resp, nil
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to start import")
+	}
+	defer tx.Rollback()
+
+	for _, mapping := range valid {
+		weight := defaultMappingWeight
+		if mapping.FrequencyWeight != nil {
+			weight = *mapping.FrequencyWeight
+		}
+
+		result, err := tx.Exec(ctx, `
+			UPDATE character_mappings
+			SET frequency_weight = $6, updated_at = NOW()
+			WHERE source_char = $1 AND target_char = $2 AND source_script = $3 AND target_script = $4
+			AND locale IS NOT DISTINCT FROM $5
+		`, mapping.SourceChar, mapping.TargetChar, mapping.SourceScript, mapping.TargetScript, mapping.Locale, weight)
+		if err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to update mapping")
+		}
+		if result.RowsAffected() > 0 {
+			resp.Updated++
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO character_mappings (source_char, target_char, source_script, target_script, locale, frequency_weight)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, mapping.SourceChar, mapping.TargetChar, mapping.SourceScript, mapping.TargetScript, mapping.Locale, weight)
+		if err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to insert mapping")
+		}
+		resp.Inserted++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to commit import")
+	}
+	return resp, nil
+}
+
+// validateMapping records every problem with one imported row under the given field prefix
+func validateMapping(problems *validationErrors, prefix string, mapping CharacterMapping) {
+	if utf8.RuneCountInString(mapping.SourceChar) != 1 {
+		problems.add(prefix+".source_char", ReasonInvalidMapping, "source_char must be a single character")
+	}
+	if strings.TrimSpace(mapping.TargetChar) == "" {
+		problems.add(prefix+".target_char", ReasonInvalidMapping, "target_char cannot be empty")
+	} else if utf8.RuneCountInString(mapping.TargetChar) > maxMappingTargetLength {
+		problems.add(prefix+".target_char", ReasonInvalidMapping, "target_char too long (maximum %d characters)", maxMappingTargetLength)
+	}
+	if !validScripts[mapping.SourceScript] {
+		problems.add(prefix+".source_script", ReasonUnsupportedInputScript, "unsupported source_script: %s", mapping.SourceScript)
+	}
+	if !validScripts[mapping.TargetScript] {
+		problems.add(prefix+".target_script", ReasonUnsupportedOutputScript, "unsupported target_script: %s", mapping.TargetScript)
+	}
+	if mapping.Locale != nil && !isValidLocale(*mapping.Locale) {
+		problems.add(prefix+".locale", ReasonInvalidLocale, "invalid locale: %s", *mapping.Locale)
+	}
+	if w := mapping.FrequencyWeight; w != nil && (*w < 0 || *w > 1) {
+		problems.add(prefix+".frequency_weight", ReasonInvalidMapping, "frequency_weight must be between 0 and 1")
+	}
+}
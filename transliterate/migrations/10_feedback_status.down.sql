@@ -0,0 +1,4 @@
+-- Remove feedback moderation state
+DROP INDEX IF EXISTS idx_transliteration_feedback_status;
+ALTER TABLE transliteration_feedback DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE transliteration_feedback DROP COLUMN IF EXISTS status;
@@ -0,0 +1,6 @@
+-- Moderation of feedback corrections
+-- Corrections wait as 'pending' until an administrator approves them, promoting the mapping
+-- they imply to character_mappings, or rejects them
+ALTER TABLE transliteration_feedback ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending'; -- 'pending', 'approved', 'rejected'
+ALTER TABLE transliteration_feedback ADD COLUMN reviewed_at TIMESTAMPTZ;
+CREATE INDEX idx_transliteration_feedback_status ON transliteration_feedback(status, created_at);
@@ -0,0 +1,4 @@
+-- Remove the indexes for browsing stored transliterations
+DROP INDEX IF EXISTS idx_transliterations_output_updated;
+DROP INDEX IF EXISTS idx_transliterations_scripts_updated;
+DROP INDEX IF EXISTS idx_transliterations_updated;
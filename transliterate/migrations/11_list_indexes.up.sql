@@ -0,0 +1,4 @@
+-- Indexes for browsing stored transliterations, newest first, optionally filtered by script
+CREATE INDEX idx_transliterations_updated ON transliterations(updated_at DESC);
+CREATE INDEX idx_transliterations_scripts_updated ON transliterations(input_script, output_script, updated_at DESC);
+CREATE INDEX idx_transliterations_output_updated ON transliterations(output_script, updated_at DESC);
@@ -0,0 +1,2 @@
+-- Remove user-preferred outputs
+ALTER TABLE transliterations DROP COLUMN IF EXISTS preferred_output;
@@ -0,0 +1,3 @@
+-- Output users preferred over the computed one, set once enough "preferred" feedback agrees.
+-- output_text keeps the computed output for rescoring and aligning corrections.
+ALTER TABLE transliterations ADD COLUMN preferred_output TEXT;
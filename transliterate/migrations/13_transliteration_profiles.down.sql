@@ -0,0 +1,2 @@
+-- Remove transliteration profiles
+DROP TABLE IF EXISTS transliteration_profiles;
@@ -0,0 +1,8 @@
+-- Named bundles of transliteration options, selected with a request's profile field.
+-- options holds the request fields as JSON, e.g. {"output_script": "ascii", "standard": "buckwalter"}.
+CREATE TABLE transliteration_profiles (
+    name VARCHAR(50) PRIMARY KEY,
+    options JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- Remove suggestion clients
+ALTER TABLE mapping_suggestions DROP COLUMN IF EXISTS client_id;
@@ -0,0 +1,3 @@
+-- Who suggested each mapping, so corroboration counts distinct clients rather than one client
+-- correcting several transliterations. NULL when the client's address wasn't known.
+ALTER TABLE mapping_suggestions ADD COLUMN client_id VARCHAR(64);
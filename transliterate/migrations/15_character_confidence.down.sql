@@ -0,0 +1,2 @@
+-- Remove stored per-character confidence
+ALTER TABLE transliterations DROP COLUMN IF EXISTS character_confidence;
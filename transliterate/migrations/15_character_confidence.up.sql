@@ -0,0 +1,5 @@
+-- The engine's per-character confidence behind each stored confidence_score, which is now the
+-- sum of the confidence factors, so the factors of a stored result can be recomputed.
+-- Rows stored earlier scored with the per-character confidence alone, so it is carried over.
+ALTER TABLE transliterations ADD COLUMN character_confidence DECIMAL(3,2);
+UPDATE transliterations SET character_confidence = confidence_score;
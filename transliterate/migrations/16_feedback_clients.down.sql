@@ -0,0 +1,2 @@
+-- Remove feedback clients
+ALTER TABLE transliteration_feedback DROP COLUMN IF EXISTS client_id;
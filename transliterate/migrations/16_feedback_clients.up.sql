@@ -0,0 +1,3 @@
+-- Who submitted each feedback, so a preferred output needs agreement from distinct clients
+-- rather than one client repeating itself. NULL when the client's address wasn't known.
+ALTER TABLE transliteration_feedback ADD COLUMN client_id VARCHAR(64);
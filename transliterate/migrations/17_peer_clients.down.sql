@@ -0,0 +1,2 @@
+-- Cleared client IDs can't be restored
+SELECT 1;
@@ -0,0 +1,5 @@
+-- Client IDs stored so far hashed the first X-Forwarded-For hop, which callers write
+-- themselves, so they can't be trusted to tell clients apart. They are cleared, and only
+-- feedback identified by the gateway's peer address corroborates from now on.
+UPDATE mapping_suggestions SET client_id = NULL;
+UPDATE transliteration_feedback SET client_id = NULL;
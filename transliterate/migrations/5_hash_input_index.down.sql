@@ -0,0 +1,3 @@
+-- Restore the plain input text index
+DROP INDEX IF EXISTS idx_transliterations_input_hash;
+CREATE INDEX idx_transliterations_input ON transliterations(input_text);
@@ -0,0 +1,3 @@
+-- Index input text by hash so document-sized inputs fit within btree row limits
+DROP INDEX IF EXISTS idx_transliterations_input;
+CREATE INDEX idx_transliterations_input_hash ON transliterations(md5(input_text));
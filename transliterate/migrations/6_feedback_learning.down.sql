@@ -0,0 +1,4 @@
+-- Remove feedback learning
+DROP INDEX IF EXISTS idx_character_mappings_updated;
+ALTER TABLE character_mappings DROP COLUMN IF EXISTS updated_at;
+DROP TABLE IF EXISTS mapping_suggestions;
@@ -0,0 +1,20 @@
+-- Learning from user corrections
+-- Character mappings implied by corrections are collected here and only promoted to
+-- character_mappings once enough distinct transliterations corroborate them
+
+CREATE TABLE mapping_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transliteration_id UUID NOT NULL REFERENCES transliterations(id),
+    source_char VARCHAR(10) NOT NULL,
+    target_char VARCHAR(50) NOT NULL,
+    source_script VARCHAR(50) NOT NULL,
+    target_script VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (transliteration_id, source_char, target_char, source_script, target_script)
+);
+
+CREATE INDEX idx_mapping_suggestions_mapping ON mapping_suggestions(source_char, source_script, target_script, target_char);
+
+-- Track when mappings change so cached transliterations computed earlier can be skipped
+ALTER TABLE character_mappings ADD COLUMN updated_at TIMESTAMPTZ DEFAULT NOW();
+CREATE INDEX idx_character_mappings_updated ON character_mappings(source_script, target_script, updated_at);
@@ -0,0 +1,2 @@
+-- Remove the options hash from cached transliterations
+ALTER TABLE transliterations DROP COLUMN IF EXISTS options_hash;
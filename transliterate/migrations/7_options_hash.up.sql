@@ -0,0 +1,4 @@
+-- Key cached transliterations on the options that produced them, so requests that differ
+-- only in standard, tones, spacing and so on never share a row. Existing rows were all
+-- computed with the default options, which hash to the empty string.
+ALTER TABLE transliterations ADD COLUMN options_hash VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Remove the computation time from cached transliterations
+ALTER TABLE transliterations DROP COLUMN IF EXISTS computed_at;
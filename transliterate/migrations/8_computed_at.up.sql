@@ -0,0 +1,7 @@
+-- Record when each cached transliteration was last computed, separately from updated_at (which
+-- every cache hit bumps), so rows older than the latest mapping change or the cache TTL can be
+-- recomputed in place. Existing rows were computed when they were created.
+ALTER TABLE transliterations ADD COLUMN computed_at TIMESTAMPTZ;
+UPDATE transliterations SET computed_at = created_at;
+ALTER TABLE transliterations ALTER COLUMN computed_at SET DEFAULT NOW();
+ALTER TABLE transliterations ALTER COLUMN computed_at SET NOT NULL;
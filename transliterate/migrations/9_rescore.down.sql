@@ -0,0 +1,4 @@
+-- Remove rescoring state from transliterations
+DROP INDEX IF EXISTS idx_transliterations_rescore;
+ALTER TABLE transliterations DROP COLUMN IF EXISTS needs_review;
+ALTER TABLE transliterations DROP COLUMN IF EXISTS rescored_at;
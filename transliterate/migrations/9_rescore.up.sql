@@ -0,0 +1,6 @@
+-- Periodic rescoring of low-confidence transliterations against the current rules.
+-- rescored_at rotates the batches; needs_review flags rows whose recomputed output
+-- no longer matches the stored one, so they can be checked rather than silently changed.
+ALTER TABLE transliterations ADD COLUMN rescored_at TIMESTAMPTZ;
+ALTER TABLE transliterations ADD COLUMN needs_review BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_transliterations_rescore ON transliterations(confidence_score, rescored_at);
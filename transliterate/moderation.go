@@ -0,0 +1,228 @@
+package transliterate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Feedback moderation states, stored in transliteration_feedback.status
+const (
+	feedbackPending  = "pending"
+	feedbackApproved = "approved"
+	feedbackRejected = "rejected"
+)
+
+// Limits for the number of corrections returned by ListPendingFeedback
+const (
+	defaultPendingLimit = 50
+	maxPendingLimit     = 200
+)
+
+// PendingFeedbackParams selects how many pending corrections to return
+type PendingFeedbackParams struct {
+	Limit int `query:"limit"` // Number of corrections to return, oldest first (default 50, maximum 200)
+}
+
+// PendingFeedbackResponse is the moderation queue of corrections awaiting review
+type PendingFeedbackResponse struct {
+	Feedback []PendingFeedback `json:"feedback"`
+}
+
+// PendingFeedback is a correction awaiting review, with the transliteration it corrects
+type PendingFeedback struct {
+	ID                string            `json:"id"`
+	TransliterationID string            `json:"transliteration_id"`
+	InputText         string            `json:"input_text"`
+	InputScript       string            `json:"input_script"`
+	OutputText        string            `json:"output_text"` // Output the correction disagrees with
+	OutputScript      string            `json:"output_script"`
+	SuggestedOutput   string            `json:"suggested_output"`
+	UserContext       string            `json:"user_context,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	Mapping           *CharacterMapping `json:"mapping,omitempty"` // Mapping approval would promote; absent when the correction changes more than one character
+}
+
+// FeedbackReview is the outcome of approving or rejecting a correction
+type FeedbackReview struct {
+	ID      string            `json:"id"`
+	Status  string            `json:"status"`            // approved or rejected
+	Mapping *CharacterMapping `json:"mapping,omitempty"` // Mapping promoted to character_mappings on approval
+}
+
+// ListPendingFeedback returns corrections that have been neither approved nor rejected
+//
+//encore:api auth method=GET path=/api/feedback/pending
+func ListPendingFeedback(ctx context.Context, params *PendingFeedbackParams) (*PendingFeedbackResponse, error) {
+	limit := defaultPendingLimit
+	if params != nil && params.Limit != 0 {
+		limit = params.Limit
+	}
+	if limit < 1 || limit > maxPendingLimit {
+		return nil, invalidArgument(ReasonInvalidLimit, "limit must be between 1 and %d", maxPendingLimit)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT f.id, f.suggested_output, COALESCE(f.user_context, ''), f.created_at,
+			t.id, t.input_text, t.output_text, t.input_script, t.output_script, t.input_locale
+		FROM transliteration_feedback f
+		JOIN transliterations t ON t.id = f.transliteration_id
+		WHERE f.feedback_type = 'correction' AND f.status = $1
+		ORDER BY f.created_at, f.id
+		LIMIT $2
+	`, feedbackPending, limit)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to list pending feedback")
+	}
+	defer rows.Close()
+
+	resp := &PendingFeedbackResponse{Feedback: []PendingFeedback{}}
+	var originals []*TransliterationResponse
+	for rows.Next() {
+		var item PendingFeedback
+		var original TransliterationResponse
+		if err := rows.Scan(&item.ID, &item.SuggestedOutput, &item.UserContext, &item.CreatedAt,
+			&original.ID, &original.InputText, &original.OutputText, &original.InputScript, &original.OutputScript, &original.InputLocale); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read pending feedback")
+		}
+		item.TransliterationID = original.ID
+		item.InputText, item.InputScript = original.InputText, original.InputScript
+		item.OutputText, item.OutputScript = original.OutputText, original.OutputScript
+		resp.Feedback = append(resp.Feedback, item)
+		originals = append(originals, &original)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read pending feedback")
+	}
+	rows.Close()
+
+	// Alignment looks mappings up, so it waits until the rows are read
+	for i, original := range originals {
+		mapping, ok, err := impliedMapping(ctx, original, resp.Feedback[i].SuggestedOutput)
+		if err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to align correction")
+		}
+		if ok {
+			resp.Feedback[i].Mapping = characterMapping(mapping.Source, mapping.Target, mapping.Script, original.OutputScript)
+		}
+	}
+
+	return resp, nil
+}
+
+// ApproveFeedback promotes the mapping a pending correction implies to character_mappings,
+// outranking competing mappings for the character without waiting for corroboration
+//
+//encore:api auth method=POST path=/api/feedback/:id/approve
+func ApproveFeedback(ctx context.Context, id string) (*FeedbackReview, error) {
+	original, suggested, err := pendingCorrection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, ok, err := impliedMapping(ctx, original, suggested)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to align correction")
+	}
+	if !ok {
+		return nil, failedPrecondition(ReasonCorrectionNotAlignable, "correction does not change a single character mapping")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to start review")
+	}
+	defer tx.Rollback()
+
+	// Only one review of a correction wins, however many arrive at once
+	result, err := tx.Exec(ctx, `
+		UPDATE transliteration_feedback
+		SET status = $2, reviewed_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, feedbackApproved, feedbackPending)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to approve feedback")
+	}
+	if result.RowsAffected() == 0 {
+		return nil, failedPrecondition(ReasonFeedbackAlreadyReviewed, "feedback has already been reviewed")
+	}
+
+	if err := promoteMappingTx(ctx, tx, mapping, original.OutputScript); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to promote mapping")
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to commit review")
+	}
+
+	return &FeedbackReview{
+		ID:      id,
+		Status:  feedbackApproved,
+		Mapping: characterMapping(mapping.Source, mapping.Target, mapping.Script, original.OutputScript),
+	}, nil
+}
+
+// RejectFeedback marks a pending correction rejected, leaving character_mappings unchanged
+//
+//encore:api auth method=POST path=/api/feedback/:id/reject
+func RejectFeedback(ctx context.Context, id string) (*FeedbackReview, error) {
+	if _, _, err := pendingCorrection(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := db.Exec(ctx, `
+		UPDATE transliteration_feedback
+		SET status = $2, reviewed_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, feedbackRejected, feedbackPending)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to reject feedback")
+	}
+	if result.RowsAffected() == 0 {
+		return nil, failedPrecondition(ReasonFeedbackAlreadyReviewed, "feedback has already been reviewed")
+	}
+
+	return &FeedbackReview{ID: id, Status: feedbackRejected}, nil
+}
+
+// pendingCorrection loads a correction awaiting review and the transliteration it corrects
+func pendingCorrection(ctx context.Context, id string) (*TransliterationResponse, string, error) {
+	if !isValidUUID(id) {
+		return nil, "", invalidArgument(ReasonInvalidID, "invalid feedback ID format")
+	}
+
+	var original TransliterationResponse
+	var suggested, feedbackType, status string
+	err := db.QueryRow(ctx, `
+		SELECT f.suggested_output, f.feedback_type, f.status,
+			t.id, t.input_text, t.output_text, t.input_script, t.output_script, t.input_locale
+		FROM transliteration_feedback f
+		JOIN transliterations t ON t.id = f.transliteration_id
+		WHERE f.id = $1
+	`, id).Scan(&suggested, &feedbackType, &status,
+		&original.ID, &original.InputText, &original.OutputText, &original.InputScript, &original.OutputScript, &original.InputLocale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", notFound(ReasonFeedbackNotFound, "feedback not found")
+	}
+	if err != nil {
+		return nil, "", internalError(ReasonDatabaseError, err, "database error")
+	}
+
+	if feedbackType != "correction" {
+		return nil, "", failedPrecondition(ReasonFeedbackNotCorrection, "only corrections are reviewed, not %s feedback", feedbackType)
+	}
+	if status != feedbackPending {
+		return nil, "", failedPrecondition(ReasonFeedbackAlreadyReviewed, "feedback has already been %s", status)
+	}
+	return &original, suggested, nil
+}
+
+// characterMapping describes a learned mapping as a character_mappings row
+func characterMapping(source, target, sourceScript, targetScript string) *CharacterMapping {
+	return &CharacterMapping{
+		SourceChar:   source,
+		SourceScript: sourceScript,
+		TargetChar:   target,
+		TargetScript: targetScript,
+	}
+}
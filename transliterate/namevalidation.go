@@ -0,0 +1,74 @@
+package transliterate
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"encore.app/transliterate/internal/nameparser"
+)
+
+// NameWarning flags a way a name does not fit its declared culture
+type NameWarning = nameparser.StructureWarning
+
+// ValidateNameRequest represents a name to check against its culture's conventions
+type ValidateNameRequest struct {
+	Text    string `json:"text"`    // Name as recorded
+	Culture string `json:"culture"` // Declared culture, e.g. 'western', 'chinese', 'arabic'
+}
+
+// ValidateNameResponse reports how well a name fits its declared culture
+type ValidateNameResponse struct {
+	Culture  string        `json:"culture"`  // Culture the name was checked against
+	Parts    int           `json:"parts"`    // Name parts counted, without titles, suffixes and honorifics
+	Valid    bool          `json:"valid"`    // True when there are no warnings
+	Warnings []NameWarning `json:"warnings"` // Each way the name departs from the culture's conventions
+}
+
+// ValidateName checks a name against the structure its declared culture expects, for data
+// quality review; warnings flag names worth a second look rather than rejecting them
+//
+//encore:api public method=POST path=/api/validate-name
+func ValidateName(ctx context.Context, req *ValidateNameRequest) (*ValidateNameResponse, error) {
+	if err := validateValidateNameRequest(req); err != nil {
+		return nil, err
+	}
+
+	parts, warnings := nameparser.NewParser(true, true).ValidateStructure(req.Text, req.Culture)
+	if warnings == nil {
+		warnings = []NameWarning{}
+	}
+
+	return &ValidateNameResponse{
+		Culture:  req.Culture,
+		Parts:    parts,
+		Valid:    len(warnings) == 0,
+		Warnings: warnings,
+	}, nil
+}
+
+// validateValidateNameRequest validates a name validation request, reporting every invalid
+// field at once
+func validateValidateNameRequest(req *ValidateNameRequest) error {
+	if req == nil {
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
+	}
+
+	var problems validationErrors
+
+	if strings.TrimSpace(req.Text) == "" {
+		problems.add("text", ReasonTextEmpty, "text cannot be empty")
+	} else if len(req.Text) > 1000 { // Names are short; reject documents
+		problems.add("text", ReasonTextTooLong, "text too long (maximum 1,000 characters)")
+	} else if !utf8.ValidString(req.Text) {
+		problems.add("text", ReasonInvalidUTF8, "text contains invalid UTF-8 sequences")
+	}
+
+	if req.Culture == "" {
+		problems.add("culture", ReasonCultureRequired, "culture is required")
+	} else if !nameparser.Cultures[req.Culture] {
+		problems.add("culture", ReasonUnsupportedCulture, "unsupported culture: %s", req.Culture)
+	}
+
+	return problems.err()
+}
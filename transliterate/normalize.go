@@ -0,0 +1,85 @@
+package transliterate
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	textnorm "encore.app/transliterate/internal/unicode"
+)
+
+// normalizationForms maps the accepted form names to their Unicode normalization forms
+var normalizationForms = map[string]norm.Form{
+	"NFC": norm.NFC, "NFD": norm.NFD, "NFKC": norm.NFKC, "NFKD": norm.NFKD,
+}
+
+// defaultNormalizationForm applies when form is not set
+const defaultNormalizationForm = "NFC"
+
+// NormalizeRequest represents a request for pure Unicode normalization, without transliteration
+type NormalizeRequest struct {
+	Text             string `json:"text"`                        // Text to normalize
+	Form             string `json:"form,omitempty"`              // 'NFC' (default), 'NFD', 'NFKC' or 'NFKD'
+	RemoveDiacritics bool   `json:"remove_diacritics,omitempty"` // Drop combining marks, e.g. 'é' to 'e'
+	CaseFolding      bool   `json:"case_folding,omitempty"`      // Lowercase for comparison
+	ASCIIOnly        bool   `json:"ascii_only,omitempty"`        // Map to ASCII, e.g. 'ü' to 'ue'
+}
+
+// NormalizeResponse represents normalized text
+type NormalizeResponse struct {
+	Text string `json:"text"` // Normalized text
+	Form string `json:"form"` // Normalization form applied
+}
+
+// Normalize applies Unicode normalization and the optional folding steps to text
+//
+//encore:api public method=POST path=/api/normalize
+func Normalize(ctx context.Context, req *NormalizeRequest) (*NormalizeResponse, error) {
+	if err := validateNormalizeRequest(req); err != nil {
+		return nil, err
+	}
+
+	formName := req.Form
+	if formName == "" {
+		formName = defaultNormalizationForm
+	}
+
+	normalized, err := textnorm.NormalizeText(req.Text, textnorm.NormalizeOptions{
+		Form:             normalizationForms[formName],
+		RemoveDiacritics: req.RemoveDiacritics,
+		CaseFolding:      req.CaseFolding,
+		ASCIIOnly:        req.ASCIIOnly,
+	})
+	if err != nil {
+		return nil, invalidArgument(ReasonInvalidUTF8, "text could not be normalized: %v", err)
+	}
+
+	return &NormalizeResponse{Text: normalized, Form: formName}, nil
+}
+
+// validateNormalizeRequest validates a normalization request, reporting every invalid field at once
+func validateNormalizeRequest(req *NormalizeRequest) error {
+	if req == nil {
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
+	}
+
+	var problems validationErrors
+
+	if strings.TrimSpace(req.Text) == "" {
+		problems.add("text", ReasonTextEmpty, "text cannot be empty")
+	} else if utf8.RuneCountInString(req.Text) > defaultMaxTextLength {
+		problems.add("text", ReasonTextTooLong, "text too long (maximum %d characters)", defaultMaxTextLength)
+	}
+
+	if !utf8.ValidString(req.Text) {
+		problems.add("text", ReasonInvalidUTF8, "text contains invalid UTF-8 sequences")
+	}
+
+	if _, ok := normalizationForms[req.Form]; req.Form != "" && !ok {
+		problems.add("form", ReasonInvalidNormalizationForm, "invalid form: %s (expected NFC, NFD, NFKC or NFKD)", req.Form)
+	}
+
+	return problems.err()
+}
@@ -0,0 +1,259 @@
+package transliterate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// profileNamePattern restricts profile names to lowercase words joined by hyphens, e.g. german-official
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// maxProfileNameLength matches transliteration_profiles.name
+const maxProfileNameLength = 50
+
+// ProfileOptions is a bundle of transliteration options saved under a profile name. Each
+// field has the name and meaning of the request field it fills in.
+type ProfileOptions struct {
+	OutputScript          string  `json:"output_script,omitempty"`
+	InputLocale           *string `json:"input_locale,omitempty"`
+	Standard              string  `json:"standard,omitempty"`
+	BoundarySpacing       string  `json:"boundary_spacing,omitempty"`
+	LongVowels            string  `json:"long_vowels,omitempty"`
+	UnmappedPolicy        string  `json:"unmapped_policy,omitempty"`
+	NameFormat            string  `json:"name_format,omitempty"`
+	NumberWords           bool    `json:"number_words,omitempty"`
+	PreserveDiacritics    bool    `json:"preserve_diacritics,omitempty"`
+	PreserveEmoji         bool    `json:"preserve_emoji,omitempty"`
+	PreserveSymbols       bool    `json:"preserve_symbols,omitempty"`
+	RespectInputCase      bool    `json:"respect_input_case,omitempty"`
+	GermanUmlautExpansion *bool   `json:"german_umlaut_expansion,omitempty"`
+}
+
+// Profile is a named bundle of transliteration options
+type Profile struct {
+	Name      string         `json:"name"`
+	Options   ProfileOptions `json:"options"`
+	BuiltIn   bool           `json:"built_in"`             // Defined by the service; cannot be changed or deleted
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"` // Absent for built-in profiles
+}
+
+// ProfileList lists the available profiles, built-in ones first
+type ProfileList struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// builtinProfiles are available in every deployment, whatever the transliteration_profiles table holds
+var builtinProfiles = map[string]ProfileOptions{
+	// German civil registry spelling: ASCII with umlauts expanded (Müller as Mueller) for any
+	// input, not only text detected as German
+	"german-official": {OutputScript: "ascii", InputLocale: optional("de-DE"), GermanUmlautExpansion: optional(true)},
+	// Reversible ASCII romanization of Arabic
+	"arabic-buckwalter": {OutputScript: "ascii", Standard: "buckwalter"},
+}
+
+// optional returns a pointer to a copy of value, for the optional fields of built-in profiles
+func optional[T any](value T) *T {
+	return &value
+}
+
+// SaveProfile creates or replaces a stored profile
+//
+//encore:api auth method=PUT path=/api/profiles/:name
+func SaveProfile(ctx context.Context, name string, options *ProfileOptions) (*Profile, error) {
+	if err := validateProfileName(name); err != nil {
+		return nil, err
+	}
+	if _, ok := builtinProfiles[name]; ok {
+		return nil, failedPrecondition(ReasonProfileBuiltIn, "profile %s is built in and cannot be changed", name)
+	}
+	if options == nil {
+		options = &ProfileOptions{}
+	}
+	if err := validateProfileOptions(options); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to encode profile")
+	}
+	profile := &Profile{Name: name, Options: *options}
+	err = db.QueryRow(ctx, `
+		INSERT INTO transliteration_profiles (name, options)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET options = EXCLUDED.options, updated_at = NOW()
+		RETURNING updated_at
+	`, name, encoded).Scan(&profile.UpdatedAt)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to save profile")
+	}
+	return profile, nil
+}
+
+// GetProfile returns a built-in or stored profile
+//
+//encore:api public method=GET path=/api/profiles/:name
+func GetProfile(ctx context.Context, name string) (*Profile, error) {
+	if err := validateProfileName(name); err != nil {
+		return nil, err
+	}
+	return loadProfile(ctx, name)
+}
+
+// ListProfiles returns the built-in profiles followed by the stored ones, each by name
+//
+//encore:api public method=GET path=/api/profiles
+func ListProfiles(ctx context.Context) (*ProfileList, error) {
+	resp := &ProfileList{Profiles: []Profile{}}
+	for _, name := range sortedKeys(builtinProfiles) {
+		resp.Profiles = append(resp.Profiles, Profile{Name: name, Options: builtinProfiles[name], BuiltIn: true})
+	}
+
+	rows, err := db.Query(ctx, `SELECT name, options, updated_at FROM transliteration_profiles ORDER BY name`)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to list profiles")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var profile Profile
+		var encoded []byte
+		if err := rows.Scan(&profile.Name, &encoded, &profile.UpdatedAt); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read profiles")
+		}
+		if err := json.Unmarshal(encoded, &profile.Options); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to decode profile %s", profile.Name)
+		}
+		resp.Profiles = append(resp.Profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read profiles")
+	}
+
+	return resp, nil
+}
+
+// DeleteProfile removes a stored profile
+//
+//encore:api auth method=DELETE path=/api/profiles/:name
+func DeleteProfile(ctx context.Context, name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if _, ok := builtinProfiles[name]; ok {
+		return failedPrecondition(ReasonProfileBuiltIn, "profile %s is built in and cannot be deleted", name)
+	}
+
+	result, err := db.Exec(ctx, `DELETE FROM transliteration_profiles WHERE name = $1`, name)
+	if err != nil {
+		return internalError(ReasonDatabaseError, err, "failed to delete profile")
+	}
+	if result.RowsAffected() == 0 {
+		return notFound(ReasonProfileNotFound, "profile not found: %s", name)
+	}
+	return nil
+}
+
+// loadProfile finds a profile by name, built-in profiles first
+func loadProfile(ctx context.Context, name string) (*Profile, error) {
+	if options, ok := builtinProfiles[name]; ok {
+		return &Profile{Name: name, Options: options, BuiltIn: true}, nil
+	}
+
+	profile := &Profile{Name: name}
+	var encoded []byte
+	err := db.QueryRow(ctx, `
+		SELECT options, updated_at FROM transliteration_profiles WHERE name = $1
+	`, name).Scan(&encoded, &profile.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(ReasonProfileNotFound, "profile not found: %s", name)
+	}
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to load profile")
+	}
+	if err := json.Unmarshal(encoded, &profile.Options); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to decode profile %s", name)
+	}
+	return profile, nil
+}
+
+// applyProfile returns a copy of the request with the options of its profile filled in.
+// Fields set in the request win; a profile can turn a boolean option on but not off, since an
+// unset boolean reads as false. The copy has no profile, so it is resolved only once.
+func applyProfile(ctx context.Context, req *TransliterationRequest) (*TransliterationRequest, error) {
+	if req == nil || req.Profile == "" {
+		return req, nil
+	}
+	if err := validateProfileName(req.Profile); err != nil {
+		return nil, err
+	}
+	profile, err := loadProfile(ctx, req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *req
+	resolved.Profile = ""
+	options := profile.Options
+	for _, field := range []struct {
+		value   *string
+		profile string
+	}{
+		{&resolved.OutputScript, options.OutputScript},
+		{&resolved.Standard, options.Standard},
+		{&resolved.BoundarySpacing, options.BoundarySpacing},
+		{&resolved.LongVowels, options.LongVowels},
+		{&resolved.UnmappedPolicy, options.UnmappedPolicy},
+		{&resolved.NameFormat, options.NameFormat},
+	} {
+		if *field.value == "" {
+			*field.value = field.profile
+		}
+	}
+	if resolved.InputLocale == nil {
+		resolved.InputLocale = options.InputLocale
+	}
+	if resolved.GermanUmlautExpansion == nil {
+		resolved.GermanUmlautExpansion = options.GermanUmlautExpansion
+	}
+	resolved.NumberWords = resolved.NumberWords || options.NumberWords
+	resolved.PreserveDiacritics = resolved.PreserveDiacritics || options.PreserveDiacritics
+	resolved.PreserveEmoji = resolved.PreserveEmoji || options.PreserveEmoji
+	resolved.PreserveSymbols = resolved.PreserveSymbols || options.PreserveSymbols
+	resolved.RespectInputCase = resolved.RespectInputCase || options.RespectInputCase
+
+	return &resolved, nil
+}
+
+// validateProfileName checks that a profile name is lowercase words joined by hyphens
+func validateProfileName(name string) error {
+	if len(name) > maxProfileNameLength || !profileNamePattern.MatchString(name) {
+		return invalidArgument(ReasonInvalidProfileName, "invalid profile name: %q (lowercase letters, digits and hyphens, at most %d characters)", name, maxProfileNameLength)
+	}
+	return nil
+}
+
+// validateProfileOptions checks a profile's options as the request fields they fill in would
+// be checked, reporting every invalid option at once
+func validateProfileOptions(options *ProfileOptions) error {
+	outputScript := options.OutputScript
+	if outputScript == "" {
+		// Requests using the profile must then name the output script themselves
+		outputScript = "latin"
+	}
+	return validateTransliterationRequest(&TransliterationRequest{
+		Text:                  "profile",
+		OutputScript:          outputScript,
+		InputLocale:           options.InputLocale,
+		Standard:              options.Standard,
+		BoundarySpacing:       options.BoundarySpacing,
+		LongVowels:            options.LongVowels,
+		UnmappedPolicy:        options.UnmappedPolicy,
+		NameFormat:            options.NameFormat,
+		GermanUmlautExpansion: options.GermanUmlautExpansion,
+	})
+}
@@ -0,0 +1,21 @@
+package transliterate
+
+import "encore.app/transliterate/internal/phonetic"
+
+// Pronunciation is an approximate IPA reading of the output, e.g. for an SSML
+// <phoneme alphabet="ipa"> hint in a voice assistant
+type Pronunciation struct {
+	IPA         string `json:"ipa"`         // Coarse IPA, without tones, stress or the short vowels unvowelled Arabic leaves out
+	Approximate bool   `json:"approximate"` // Always true: the reading follows the romanization rules, not a pronouncing dictionary
+}
+
+// applyPronunciation adds the pronunciation of a Latin or ASCII output when
+// include_pronunciation is requested and the input script has phonetic rules (chinese, arabic)
+func applyPronunciation(resp *TransliterationResponse, req *TransliterationRequest) {
+	if !req.IncludePronunciation || (resp.OutputScript != "latin" && resp.OutputScript != "ascii") {
+		return
+	}
+	if ipa := phonetic.Pronounce(resp.InputText, resp.OutputText, resp.InputScript); ipa != "" {
+		resp.Pronunciation = &Pronunciation{IPA: ipa, Approximate: true}
+	}
+}
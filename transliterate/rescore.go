@@ -0,0 +1,146 @@
+package transliterate
+
+import (
+	"context"
+
+	"encore.app/transliterate/internal/detection"
+	"encore.app/transliterate/internal/transliteration"
+
+	"encore.dev/cron"
+)
+
+// Rescore low-confidence transliterations as mappings and rules improve. The cadence is set
+// here, since Encore requires a job's schedule to be a constant; the batch size and threshold
+// are RescoreBatchSize and RescoreConfidenceThreshold in config.cue.
+var _ = cron.NewJob("rescore-transliterations", cron.JobConfig{
+	Title:    "Rescore low-confidence transliterations",
+	Every:    6 * cron.Hour,
+	Endpoint: RescoreTransliterations,
+})
+
+// RescoreResponse summarizes a rescoring run
+type RescoreResponse struct {
+	Rescored int `json:"rescored"` // Transliterations whose confidence_score was recomputed
+	Flagged  int `json:"flagged"`  // Of those, how many now produce different output and need review
+	Skipped  int `json:"skipped"`  // Transliterations that failed to rescore and wait for a later run
+}
+
+// storedTransliteration is a stored row selected for rescoring
+type storedTransliteration struct {
+	ID           string
+	InputText    string
+	OutputText   string
+	InputScript  string
+	OutputScript string
+	InputLocale  *string
+}
+
+// RescoreTransliterations recomputes the confidence of a batch of low-confidence stored
+// transliterations with the current rules, least recently rescored first. Stored output is
+// left alone; rows whose output would now differ are flagged for review instead. A row that
+// fails to rescore is logged and skipped, so it can't hold up the rest of the batch.
+//
+//encore:api private
+func RescoreTransliterations(ctx context.Context) (*RescoreResponse, error) {
+	// Only rows stored with default options can be recomputed; the options hash can't be reversed
+	rows, err := db.Query(ctx, `
+		SELECT id, input_text, output_text, input_script, output_script, input_locale
+		FROM transliterations
+		WHERE confidence_score < $1 AND options_hash = ''
+		ORDER BY rescored_at NULLS FIRST, confidence_score
+		LIMIT $2
+	`, cfg.RescoreConfidenceThreshold, cfg.RescoreBatchSize)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to query transliterations to rescore")
+	}
+	defer rows.Close()
+
+	var batch []storedTransliteration
+	for rows.Next() {
+		var row storedTransliteration
+		if err := rows.Scan(&row.ID, &row.InputText, &row.OutputText, &row.InputScript, &row.OutputScript, &row.InputLocale); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read transliterations to rescore")
+		}
+		batch = append(batch, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read transliterations to rescore")
+	}
+
+	resp := &RescoreResponse{}
+	for _, row := range batch {
+		changed, ok := rescoreRow(ctx, db, row)
+		switch {
+		case !ok:
+			resp.Skipped++
+		case changed:
+			resp.Rescored++
+			resp.Flagged++
+		default:
+			resp.Rescored++
+		}
+	}
+
+	return resp, nil
+}
+
+// rescoreRow rescores and updates one stored row, reporting whether its output changed and
+// whether it was rescored. A row that fails is logged and still stamped as rescored, so it
+// goes to the back of the rotation rather than failing every run.
+func rescoreRow(ctx context.Context, database execer, row storedTransliteration) (changed, ok bool) {
+	factors, characterConfidence, changed, err := rescoreTransliteration(ctx, row)
+	if err != nil {
+		logError("failed to rescore transliteration", "transliteration_id", row.ID, "err", err)
+		stampRescored(ctx, database, row.ID)
+		return false, false
+	}
+
+	_, err = database.Exec(ctx, `
+		UPDATE transliterations
+		SET confidence_score = $2, character_confidence = $3, rescored_at = NOW(), needs_review = needs_review OR $4
+		WHERE id = $1
+	`, row.ID, factors.Score, characterConfidence, changed)
+	if err != nil {
+		logError("failed to update rescored transliteration", "transliteration_id", row.ID, "err", err)
+		stampRescored(ctx, database, row.ID)
+		return false, false
+	}
+	return changed, true
+}
+
+// stampRescored marks a row that failed to rescore as rescored, keeping its score
+func stampRescored(ctx context.Context, database execer, id string) {
+	_, err := database.Exec(ctx, `UPDATE transliterations SET rescored_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		logError("failed to mark transliteration rescored", "transliteration_id", id, "err", err)
+	}
+}
+
+// rescoreTransliteration converts a stored row's input again with default options, returning
+// the stored output's new confidence factors, the conversion's per-character confidence and
+// whether the output differs from what was stored
+func rescoreTransliteration(ctx context.Context, row storedTransliteration) (ConfidenceFactors, float64, bool, error) {
+	result, err := convertWithDefaults(ctx, row.InputText, row.InputScript, row.OutputScript, row.InputLocale)
+	if err != nil {
+		return ConfidenceFactors{}, 0, false, err
+	}
+	factors := calculateConfidence(row.InputText, row.OutputText, row.InputScript, row.OutputScript, result.Confidence)
+	return factors, result.Confidence, result.Output != row.OutputText, nil
+}
+
+// convertWithDefaults converts text as a request with default options would, in inputLocale
+// or else the language detected in the text
+func convertWithDefaults(ctx context.Context, text, inputScript, outputScript string, inputLocale *string) (*transliteration.Result, error) {
+	// Fall back to the detected language, as a request does
+	locale := detection.DetectLanguage(text, detection.DetectScript(text)).Language
+	if inputLocale != nil {
+		locale = *inputLocale
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UmlautExpansion = expandsGermanUmlauts(nil, &locale)
+	engine := transliteration.NewEngine(config, db)
+
+	result, _, err := transliterateName(ctx, engine, text, inputScript, outputScript, locale)
+	return result, err
+}
@@ -0,0 +1,269 @@
+package transliterate
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"encore.app/transliterate/internal/gender"
+	"encore.app/transliterate/internal/nameparser"
+	"encore.app/transliterate/internal/transliteration"
+)
+
+// SchemaDocument is a JSON Schema (draft-07) description of the public API types
+type SchemaDocument struct {
+	Schema      string                 `json:"$schema"`
+	Title       string                 `json:"title"`
+	Definitions map[string]*JSONSchema `json:"definitions"`
+}
+
+// JSONSchema describes a single type or property
+type JSONSchema struct {
+	Type                 any                    `json:"type,omitempty"` // string, or []string for nullable values
+	Ref                  string                 `json:"$ref,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+}
+
+// schemaTypes lists the API types published in the schema, keyed by definition name
+var schemaTypes = map[string]reflect.Type{
+	"TransliterationRequest":  reflect.TypeOf(TransliterationRequest{}),
+	"TransliterationResponse": reflect.TypeOf(TransliterationResponse{}),
+	"FeedbackRequest":         reflect.TypeOf(FeedbackRequest{}),
+	"ParseNameRequest":        reflect.TypeOf(ParseNameRequest{}),
+	"ParseNameResponse":       reflect.TypeOf(ParseNameResponse{}),
+	"ValidateNameRequest":     reflect.TypeOf(ValidateNameRequest{}),
+	"ValidateNameResponse":    reflect.TypeOf(ValidateNameResponse{}),
+	"NameWarning":             reflect.TypeOf(NameWarning{}),
+	"NameStructure":           reflect.TypeOf(NameStructure{}),
+	"GenderInference":         reflect.TypeOf(GenderInference{}),
+	"LanguageHint":            reflect.TypeOf(LanguageHint{}),
+	"TransliterationSegment":  reflect.TypeOf(TransliterationSegment{}),
+	"ScriptDetection":         reflect.TypeOf(ScriptDetection{}),
+	"PhoneticKeys":            reflect.TypeOf(PhoneticKeys{}),
+	"Pronunciation":           reflect.TypeOf(Pronunciation{}),
+	"ConfidenceFactors":       reflect.TypeOf(ConfidenceFactors{}),
+	"NormalizeRequest":        reflect.TypeOf(NormalizeRequest{}),
+	"NormalizeResponse":       reflect.TypeOf(NormalizeResponse{}),
+	"ScoreRequest":            reflect.TypeOf(ScoreRequest{}),
+	"ScoreResponse":           reflect.TypeOf(ScoreResponse{}),
+	"StatsResponse":           reflect.TypeOf(StatsResponse{}),
+	"TopTransliteration":      reflect.TypeOf(TopTransliteration{}),
+	"ScriptPairCount":         reflect.TypeOf(ScriptPairCount{}),
+	"ConfidenceHistogram":     reflect.TypeOf(ConfidenceHistogram{}),
+	"ConfidenceBucket":        reflect.TypeOf(ConfidenceBucket{}),
+	"TransliterationPage":     reflect.TypeOf(TransliterationPage{}),
+	"StoredTransliteration":   reflect.TypeOf(StoredTransliteration{}),
+	"ScriptsResponse":         reflect.TypeOf(ScriptsResponse{}),
+	"SupportedScriptPair":     reflect.TypeOf(SupportedScriptPair{}),
+	"ImportMappingsRequest":   reflect.TypeOf(ImportMappingsRequest{}),
+	"CharacterMapping":        reflect.TypeOf(CharacterMapping{}),
+	"ImportMappingsResponse":  reflect.TypeOf(ImportMappingsResponse{}),
+	"FieldError":              reflect.TypeOf(FieldError{}),
+	"PendingFeedbackResponse": reflect.TypeOf(PendingFeedbackResponse{}),
+	"PendingFeedback":         reflect.TypeOf(PendingFeedback{}),
+	"FeedbackReview":          reflect.TypeOf(FeedbackReview{}),
+	"ProfileOptions":          reflect.TypeOf(ProfileOptions{}),
+	"Profile":                 reflect.TypeOf(Profile{}),
+	"ProfileList":             reflect.TypeOf(ProfileList{}),
+}
+
+// schemaEnums returns the valid values for enum-like fields, keyed by "Definition.json_field"
+func schemaEnums() map[string][]string {
+	scripts := sortedKeys(validScripts)
+	nameFormats := sortedKeys(nameparser.NameFormats)
+	boundarySpacings := sortedKeys(validBoundarySpacings)
+	longVowels := sortedKeys(validLongVowels)
+	unmappedPolicies := sortedKeys(validUnmappedPolicies)
+	return map[string][]string{
+		"TransliterationRequest.input_script":              scripts,
+		"TransliterationRequest.output_script":             scripts,
+		"TransliterationRequest.additional_output_scripts": scripts,
+		"TransliterationRequest.boundary_spacing":          boundarySpacings,
+		"TransliterationRequest.standard":                  sortedStandards(),
+		"TransliterationRequest.long_vowels":               longVowels,
+		"TransliterationRequest.invalid_code_points":       sortedKeys(validCodePointPolicies),
+		"TransliterationRequest.script_mismatch":           sortedKeys(validScriptMismatchPolicies),
+		"TransliterationRequest.name_format":               nameFormats,
+		"TransliterationRequest.unmapped_policy":           unmappedPolicies,
+		"ProfileOptions.output_script":                     scripts,
+		"ProfileOptions.boundary_spacing":                  boundarySpacings,
+		"ProfileOptions.standard":                          sortedStandards(),
+		"ProfileOptions.long_vowels":                       longVowels,
+		"ProfileOptions.name_format":                       nameFormats,
+		"ProfileOptions.unmapped_policy":                   unmappedPolicies,
+		"ParseNameRequest.name_format":                     nameFormats,
+		"ValidateNameRequest.culture":                      sortedKeys(nameparser.Cultures),
+		"NameWarning.code":                                 {nameparser.WarningUnexpectedCharacters, nameparser.WarningUnexpectedTokenCount},
+		"FeedbackRequest.feedback_type":                    sortedKeys(validFeedbackTypes),
+		"FeedbackReview.status":                            {feedbackApproved, feedbackRejected},
+		"CharacterMapping.source_script":                   scripts,
+		"CharacterMapping.target_script":                   scripts,
+		"NormalizeRequest.form":                            sortedKeys(normalizationForms),
+		"ScoreRequest.input_script":                        scripts,
+		"ScoreRequest.output_script":                       scripts,
+		"StoredTransliteration.input_script":               scripts,
+		"StoredTransliteration.output_script":              scripts,
+		"SupportedScriptPair.quality":                      {qualityHigh, qualityMedium, qualityLow, qualityUnrated},
+		"GenderInference.value":                            {gender.Female, gender.Male, gender.NonBinary, gender.Unknown},
+	}
+}
+
+// GetSchema returns a machine-readable JSON Schema for the request and response types
+//
+//encore:api public method=GET path=/api/schema
+func GetSchema(ctx context.Context) (*SchemaDocument, error) {
+	return buildSchemaDocument(), nil
+}
+
+// buildSchemaDocument reflects over the API types to produce the schema document
+func buildSchemaDocument() *SchemaDocument {
+	doc := &SchemaDocument{
+		Schema:      "http://json-schema.org/draft-07/schema#",
+		Title:       "Transliteration API",
+		Definitions: make(map[string]*JSONSchema),
+	}
+
+	names := make(map[reflect.Type]string, len(schemaTypes))
+	for name, t := range schemaTypes {
+		names[t] = name
+	}
+
+	enums := schemaEnums()
+	for name, t := range schemaTypes {
+		doc.Definitions[name] = structSchema(name, t, names, enums)
+	}
+
+	return doc
+}
+
+// structSchema builds the object schema for a struct type from its json tags
+func structSchema(name string, t reflect.Type, names map[reflect.Type]string, enums map[string][]string) *JSONSchema {
+	closed := false
+	schema := &JSONSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: &closed,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Headers and query parameters are not part of the JSON body
+		if field.Tag.Get("header") != "" || field.Tag.Get("query") != "" {
+			continue
+		}
+
+		jsonName, omitEmpty := parseJSONTag(field)
+		if jsonName == "-" {
+			continue
+		}
+
+		property := typeSchema(field.Type, names)
+		if values, ok := enums[name+"."+jsonName]; ok {
+			// A list's values are its items' enum
+			if property.Items != nil {
+				property.Items.Enum = values
+			} else {
+				property.Enum = values
+			}
+		}
+		schema.Properties[jsonName] = property
+
+		if !omitEmpty {
+			schema.Required = append(schema.Required, jsonName)
+		}
+	}
+
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON Schema representation
+func typeSchema(t reflect.Type, names map[reflect.Type]string) *JSONSchema {
+	if t.Kind() == reflect.Ptr {
+		inner := typeSchema(t.Elem(), names)
+		// Nullable pointers to named types can't combine $ref with type, so only scalars gain "null"
+		if typeName, ok := inner.Type.(string); ok {
+			inner.Type = []string{typeName, "null"}
+		}
+		return inner
+	}
+
+	if name, ok := names[t]; ok {
+		return &JSONSchema{Ref: "#/definitions/" + name}
+	}
+
+	// Timestamps are encoded as RFC 3339 strings
+	if t == reflect.TypeOf(time.Time{}) {
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: typeSchema(t.Elem(), names)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object"}
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// parseJSONTag returns the JSON field name and whether it is omitted when empty
+func parseJSONTag(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty
+}
+
+// sortedKeys returns the keys of a set or lookup table in sorted order
+func sortedKeys[V any](set map[string]V) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedStandards returns the selectable romanization standards in sorted order
+func sortedStandards() []string {
+	standards := make([]string, 0, len(transliteration.StandardScripts))
+	for standard := range transliteration.StandardScripts {
+		standards = append(standards, standard)
+	}
+	sort.Strings(standards)
+	return standards
+}
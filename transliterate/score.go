@@ -0,0 +1,88 @@
+package transliterate
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ScoreRequest represents a conversion produced elsewhere, to be scored without transliterating
+type ScoreRequest struct {
+	InputText    string `json:"input_text"`    // Source text
+	OutputText   string `json:"output_text"`   // Conversion of the source text to score
+	InputScript  string `json:"input_script"`  // Script of input_text
+	OutputScript string `json:"output_script"` // Script of output_text
+}
+
+// ScoreResponse reports the confidence a conversion would be given
+type ScoreResponse struct {
+	ConfidenceScore   float64            `json:"confidence_score"`   // Same as confidence_factors.score
+	ConfidenceFactors *ConfidenceFactors `json:"confidence_factors"` // Why the output looks as reliable as it does
+}
+
+// Score rates how plausibly output_text converts input_text, with the confidence factors used
+// for transliterations, so romanizations from other systems can be compared. The input is
+// converted with default options for its per-character confidence; nothing is stored.
+//
+//encore:api public method=POST path=/api/score
+func Score(ctx context.Context, req *ScoreRequest) (*ScoreResponse, error) {
+	if err := validateScoreRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Texts are composed as transliteration input is, so decomposed text scores the same
+	inputText, outputText := norm.NFC.String(req.InputText), norm.NFC.String(req.OutputText)
+
+	// The input's own conversion gives the per-character confidence a transliteration would have
+	result, err := convertWithDefaults(ctx, inputText, req.InputScript, req.OutputScript, nil)
+	if err != nil {
+		return nil, internalError(ReasonTransliterationFailed, err, "failed to score conversion")
+	}
+	factors := calculateConfidence(inputText, outputText, req.InputScript, req.OutputScript, result.Confidence)
+	return &ScoreResponse{ConfidenceScore: factors.Score, ConfidenceFactors: &factors}, nil
+}
+
+// validateScoreRequest validates a scoring request, reporting every invalid field at once
+func validateScoreRequest(req *ScoreRequest) error {
+	if req == nil {
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
+	}
+
+	var problems validationErrors
+
+	for _, text := range []struct{ field, value string }{
+		{"input_text", req.InputText},
+		{"output_text", req.OutputText},
+	} {
+		if strings.TrimSpace(text.value) == "" {
+			problems.add(text.field, ReasonTextEmpty, "%s cannot be empty", text.field)
+		} else if utf8.RuneCountInString(text.value) > defaultMaxTextLength {
+			problems.add(text.field, ReasonTextTooLong, "%s too long (maximum %d characters)", text.field, defaultMaxTextLength)
+		} else if !utf8.ValidString(text.value) {
+			problems.add(text.field, ReasonInvalidUTF8, "%s contains invalid UTF-8 sequences", text.field)
+		}
+	}
+
+	if req.InputScript == "" {
+		problems.add("input_script", ReasonInputScriptRequired, "input_script is required")
+	} else if !validScripts[req.InputScript] {
+		problems.add("input_script", ReasonUnsupportedInputScript, "unsupported input script: %s", req.InputScript)
+	}
+
+	if req.OutputScript == "" {
+		problems.add("output_script", ReasonOutputScriptRequired, "output_script is required")
+	} else if !validScripts[req.OutputScript] {
+		problems.add("output_script", ReasonUnsupportedOutputScript, "unsupported output script: %s", req.OutputScript)
+	}
+
+	if err := problems.err(); err != nil {
+		return err
+	}
+
+	if !isSupportedScriptPair(req.InputScript, req.OutputScript) {
+		return unsupportedScriptPair(req.InputScript, req.OutputScript)
+	}
+	return nil
+}
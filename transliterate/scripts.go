@@ -0,0 +1,80 @@
+package transliterate
+
+import (
+	"context"
+)
+
+// Quality tiers reported for script pairs, derived from calculateScriptCompatibility
+const (
+	qualityHigh    = "high"
+	qualityMedium  = "medium"
+	qualityLow     = "low"
+	qualityUnrated = "unrated"
+)
+
+// ScriptsResponse describes the scripts and script pairs the API supports
+type ScriptsResponse struct {
+	InputScripts  []string              `json:"input_scripts"`  // Scripts accepted for input_script
+	OutputScripts []string              `json:"output_scripts"` // Scripts accepted for output_script
+	Pairs         []SupportedScriptPair `json:"pairs"`          // Every supported input→output combination
+}
+
+// SupportedScriptPair is one supported input→output script combination
+type SupportedScriptPair struct {
+	InputScript  string `json:"input_script"`
+	OutputScript string `json:"output_script"`
+	Quality      string `json:"quality"` // high, medium, low or unrated
+}
+
+// GetScripts returns the supported scripts and script pairs so clients need not hardcode them
+//
+//encore:api public method=GET path=/api/transliterate/scripts
+func GetScripts(ctx context.Context) (*ScriptsResponse, error) {
+	return buildScriptsResponse(), nil
+}
+
+// buildScriptsResponse lists every valid script pair in a stable order
+func buildScriptsResponse() *ScriptsResponse {
+	resp := &ScriptsResponse{
+		InputScripts:  []string{},
+		OutputScripts: []string{},
+		Pairs:         []SupportedScriptPair{},
+	}
+
+	outputs := make(map[string]bool)
+	for _, input := range sortedKeys(validScripts) {
+		hasPair := false
+		for _, output := range sortedKeys(validScripts) {
+			if !isSupportedScriptPair(input, output) {
+				continue
+			}
+			hasPair = true
+			outputs[output] = true
+			resp.Pairs = append(resp.Pairs, SupportedScriptPair{
+				InputScript:  input,
+				OutputScript: output,
+				Quality:      scriptPairQuality(input, output),
+			})
+		}
+		if hasPair {
+			resp.InputScripts = append(resp.InputScripts, input)
+		}
+	}
+	resp.OutputScripts = append(resp.OutputScripts, sortedKeys(outputs)...)
+
+	return resp
+}
+
+// scriptPairQuality maps a pair's compatibility score to a coarse quality tier
+func scriptPairQuality(inputScript, outputScript string) string {
+	switch compatibility := calculateScriptCompatibility(inputScript, outputScript); {
+	case compatibility >= 0.3:
+		return qualityHigh
+	case compatibility >= 0.2:
+		return qualityMedium
+	case compatibility >= 0.1:
+		return qualityLow
+	default:
+		return qualityUnrated
+	}
+}
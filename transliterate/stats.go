@@ -0,0 +1,194 @@
+package transliterate
+
+import (
+	"context"
+)
+
+// Limits for the number of top transliterations returned by GetStats
+const (
+	defaultStatsLimit = 10
+	maxStatsLimit     = 100
+)
+
+// StatsParams selects how much detail GetStats returns
+type StatsParams struct {
+	Limit int `query:"limit"` // Number of top transliterations to return (default 10, maximum 100)
+}
+
+// StatsResponse summarizes stored transliterations for dashboards
+type StatsResponse struct {
+	TotalCount  int64                `json:"total_count"`  // Number of stored transliterations
+	TotalUsage  int64                `json:"total_usage"`  // Sum of usage_count across all transliterations
+	Top         []TopTransliteration `json:"top"`          // Most-used transliterations, by usage_count
+	ScriptPairs []ScriptPairCount    `json:"script_pairs"` // Breakdown by input and output script
+}
+
+// TopTransliteration is a frequently requested transliteration
+type TopTransliteration struct {
+	ID           string `json:"id"`
+	InputText    string `json:"input_text"`
+	OutputText   string `json:"output_text"`
+	InputScript  string `json:"input_script"`
+	OutputScript string `json:"output_script"`
+	UsageCount   int64  `json:"usage_count"`
+}
+
+// ScriptPairCount aggregates transliterations for one input→output script pair
+type ScriptPairCount struct {
+	InputScript  string `json:"input_script"`
+	OutputScript string `json:"output_script"`
+	Count        int64  `json:"count"`       // Stored transliterations for the pair
+	UsageCount   int64  `json:"usage_count"` // Sum of their usage_count
+}
+
+// GetStats returns aggregate usage of stored transliterations. The top transliterations are
+// the texts people submitted, often names, so only administrators can read them.
+//
+//encore:api auth method=GET path=/api/transliterate/stats
+func GetStats(ctx context.Context, params *StatsParams) (*StatsResponse, error) {
+	limit := defaultStatsLimit
+	if params != nil && params.Limit != 0 {
+		limit = params.Limit
+	}
+	if limit < 1 || limit > maxStatsLimit {
+		return nil, invalidArgument(ReasonInvalidLimit, "limit must be between 1 and %d", maxStatsLimit)
+	}
+
+	stats := &StatsResponse{
+		Top:         []TopTransliteration{},
+		ScriptPairs: []ScriptPairCount{},
+	}
+
+	err := db.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(usage_count), 0)
+		FROM transliterations
+	`).Scan(&stats.TotalCount, &stats.TotalUsage)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to count transliterations")
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, input_text, output_text, input_script, output_script, usage_count
+		FROM transliterations
+		ORDER BY usage_count DESC, updated_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to query top transliterations")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var top TopTransliteration
+		if err := rows.Scan(&top.ID, &top.InputText, &top.OutputText, &top.InputScript, &top.OutputScript, &top.UsageCount); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read top transliterations")
+		}
+		stats.Top = append(stats.Top, top)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read top transliterations")
+	}
+
+	pairs, err := db.Query(ctx, `
+		SELECT input_script, output_script, COUNT(*), COALESCE(SUM(usage_count), 0)
+		FROM transliterations
+		GROUP BY input_script, output_script
+		ORDER BY COUNT(*) DESC, input_script, output_script
+	`)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to query script pairs")
+	}
+	defer pairs.Close()
+
+	for pairs.Next() {
+		var pair ScriptPairCount
+		if err := pairs.Scan(&pair.InputScript, &pair.OutputScript, &pair.Count, &pair.UsageCount); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read script pairs")
+		}
+		stats.ScriptPairs = append(stats.ScriptPairs, pair)
+	}
+	if err := pairs.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read script pairs")
+	}
+
+	return stats, nil
+}
+
+// confidenceBuckets is the number of equal-width buckets of ConfidenceHistogram
+const confidenceBuckets = 5
+
+// ConfidenceHistogramParams filters the transliterations counted by GetConfidenceHistogram
+type ConfidenceHistogramParams struct {
+	InputScript string `query:"input_script"` // Only count transliterations from this script (optional)
+}
+
+// ConfidenceHistogram is the distribution of confidence scores of stored transliterations
+type ConfidenceHistogram struct {
+	InputScript string             `json:"input_script,omitempty"` // Script the counts are filtered to, if any
+	Buckets     []ConfidenceBucket `json:"buckets"`                // Buckets of width 0.2, lowest first
+	Unscored    int64              `json:"unscored"`               // Transliterations without a confidence score
+	Total       int64              `json:"total"`                  // All transliterations counted, scored or not
+}
+
+// ConfidenceBucket counts the transliterations scoring from Min up to Max; the last bucket
+// also includes a score of exactly 1
+type ConfidenceBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int64   `json:"count"`
+}
+
+// GetConfidenceHistogram returns how many stored transliterations fall in each confidence
+// range, for monitoring output quality
+//
+//encore:api public method=GET path=/api/transliterate/confidence-histogram
+func GetConfidenceHistogram(ctx context.Context, params *ConfidenceHistogramParams) (*ConfidenceHistogram, error) {
+	inputScript := ""
+	if params != nil {
+		inputScript = params.InputScript
+	}
+	if inputScript != "" && !validScripts[inputScript] {
+		return nil, invalidArgument(ReasonUnsupportedInputScript, "unsupported input script: %s", inputScript)
+	}
+
+	histogram := &ConfidenceHistogram{
+		InputScript: inputScript,
+		Buckets:     make([]ConfidenceBucket, confidenceBuckets),
+	}
+	for i := range histogram.Buckets {
+		histogram.Buckets[i].Min = float64(i) / confidenceBuckets
+		histogram.Buckets[i].Max = float64(i+1) / confidenceBuckets
+	}
+
+	// width_bucket puts a score of exactly 1 in an overflow bucket, so it is folded into the
+	// last one; unscored rows have a NULL bucket
+	rows, err := db.Query(ctx, `
+		SELECT LEAST(GREATEST(width_bucket(confidence_score, 0, 1, $1), 1), $1), COUNT(*)
+		FROM transliterations
+		WHERE $2 = '' OR input_script = $2
+		GROUP BY 1
+	`, confidenceBuckets, inputScript)
+	if err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to query confidence histogram")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket *int
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to read confidence histogram")
+		}
+		if bucket == nil {
+			histogram.Unscored += count
+		} else {
+			histogram.Buckets[*bucket-1].Count += count
+		}
+		histogram.Total += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ReasonDatabaseError, err, "failed to read confidence histogram")
+	}
+
+	return histogram, nil
+}
@@ -0,0 +1,110 @@
+package transliterate
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"encore.app/transliterate/internal/transliteration"
+
+	"encore.dev/beta/errs"
+)
+
+// streamParams are the query parameters of TransliterateStream
+type streamParams struct {
+	InputScript  string
+	OutputScript string
+	Locale       *string
+}
+
+// TransliterateStream converts a text document sent as the raw request body, writing the
+// output as plain text while the body is still being read. Nothing is stored or cached.
+//
+//encore:api public raw method=POST path=/api/transliterate/stream
+func TransliterateStream(w http.ResponseWriter, req *http.Request) {
+	params, err := parseStreamParams(req.URL.Query())
+	if err != nil {
+		errs.HTTPError(w, err)
+		return
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UmlautExpansion = expandsGermanUmlauts(nil, params.Locale)
+	engine := transliteration.NewEngine(config, db)
+
+	locale := ""
+	if params.Locale != nil {
+		locale = *params.Locale
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	out := &countingWriter{w: w}
+	result, err := engine.TransliterateStream(req.Context(), out, req.Body, params.InputScript, params.OutputScript, locale)
+	if err == nil {
+		logConversion(result, params.InputScript, params.OutputScript)
+		return
+	}
+	if out.n > 0 {
+		// Once output has been sent the status can no longer report the failure, only the log
+		logError("stream failed after output was sent", "input_script", params.InputScript, "output_script", params.OutputScript, "bytes_written", out.n, "err", err)
+		return
+	}
+	if errors.Is(err, transliteration.ErrInvalidUTF8) {
+		errs.HTTPError(w, invalidArgument(ReasonInvalidUTF8, "text contains invalid UTF-8 sequences"))
+		return
+	}
+	errs.HTTPError(w, internalError(ReasonTransliterationFailed, err, "transliteration failed"))
+}
+
+// parseStreamParams validates the query parameters of a streaming request. The input script
+// is required, since detecting it would mean buffering the document.
+func parseStreamParams(query url.Values) (*streamParams, error) {
+	params := &streamParams{
+		InputScript:  query.Get("input_script"),
+		OutputScript: query.Get("output_script"),
+	}
+	if locale := query.Get("locale"); locale != "" {
+		params.Locale = &locale
+	}
+
+	var problems validationErrors
+
+	if params.InputScript == "" {
+		problems.add("input_script", ReasonInputScriptRequired, "input_script is required for streaming")
+	} else if !validScripts[params.InputScript] {
+		problems.add("input_script", ReasonUnsupportedInputScript, "unsupported input script: %s", params.InputScript)
+	}
+
+	if params.OutputScript == "" {
+		problems.add("output_script", ReasonOutputScriptRequired, "output_script is required")
+	} else if !validScripts[params.OutputScript] {
+		problems.add("output_script", ReasonUnsupportedOutputScript, "unsupported output script: %s", params.OutputScript)
+	}
+
+	if params.Locale != nil && !isValidLocale(*params.Locale) {
+		problems.add("locale", ReasonInvalidLocale, "invalid locale format: %s", *params.Locale)
+	}
+
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	if !isSupportedScriptPair(params.InputScript, params.OutputScript) {
+		return nil, unsupportedScriptPair(params.InputScript, params.OutputScript)
+	}
+
+	return params, nil
+}
+
+// countingWriter records how many bytes have been written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -4,10 +4,14 @@ package transliterate
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"embed"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -18,7 +22,9 @@ import (
 	"encore.app/transliterate/internal/detection"
 	"encore.app/transliterate/internal/gender"
 	"encore.app/transliterate/internal/nameparser"
+	"encore.app/transliterate/internal/phonetic"
 	"encore.app/transliterate/internal/transliteration"
+	textnorm "encore.app/transliterate/internal/unicode"
 
 	"encore.dev/storage/sqldb"
 )
@@ -26,19 +32,96 @@ import (
 // TransliterationRequest represents a request to transliterate text
 type TransliterationRequest struct {
 	Text         string  `json:"text"`                    // Text to transliterate
+	Profile      string  `json:"profile,omitempty"`       // Named bundle of options, e.g. 'german-official'; options set in the request take precedence (optional)
 	InputScript  string  `json:"input_script,omitempty"`  // e.g., 'cyrillic', 'chinese', 'arabic' (optional - can auto-detect)
 	OutputScript string  `json:"output_script"`           // e.g., 'latin', 'ascii'
+	AdditionalOutputScripts []string `json:"additional_output_scripts,omitempty"` // Further output scripts to convert to in the same request, e.g. ['ascii'] (optional)
 	InputLocale  *string `json:"input_locale,omitempty"`  // e.g., 'zh-CN', 'ru-RU' (optional)
+	NumberWords  bool    `json:"number_words,omitempty"`  // Convert spelled-out numbers like '三十五' to '35' (optional)
+	BoundarySpacing string `json:"boundary_spacing,omitempty"` // 'always', 'never' or 'smart' (default) spacing at script boundaries
+	Standard     string  `json:"standard,omitempty"`      // Romanization standard, e.g. 'buckwalter' for Arabic (optional)
+	InvalidCodePoints string `json:"invalid_code_points,omitempty"` // 'allow' (default), 'reject' or 'strip' private-use/unassigned code points
+	MaxLength    int     `json:"max_length,omitempty"`    // Raise the character limit for document-sized input, up to 200,000 (optional)
+	ScriptMismatch string `json:"script_mismatch,omitempty"` // 'trust_client' (default), 'trust_detection', 'warn' or 'error' when input_script disagrees with detection
+	InlineOriginal bool   `json:"inline_original,omitempty"` // Return the original alongside the output, e.g. 'Владимир [Vladimir]' (optional)
+	InlineTemplate string `json:"inline_template,omitempty"` // Template for inline_original using {input} and {output}; defaults to '{input} [{output}]'
+	DomainSafe   bool    `json:"domain_safe,omitempty"`   // Reduce ascii output to a DNS label of letters, digits and hyphens, e.g. 'Dr. Jürgen Groß' to 'juergen-gross' (optional)
+	IncludePronunciation bool `json:"include_pronunciation,omitempty"` // Add an approximate IPA pronunciation of Chinese and Arabic names to latin or ascii output (optional)
+	LongVowels   string  `json:"long_vowels,omitempty"`   // Japanese long vowels as 'doubled' (default, tookyoo) or 'macron' (tōkyō)
+	PreserveDiacritics bool `json:"preserve_diacritics,omitempty"` // Keep source accents on latin output, e.g. 'Σοφία' to 'Sophía' (ignored for ascii)
+	GermanUmlautExpansion *bool `json:"german_umlaut_expansion,omitempty"` // Expand umlauts to ae/oe/ue in ascii output of German input (default true); other input folds them to a/o/u
+	Preview      bool    `json:"preview,omitempty"`       // Return the result without storing it or counting a cache hit (optional)
+	CacheOnly    bool    `json:"cache_only,omitempty"`    // Return a cached result or fail with cache_miss, never converting or storing (optional)
+	NameFormat   string  `json:"name_format,omitempty"`   // Order of name.full_ascii: 'given-first', 'family-first' or 'sortable' (default: the culture's own order)
+	MinDetectionConfidence float64 `json:"min_detection_confidence,omitempty"` // Reject auto-detection below this confidence (0-1) instead of guessing (optional)
+	MaxAlternatives int  `json:"max_alternatives,omitempty"` // Most alternative_forms and alternatives to return, most likely first (default 3, maximum 10)
+	Verbose      bool    `json:"verbose,omitempty"`       // Return per-character segments showing which method produced each piece of output (bypasses the cache)
+	IncludeDetectionDetails bool `json:"include_detection_details,omitempty"` // Return the script detection confidence and per-script letter counts (optional)
+	UnmappedPolicy string `json:"unmapped_policy,omitempty"` // Output for characters without a mapping: 'question' ("?"), 'drop', 'keep' or 'unicode-name' (default: question for ascii, keep otherwise)
+	PreserveEmoji  bool   `json:"preserve_emoji,omitempty"`  // Keep emoji as written, even in ascii output (optional)
+	PreserveSymbols bool  `json:"preserve_symbols,omitempty"` // Keep emoji and all other symbols such as '©' and '€' as written, even in ascii output (optional)
+	RespectInputCase bool `json:"respect_input_case,omitempty"` // Keep internal capitals typed in the input (MacArthur, McDONALD) in name, even in the family name
+	GenderDistribution bool `json:"gender_distribution,omitempty"` // Add M/F/X probabilities summing to 1 to gender (optional)
+	InferGender  *bool   `json:"infer_gender,omitempty"`  // Set false to skip gender inference and leave gender out of the response (default true unless disabled for the deployment)
+	APIVersion      string `header:"X-API-Version"` // Response shape: '1' or '2' (default, the current shape)
+	APIVersionQuery string `query:"api_version"`    // Response shape, for clients that can't set headers; X-API-Version takes precedence
 }
 
+// defaultInlineTemplate combines the original and transliterated text for bilingual display
+const defaultInlineTemplate = "{input} [{output}]"
+
+// Text length limits, in characters
+const (
+	defaultMaxTextLength = 10000  // Applies when max_length is not set
+	maxTextLengthCap     = 200000 // Hard cap for max_length
+	maxCachedTextLength  = 10000  // Larger inputs are stored but never looked up in the cache
+)
+
+// maxAdditionalOutputScripts limits additional_output_scripts, each of which is a full conversion
+const maxAdditionalOutputScripts = 3
+
+// Limits on alternative_forms
+const (
+	defaultMaxAlternatives    = 3   // Applies when max_alternatives is not set
+	maxAlternativesCap        = 10  // Hard cap for max_alternatives
+	maxAlternativesTextLength = 100 // Longer text, such as a document, has no alternatives
+)
+
+// cacheTTL is how long a cached transliteration is served before it is recomputed, even when
+// no mapping for its scripts has changed, so improvements to the built-in tables reach old
+// rows too. Zero disables expiry.
+var cacheTTL = 30 * 24 * time.Hour
+
+// Policies for private-use and unassigned code points in input text
+const (
+	codePointsAllow  = "allow"
+	codePointsReject = "reject"
+	codePointsStrip  = "strip"
+)
+
+// Policies for an input_script that disagrees with high-confidence detection
+const (
+	scriptMismatchTrustClient    = "trust_client"
+	scriptMismatchTrustDetection = "trust_detection"
+	scriptMismatchWarn           = "warn"
+	scriptMismatchError          = "error"
+)
+
+// scriptMismatchConfidence is the detection confidence above which a disagreeing input_script is suspect
+const scriptMismatchConfidence = 0.85
+
 // NameStructure represents parsed name components
 type NameStructure = nameparser.NameStructure
 
 // GenderInference represents inferred gender with confidence
 type GenderInference = gender.Inference
 
+// LanguageHint represents the detected language with confidence and indicators
+type LanguageHint = detection.LanguageHint
+
 // TransliterationResponse represents the result of transliteration
 type TransliterationResponse struct {
+	APIVersion       string           `json:"api_version"`              // Version the response is shaped for
 	ID               string           `json:"id"`
 	InputText        string           `json:"input_text"`
 	OutputText       string           `json:"output_text"`
@@ -46,9 +129,65 @@ type TransliterationResponse struct {
 	OutputScript     string           `json:"output_script"`
 	InputLocale      *string          `json:"input_locale,omitempty"`
 	ConfidenceScore  *float64         `json:"confidence_score"`
-	AlternativeForms []string         `json:"alternative_forms,omitempty"`
+	AlternativeForms []string         `json:"alternative_forms,omitempty"` // Other plausible outputs using competing character mappings, most likely first
+	Notes            []string         `json:"notes,omitempty"`          // Warnings and how the text was processed, for fresh conversions
 	Name             *NameStructure   `json:"name,omitempty"`           // Structured name parsing
-	Gender           *GenderInference `json:"gender,omitempty"`         // Gender inference
+	Gender           *GenderInference `json:"gender,omitempty"`         // Gender inference, absent when it is off
+	SearchTokens     []string         `json:"search_tokens,omitempty"`  // Lowercased, diacritic-free tokens for full-text indexing
+	LanguageHint     *LanguageHint    `json:"language_hint,omitempty"`  // Detected language and the indicators behind it
+	FromCache        bool             `json:"from_cache"`               // True when served from a previously stored transliteration
+	Preferred        bool             `json:"preferred,omitempty"`      // True when output_text is the form users preferred over the computed one
+	ConfidenceFactors *ConfidenceFactors `json:"confidence_factors,omitempty"` // Why the output looks as reliable as it does
+	UnmappedCount    int              `json:"unmapped_count"`           // "?" placeholders the output has for characters without a mapping
+	Preview          bool             `json:"preview,omitempty"`        // True when nothing was stored; a fresh result then has no ID
+	Segments         []TransliterationSegment `json:"segments,omitempty"` // Per-character provenance, only for verbose requests
+	Alternatives     []string         `json:"alternatives,omitempty"`   // Other plausible outputs, most likely first, when the conversion had to guess (e.g. restored Vietnamese diacritics)
+	Detection        *ScriptDetection `json:"detection,omitempty"`      // Script detection details, only when include_detection_details is set
+	PhoneticKeys     *PhoneticKeys    `json:"phonetic_keys,omitempty"`  // Sound-alike keys of the parsed names, only for Latin output
+	Pronunciation    *Pronunciation   `json:"pronunciation,omitempty"`  // Approximate IPA reading, only when include_pronunciation is set
+	Outputs          map[string]string `json:"outputs,omitempty"`       // Output text by script, for output_script and each additional_output_scripts entry
+}
+
+// PhoneticKeys are Metaphone keys of the romanized given and family names, for matching
+// records whose spellings differ but sound alike (Catherine and Katherine are both K0RN).
+// Names of several words have a key per word, separated by spaces.
+type PhoneticKeys struct {
+	First  string `json:"first,omitempty"`  // Key of the given name
+	Family string `json:"family,omitempty"` // Key of the family name
+}
+
+// ScriptDetection explains how the input was classified, e.g. why mixed-script text was
+// detected as one script rather than another
+type ScriptDetection struct {
+	Script     string         `json:"script"`     // Dominant script detected in the text
+	Confidence float64        `json:"confidence"` // Detection confidence (0-1)
+	Details    map[string]int `json:"details"`    // Letters counted per script, e.g. {"latin": 5, "cyrillic": 3}
+}
+
+// TransliterationSegment is one piece of a verbose response; concatenating the outputs gives
+// the transliterated text before inline_original is applied
+type TransliterationSegment struct {
+	Input      string  `json:"input"`      // Source character(s); empty for an inserted boundary space
+	Output     string  `json:"output"`     // Text produced for them
+	Method     string  `json:"method"`     // passthrough, standard, database, builtin, surname, fallback, unchanged or boundary
+	Confidence float64 `json:"confidence"` // Confidence of this segment alone
+}
+
+// ParseNameRequest represents a request to parse an already-romanized name
+type ParseNameRequest struct {
+	Text       string `json:"text"`                  // Name to parse (may already be ASCII)
+	Culture    string `json:"culture,omitempty"`     // e.g., 'western', 'chinese', 'arabic' (optional - can auto-detect)
+	Language   string `json:"language,omitempty"`    // e.g., 'vi', 'zh', 'ar' (optional - can auto-detect)
+	NameFormat string `json:"name_format,omitempty"` // Order of name.full_ascii: 'given-first', 'family-first' or 'sortable' (default: the culture's own order)
+	RespectInputCase bool `json:"respect_input_case,omitempty"` // Keep internal capitals typed in the input (MacArthur, McDONALD), even in the family name
+	GenderDistribution bool `json:"gender_distribution,omitempty"` // Add M/F/X probabilities summing to 1 to gender (optional)
+	InferGender *bool `json:"infer_gender,omitempty"` // Set false to skip gender inference and leave gender out of the response (default true unless disabled for the deployment)
+}
+
+// ParseNameResponse represents the structured result of name parsing
+type ParseNameResponse struct {
+	Name   *NameStructure   `json:"name"`
+	Gender *GenderInference `json:"gender,omitempty"` // Absent when gender inference is off
 }
 
 // FeedbackRequest represents user feedback on transliteration results
@@ -64,93 +203,214 @@ type FeedbackRequest struct {
 //encore:api public method=POST path=/transliterate
 func Transliterate(ctx context.Context, req *TransliterationRequest) (*TransliterationResponse, error) {
 	start := time.Now()
+
+	// A profile fills in the options the request leaves unset
+	req, err := applyProfile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Validate input
 	if err := validateTransliterationRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, err
+	}
+	if len(req.AdditionalOutputScripts) > 0 {
+		return transliterateToScripts(ctx, req)
+	}
+
+	// Strip private-use and unassigned code points (font hacks, corrupted data) when requested
+	text, warnings, err := applyCodePointPolicy(req.Text, req.InvalidCodePoints)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bidi marks are invisible formatting; left in, they skew detection and confidence
+	text, err = stripBidiControls(text)
+	if err != nil {
+		return nil, err
 	}
 
+	// Compose before detection, mapping and the cache lookup so NFD and NFC input behave alike
+	text = textnorm.ComposeNFC(text)
+
 	// Initialize engines
-	transliterationEngine := transliteration.NewEngine(transliteration.DefaultConfig(), db)
-	nameParser := nameparser.NewParser(true, true) // preserveOriginal, strictCultural
-	genderEngine := gender.NewEngine(true, false)  // useStatistical, culturalOnly
+	config := transliteration.DefaultConfig()
+	config.NumberWords = req.NumberWords
+	if req.BoundarySpacing != "" {
+		config.BoundarySpacing = req.BoundarySpacing
+	}
+	config.Standard = req.Standard
+	if req.LongVowels != "" {
+		config.LongVowels = req.LongVowels
+	}
+	config.PreserveDiacritics = req.PreserveDiacritics
+	config.Trace = req.Verbose
+	config.UnmappedPolicy = req.UnmappedPolicy
+	config.PreserveEmoji = req.PreserveEmoji
+	config.PreserveSymbols = req.PreserveSymbols
 
 	// Detect input script if not provided
 	inputScript := req.InputScript
-	scriptInfo := detection.DetectScript(req.Text)
+	detectScript := detection.DetectScript
+	if req.IncludeDetectionDetails {
+		// The reported letter counts cover the whole text, not the sample detection may stop at
+		detectScript = detection.DetectScriptFull
+	}
+	scriptInfo := detectScript(text)
 	if inputScript == "" {
-		inputScript = scriptInfo.Script
-		if inputScript == "unknown" {
-			return nil, errors.New("unable to detect input script")
+		inputScript, err = detectInputScript(scriptInfo, req.MinDetectionConfidence)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Catch clients passing the wrong script for the text
+		var warning string
+		inputScript, warning, err = applyScriptMismatchPolicy(inputScript, req.ScriptMismatch, scriptInfo)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
 		}
 	}
+	logDetection(scriptInfo, inputScript)
 
 	// Detect language for cultural context
-	languageHint := detection.DetectLanguage(req.Text, scriptInfo)
+	languageHint := detection.DetectLanguage(text, scriptInfo)
 	locale := req.InputLocale
 	if locale == nil && languageHint.Language != "unknown" {
 		locale = &languageHint.Language
 	}
+	config.UmlautExpansion = expandsGermanUmlauts(req.GermanUmlautExpansion, locale)
+	transliterationEngine := transliteration.NewEngine(config, db)
 
 	// Validate script combination
 	if !isSupportedScriptPair(inputScript, req.OutputScript) {
-		return nil, fmt.Errorf("unsupported script conversion: %s to %s", inputScript, req.OutputScript)
+		return nil, unsupportedScriptPair(inputScript, req.OutputScript)
+	}
+
+	// An explicit locale selects locale-specific schemes (Serbian ј j, Western Armenian) and
+	// casing rules (Turkish İ/ı) over the detected language
+	engineLocale := languageHint.Language
+	if req.InputLocale != nil {
+		engineLocale = *req.InputLocale
 	}
 
-	// Check if we have this transliteration cached
-	cached, err := getCachedTransliteration(ctx, req.Text, inputScript, req.OutputScript, req.InputLocale)
-	if err == nil && cached != nil {
+	// Check if we have this transliteration cached with the same options (documents are rarely repeated);
+	// verbose requests need the engine's own segments, so they always convert afresh
+	optionsHash := transliterationOptionsHash(req)
+	var cached *TransliterationResponse
+	var stale bool
+	if utf8.RuneCountInString(text) <= maxCachedTextLength && !req.Verbose {
+		cached, stale, err = getCachedTransliteration(ctx, text, inputScript, req.OutputScript, req.InputLocale, optionsHash)
+		logCacheLookup(cached, stale, err)
+	}
+	if err == nil && cached != nil && !stale {
 		// Parse name structure and gender for cached results (they may not be stored)
-		if cached.Name == nil {
+		if cached.Name == nil || cached.Gender == nil {
 			culture := determineCulture(inputScript, languageHint.Language)
-			parsed := nameParser.ParseName(req.Text, cached.OutputText, culture, languageHint.Language)
-			cached.Name = parsed
+			cached.Name, cached.Gender = analyzeName(text, cached.OutputText, culture, engineLocale, infersGender(req.InferGender))
 		}
-		if cached.Gender == nil {
-			culture := determineCulture(inputScript, languageHint.Language)
-			inferred := genderEngine.InferGender(req.Text, cached.OutputText, culture, languageHint.Language)
-			cached.Gender = inferred
+		if req.RespectInputCase {
+			nameparser.RespectInputCase(cached.Name, cached.OutputText)
+		}
+		applyNameFormat(cached.Name, req.NameFormat)
+		if req.GenderDistribution && cached.Gender != nil {
+			cached.Gender.Distribution = gender.Distribution(cached.Gender)
+		}
+		cached.PhoneticKeys = buildPhoneticKeys(cached.Name, cached.OutputScript)
+		cached.SearchTokens = buildSearchTokens(cached.Name, cached.OutputText, cached.PhoneticKeys)
+		cached.LanguageHint = responseLanguageHint(languageHint)
+		cached.FromCache = true
+		cached.UnmappedCount = countUnmapped(cached.InputText, cached.OutputText)
+		cached.Preview = req.Preview
+		cached.AlternativeForms = alternativeForms(ctx, transliterationEngine, cached.OutputText, text, inputScript, languageHint.Language, req)
+		cached.Alternatives = limitAlternatives(transliterationEngine.GuessedAlternatives(text, inputScript, req.OutputScript), req.MaxAlternatives)
+		if req.IncludeDetectionDetails {
+			cached.Detection = scriptDetection(scriptInfo)
 		}
 
-		// Update usage count
-		_, updateErr := db.Exec(ctx, `
-			UPDATE transliterations
-			SET usage_count = usage_count + 1, updated_at = NOW()
-			WHERE id = $1
-		`, cached.ID)
-		if updateErr != nil {
-			// Log but don't fail - return cached result anyway
+		// Update usage count; previews are not counted
+		if !req.Preview {
+			countCacheHit(ctx, db, cached.ID)
 		}
+		applyPronunciation(cached, req)
+		applyInlineOriginal(cached, req)
+		applyDomainSafe(cached, req)
+		applyAPIVersion(cached, requestedAPIVersion(req.APIVersion, req.APIVersionQuery))
 		return cached, nil
 	}
 
+	// Latency-sensitive clients would rather miss than wait for a conversion; a stale row
+	// would be recomputed, so it is a miss too
+	if req.CacheOnly {
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, internalError(ReasonDatabaseError, err, "database error")
+		}
+		return nil, notFound(ReasonCacheMiss, "transliteration not cached")
+	}
+
 	// Perform transliteration using the new engine
-	transliterationResult, err := transliterationEngine.Transliterate(ctx, req.Text, inputScript, req.OutputScript, languageHint.Language)
+	transliterationResult, honorifics, err := transliterateName(ctx, transliterationEngine, text, inputScript, req.OutputScript, engineLocale)
 	if err != nil {
-		return nil, fmt.Errorf("transliteration failed: %w", err)
+		return nil, internalError(ReasonTransliterationFailed, err, "transliteration failed")
+	}
+	logConversion(transliterationResult, inputScript, req.OutputScript)
+	for _, honorific := range honorifics {
+		warnings = append(warnings, fmt.Sprintf("Honorific '%s' recognized and removed from output", honorific.Native))
 	}
 
 	outputText := transliterationResult.Output
 	
-	// Parse name structure from transliterated text
+	// Parse name structure and infer gender from name and cultural markers
 	culture := determineCulture(inputScript, languageHint.Language)
-	nameStructure := nameParser.ParseName(req.Text, outputText, culture, languageHint.Language)
-
-	// Infer gender from name and cultural markers
-	genderInference := genderEngine.InferGender(req.Text, outputText, culture, languageHint.Language)
-
-	// Store the result
-	result, err := storeTransliteration(ctx, req.Text, outputText, inputScript, req.OutputScript, req.InputLocale, transliterationResult.Confidence)
-	if err != nil {
-		return nil, fmt.Errorf("failed to store transliteration: %w", err)
+	nameStructure, genderInference := analyzeName(text, outputText, culture, engineLocale, infersGender(req.InferGender))
+	if req.RespectInputCase {
+		nameparser.RespectInputCase(nameStructure, outputText)
+	}
+	applyNameFormat(nameStructure, req.NameFormat)
+
+	// Store the result, unless the client only wants a preview; a stale cached row is
+	// updated in place so its ID and usage count carry over
+	var result *TransliterationResponse
+	if req.Preview {
+		result = newTransliterationResponse("", text, outputText, inputScript, req.OutputScript, req.InputLocale)
+		applyConfidence(result, transliterationResult.Confidence)
+		result.Preview = true
+	} else if cached != nil && stale {
+		result, err = refreshTransliteration(ctx, cached, outputText, transliterationResult.Confidence)
+		if err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to update transliteration")
+		}
+	} else {
+		result, err = storeTransliteration(ctx, text, outputText, inputScript, req.OutputScript, req.InputLocale, optionsHash, transliterationResult.Confidence)
+		if err != nil {
+			return nil, internalError(ReasonDatabaseError, err, "failed to store transliteration")
+		}
 	}
 
 	// Add structured name parsing and gender inference to response
 	result.Name = nameStructure
 	result.Gender = genderInference
+	if req.GenderDistribution && result.Gender != nil {
+		result.Gender.Distribution = gender.Distribution(result.Gender)
+	}
+	result.PhoneticKeys = buildPhoneticKeys(nameStructure, result.OutputScript)
+	result.SearchTokens = buildSearchTokens(nameStructure, outputText, result.PhoneticKeys)
+	result.LanguageHint = responseLanguageHint(languageHint)
+	result.UnmappedCount = countUnmapped(result.InputText, result.OutputText)
+	result.AlternativeForms = alternativeForms(ctx, transliterationEngine, outputText, text, inputScript, languageHint.Language, req)
+	result.Alternatives = limitAlternatives(transliterationResult.Alternatives, req.MaxAlternatives)
+	if req.IncludeDetectionDetails {
+		result.Detection = scriptDetection(scriptInfo)
+	}
+	if req.Verbose {
+		result.Segments = transliterationSegments(transliterationResult.Segments)
+	}
 	
 	// Add processing notes
 	notes := make([]string, 0)
+	notes = append(notes, warnings...)
 	notes = append(notes, transliterationResult.Notes...)
 	notes = append(notes, fmt.Sprintf("Script detected: %s (%.2f confidence)", scriptInfo.Script, scriptInfo.Confidence))
 	if languageHint.Language != "unknown" {
@@ -158,51 +418,170 @@ func Transliterate(ctx context.Context, req *TransliterationRequest) (*Translite
 	}
 	notes = append(notes, fmt.Sprintf("Processing time: %v", time.Since(start)))
 	
-	result.AlternativeForms = notes
+	result.Notes = notes
+	applyPronunciation(result, req)
+	applyInlineOriginal(result, req)
+	applyDomainSafe(result, req)
+	applyAPIVersion(result, requestedAPIVersion(req.APIVersion, req.APIVersionQuery))
+
+	return result, nil
+}
+
+// alternativeForms returns up to max_alternatives other plausible conversions of text, most
+// likely first. They are optional, so a failed lookup is logged and leaves them out rather
+// than failing the request.
+func alternativeForms(ctx context.Context, engine *transliteration.Engine, primary, text, inputScript, locale string, req *TransliterationRequest) []string {
+	if utf8.RuneCountInString(text) > maxAlternativesTextLength {
+		return nil
+	}
+	alternatives, err := engine.Alternatives(ctx, primary, text, inputScript, req.OutputScript, locale)
+	if err != nil {
+		logError("failed to find alternative forms", "err", err)
+		return nil
+	}
+	return limitAlternatives(alternatives, req.MaxAlternatives)
+}
 
+// limitAlternatives keeps the most likely alternatives, up to max_alternatives (or the default
+// when unset)
+func limitAlternatives(alternatives []string, maxAlternatives int) []string {
+	if maxAlternatives == 0 {
+		maxAlternatives = defaultMaxAlternatives
+	}
+	if len(alternatives) > maxAlternatives {
+		return alternatives[:maxAlternatives]
+	}
+	return alternatives
+}
+
+// transliterateToScripts converts the text to output_script and then to each additional
+// output script, as separate requests with their own cache entries. The response is the
+// one for output_script, with every script's output text in Outputs.
+func transliterateToScripts(ctx context.Context, req *TransliterationRequest) (*TransliterationResponse, error) {
+	primary := *req
+	primary.AdditionalOutputScripts = nil
+	result, err := Transliterate(ctx, &primary)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Outputs = map[string]string{result.OutputScript: result.OutputText}
+	for _, script := range req.AdditionalOutputScripts {
+		// Convert from the script the primary conversion settled on, so detection runs once
+		additional := primary
+		additional.OutputScript = script
+		additional.InputScript = result.InputScript
+		additional.Verbose = false
+		additional.IncludeDetectionDetails = false
+		additional.DomainSafe = false // Only ascii output becomes a label, and that is output_script
+		converted, err := Transliterate(ctx, &additional)
+		if err != nil {
+			return nil, err
+		}
+		result.Outputs[script] = converted.OutputText
+	}
 	return result, nil
 }
 
+// scriptDetection converts script detection results for the response
+func scriptDetection(info detection.ScriptInfo) *ScriptDetection {
+	details := info.Details
+	if details == nil {
+		details = map[string]int{}
+	}
+	return &ScriptDetection{Script: info.Script, Confidence: info.Confidence, Details: details}
+}
+
+// transliterateName converts a name the way POST /transliterate does: native-script
+// honorifics (女士, 先生, 씨, さん) and romanized ones (-san, -ssi) are left out of the output,
+// and Chinese surnames with special readings (单 Shan, 解 Xie) are read in the family-name position.
+// The confidence is clamped to the script pair's bounds.
+func transliterateName(ctx context.Context, engine *transliteration.Engine, text, inputScript, outputScript, locale string) (*transliteration.Result, []nameparser.Honorific, error) {
+	nameText, native := nameparser.ExtractNativeHonorific(text)
+	nameText, honorifics := nameparser.ExtractRomanizedHonorifics(nameText)
+	if native != nil {
+		honorifics = append([]nameparser.Honorific{*native}, honorifics...)
+	}
+
+	surnameRunes := 0
+	if inputScript == "chinese" {
+		surnameRunes = nameparser.ChineseSurnameLength(nameText)
+	}
+
+	result, err := engine.TransliterateName(ctx, nameText, inputScript, outputScript, locale, surnameRunes)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Confidence = boundConfidence(inputScript, outputScript, result.Confidence)
+	return result, honorifics, nil
+}
+
+// transliterationSegments converts the engine's segments for a verbose response
+func transliterationSegments(segments []transliteration.Segment) []TransliterationSegment {
+	result := make([]TransliterationSegment, len(segments))
+	for i, segment := range segments {
+		result[i] = TransliterationSegment{
+			Input:      segment.Source,
+			Output:     segment.Output,
+			Method:     segment.Method,
+			Confidence: segment.Confidence,
+		}
+	}
+	return result
+}
+
 // GetTransliteration retrieves a previously stored transliteration by ID
 //
 //encore:api public method=GET path=/transliterate/:id
 func GetTransliteration(ctx context.Context, id string) (*TransliterationResponse, error) {
 	// Validate UUID format
 	if !isValidUUID(id) {
-		return nil, errors.New("invalid transliteration ID format")
+		return nil, invalidArgument(ReasonInvalidID, "invalid transliteration ID format")
 	}
 
 	var result TransliterationResponse
 	var inputLocale *string
+	var characterConfidence float64
 
 	err := db.QueryRow(ctx, `
-		SELECT id, input_text, output_text, input_script, output_script, input_locale, confidence_score
+		SELECT id, input_text, COALESCE(preferred_output, output_text), input_script, output_script, input_locale,
+			COALESCE(character_confidence, confidence_score, 0), preferred_output IS NOT NULL
 		FROM transliterations
 		WHERE id = $1
 	`, id).Scan(&result.ID, &result.InputText, &result.OutputText, &result.InputScript,
-		&result.OutputScript, &inputLocale, &result.ConfidenceScore)
+		&result.OutputScript, &inputLocale, &characterConfidence, &result.Preferred)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("transliteration not found")
+		return nil, notFound(ReasonNotFound, "transliteration not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("database error: %w", err)
+		return nil, internalError(ReasonDatabaseError, err, "database error")
 	}
 
 	result.InputLocale = inputLocale
+	applyConfidence(&result, characterConfidence)
+	annotateStoredTransliteration(&result)
+	applyAPIVersion(&result, currentAPIVersion)
 
-	// Add name parsing and gender inference for retrieved records
-	nameParser := nameparser.NewParser(true, true)
-	genderEngine := gender.NewEngine(true, false)
-	
+	return &result, nil
+}
+
+// annotateStoredTransliteration adds name parsing, gender inference and the other derived
+// fields that are computed rather than stored
+func annotateStoredTransliteration(result *TransliterationResponse) {
 	scriptInfo := detection.DetectScript(result.InputText)
 	languageHint := detection.DetectLanguage(result.InputText, scriptInfo)
 	culture := determineCulture(result.InputScript, languageHint.Language)
+	nameLanguage := languageHint.Language
+	if result.InputLocale != nil {
+		nameLanguage = *result.InputLocale
+	}
 	
-	result.Name = nameParser.ParseName(result.InputText, result.OutputText, culture, languageHint.Language)
-	result.Gender = genderEngine.InferGender(result.InputText, result.OutputText, culture, languageHint.Language)
-
-	return &result, nil
+	result.Name, result.Gender = analyzeName(result.InputText, result.OutputText, culture, nameLanguage, !genderInferenceDisabled())
+	result.PhoneticKeys = buildPhoneticKeys(result.Name, result.OutputScript)
+	result.SearchTokens = buildSearchTokens(result.Name, result.OutputText, result.PhoneticKeys)
+	result.LanguageHint = responseLanguageHint(languageHint)
+	result.UnmappedCount = countUnmapped(result.InputText, result.OutputText)
 }
 
 // SubmitFeedback allows users to provide feedback on transliteration results
@@ -211,28 +590,207 @@ func GetTransliteration(ctx context.Context, id string) (*TransliterationRespons
 func SubmitFeedback(ctx context.Context, id string, req *FeedbackRequest) error {
 	// Validate feedback request
 	if err := validateFeedbackRequest(req); err != nil {
-		return fmt.Errorf("invalid feedback: %w", err)
+		return err
 	}
 
-	// Verify the transliteration exists
-	_, err := GetTransliteration(ctx, id)
+	// Verify the transliteration exists (its error already carries the code and reason)
+	original, err := GetTransliteration(ctx, id)
 	if err != nil {
-		return fmt.Errorf("invalid transliteration ID: %w", err)
+		return err
 	}
 
 	// Store feedback
+	client := feedbackClient()
 	_, err = db.Exec(ctx, `
-		INSERT INTO transliteration_feedback (transliteration_id, suggested_output, feedback_type, user_context)
-		VALUES ($1, $2, $3, $4)
-	`, id, req.SuggestedOutput, req.FeedbackType, req.UserContext)
+		INSERT INTO transliteration_feedback (transliteration_id, suggested_output, feedback_type, user_context, client_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+	`, id, req.SuggestedOutput, req.FeedbackType, req.UserContext, client)
 
 	if err != nil {
-		return fmt.Errorf("failed to store feedback: %w", err)
+		return internalError(ReasonDatabaseError, err, "failed to store feedback")
+	}
+
+	// Corrections feed back into the character mappings once corroborated, and preferred
+	// forms replace the cached output
+	switch req.FeedbackType {
+	case "correction":
+		if learnErr := learnFromCorrection(ctx, original, req.SuggestedOutput, client); learnErr != nil {
+			// The feedback itself is stored, so learning from it can be retried
+			logError("failed to learn from correction", "transliteration_id", id, "err", learnErr)
+		}
+	case "preferred":
+		if preferErr := applyPreferredOutput(ctx, original, req.SuggestedOutput, client); preferErr != nil {
+			// The feedback itself is stored, so the preferred form can be applied later
+			logError("failed to apply preferred output", "transliteration_id", id, "err", preferErr)
+		}
 	}
 
 	return nil
 }
 
+// ParseName parses an already-romanized name into its structure without transliterating it
+//
+//encore:api public method=POST path=/api/parse-name
+func ParseName(ctx context.Context, req *ParseNameRequest) (*ParseNameResponse, error) {
+	if err := validateParseNameRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Fill in cultural context from the text itself when not provided
+	scriptInfo := detection.DetectScript(req.Text)
+	language := req.Language
+	if language == "" {
+		language = detection.DetectLanguage(req.Text, scriptInfo).Language
+	}
+	culture := req.Culture
+	if culture == "" {
+		culture = determineCulture(scriptInfo.Script, language)
+	}
+
+	// Fold any remaining diacritics so the structure matches the transliteration output;
+	// native honorifics are recognised from the original text instead
+	nameText, _ := nameparser.ExtractNativeHonorific(req.Text)
+	romanized, err := textnorm.ToASCII(nameText)
+	if err != nil {
+		return nil, invalidArgument(ReasonInvalidUTF8, "text contains invalid UTF-8 sequences")
+	}
+
+	name, genderInference := analyzeName(req.Text, romanized, culture, language, infersGender(req.InferGender))
+	if req.RespectInputCase {
+		nameparser.RespectInputCase(name, romanized)
+	}
+	applyNameFormat(name, req.NameFormat)
+	if req.GenderDistribution && genderInference != nil {
+		genderInference.Distribution = gender.Distribution(genderInference)
+	}
+	return &ParseNameResponse{Name: name, Gender: genderInference}, nil
+}
+
+// analyzeName parses the name structure and, when inferGender is set, infers gender for a
+// romanized name; otherwise the gender is nil and the inference never runs
+func analyzeName(originalText, romanizedText, culture, language string, inferGender bool) (*NameStructure, *GenderInference) {
+	nameParser := nameparser.NewParser(true, true) // preserveOriginal, strictCultural
+	name := nameParser.ParseName(originalText, romanizedText, culture, language)
+	if !inferGender {
+		return name, nil
+	}
+
+	genderEngine := gender.NewEngine(true, false) // useStatistical, culturalOnly
+	inferred := genderEngine.InferGender(originalText, romanizedText, culture, language)
+
+	// Gendered honorifics (女士, 先生, -kun) outweigh weaker name-based inference
+	honorific := nameparser.FindHonorific(originalText)
+	if honorific != nil && honorific.Gender != "" && honorific.Confidence > inferred.Confidence {
+		inferred = &GenderInference{
+			Value:      honorific.Gender,
+			Confidence: honorific.Confidence,
+			Source:     "cultural_marker",
+			Reason:     fmt.Sprintf("Honorific '%s' indicates gender", honorific.Native),
+		}
+	}
+
+	// A gendered title (Mr, Mrs, Mx) is the strongest signal and overrides the name itself;
+	// titles translated from a native honorific keep the honorific's own confidence
+	for _, title := range name.Titles {
+		if honorific != nil && title == honorific.Title {
+			continue
+		}
+		titled := gender.GetGenderFromTitle(title)
+		if titled.Value == gender.Unknown {
+			continue
+		}
+		reason := fmt.Sprintf("Title '%s' indicates gender", title)
+		if inferred.Value != gender.Unknown && inferred.Value != titled.Value {
+			reason += fmt.Sprintf("; conflicts with name-based inference %s (%s)", inferred.Value, inferred.Reason)
+		}
+		titled.Reason = reason
+		inferred = titled
+		break
+	}
+
+	return name, inferred
+}
+
+// applyNameFormat re-renders FullASCII in the requested order; Order keeps the culture's own
+func applyNameFormat(name *NameStructure, format string) {
+	if name != nil && format != "" {
+		name.FullASCII = nameparser.FormatName(name, format)
+	}
+}
+
+// buildSearchTokens assembles lowercased, diacritic-free index tokens from the given,
+// middle and family names, falling back to the output text when no name was parsed, followed
+// by the lowercased Metaphone keys so the index also matches names spelled differently
+func buildSearchTokens(name *NameStructure, outputText string, keys *PhoneticKeys) []string {
+	var parts []string
+	if name != nil && (name.First != "" || name.Family != "") {
+		parts = append(parts, name.First)
+		parts = append(parts, name.Middle...)
+		parts = append(parts, name.Patronymic)
+		parts = append(parts, name.Family)
+	} else {
+		parts = append(parts, outputText)
+	}
+
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		folded, err := textnorm.ToASCII(part)
+		if err != nil {
+			continue
+		}
+		// Split compound names (García-López, O'Brien) into their components
+		words := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if !seen[word] {
+				seen[word] = true
+				tokens = append(tokens, word)
+			}
+		}
+	}
+
+	if keys != nil {
+		for _, key := range strings.Fields(strings.ToLower(keys.First + " " + keys.Family)) {
+			if !seen[key] {
+				seen[key] = true
+				tokens = append(tokens, key)
+			}
+		}
+	}
+
+	return tokens
+}
+
+// buildPhoneticKeys computes Metaphone keys for the given and family names of a Latin or
+// ASCII output, returning nil for other outputs or when no name was parsed
+func buildPhoneticKeys(name *NameStructure, outputScript string) *PhoneticKeys {
+	if name == nil || (outputScript != "latin" && outputScript != "ascii") {
+		return nil
+	}
+	keys := &PhoneticKeys{First: phoneticKey(name.First), Family: phoneticKey(name.Family)}
+	if keys.First == "" && keys.Family == "" {
+		return nil
+	}
+	return keys
+}
+
+// phoneticKey returns the Metaphone keys of the words of a name, separated by spaces
+func phoneticKey(name string) string {
+	folded, err := textnorm.ToASCII(name)
+	if err != nil {
+		return ""
+	}
+	var keys []string
+	for _, word := range strings.FieldsFunc(folded, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if key := phonetic.Metaphone(word); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
 // determineCulture maps script and language to cultural context
 func determineCulture(script, language string) string {
 	switch {
@@ -248,7 +806,9 @@ func determineCulture(script, language string) string {
 		return "arabic"
 	case language == "th" || script == "thai":
 		return "thai"
-	case strings.Contains(language, "id") || strings.Contains(language, "ms"):
+	case language == "ru" || script == "cyrillic":
+		return "russian"
+	case strings.Contains(language, "id") || strings.Contains(language, "ms") || script == "indonesian":
 		return "indonesian"
 	case language == "hi" || language == "ta" || language == "te":
 		return "indian"
@@ -259,50 +819,117 @@ func determineCulture(script, language string) string {
 
 // Helper functions
 
-func getCachedTransliteration(ctx context.Context, inputText, inputScript, outputScript string, inputLocale *string) (*TransliterationResponse, error) {
+// execer runs a statement; *sqldb.Database is one
+type execer interface {
+	Exec(ctx context.Context, query string, args ...any) (sqldb.ExecResult, error)
+}
+
+// countCacheHit adds a use to a cached transliteration. The cached result is served whether
+// or not the count is updated, so a failure is only logged.
+func countCacheHit(ctx context.Context, database execer, id string) {
+	_, err := database.Exec(ctx, `
+		UPDATE transliterations
+		SET usage_count = usage_count + 1, updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		logError("failed to update usage count", "transliteration_id", id, "err", err)
+	}
+}
+
+// getCachedTransliteration returns the stored transliteration for the input and options, and
+// whether it is stale: computed before the latest change to a mapping for its scripts, or
+// longer ago than cacheTTL. A preferred output is served instead of the computed one and never
+// goes stale, since it does not depend on the rules.
+func getCachedTransliteration(ctx context.Context, inputText, inputScript, outputScript string, inputLocale *string, optionsHash string) (*TransliterationResponse, bool, error) {
 	var result TransliterationResponse
 	var cachedInputLocale *string
+	var computedAt time.Time
+	var mappingsUpdatedAt *time.Time
+	var characterConfidence float64
 
 	err := db.QueryRow(ctx, `
-		SELECT id, input_text, output_text, input_script, output_script, input_locale, confidence_score
+		SELECT id, input_text, COALESCE(preferred_output, output_text), input_script, output_script, input_locale,
+			COALESCE(character_confidence, confidence_score, 0), preferred_output IS NOT NULL, computed_at, (
+				SELECT MAX(updated_at) FROM character_mappings
+				WHERE source_script = $2 AND target_script = $3
+			)
 		FROM transliterations
-		WHERE input_text = $1 AND input_script = $2 AND output_script = $3
-		AND ($4::text IS NULL OR input_locale = $4)
+		WHERE md5(input_text) = md5($1) AND input_text = $1 AND input_script = $2 AND output_script = $3
+		AND input_locale IS NOT DISTINCT FROM $4
+		AND options_hash = $5
 		ORDER BY usage_count DESC, updated_at DESC
 		LIMIT 1
-	`, inputText, inputScript, outputScript, inputLocale).Scan(
+	`, inputText, inputScript, outputScript, inputLocale, optionsHash).Scan(
 		&result.ID, &result.InputText, &result.OutputText,
-		&result.InputScript, &result.OutputScript, &cachedInputLocale, &result.ConfidenceScore)
+		&result.InputScript, &result.OutputScript, &cachedInputLocale, &characterConfidence,
+		&result.Preferred, &computedAt, &mappingsUpdatedAt)
 
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	result.InputLocale = cachedInputLocale
-	return &result, nil
+	applyConfidence(&result, characterConfidence)
+	return &result, !result.Preferred && isStaleTransliteration(computedAt, mappingsUpdatedAt, cacheTTL, time.Now()), nil
 }
 
-func storeTransliteration(ctx context.Context, inputText, outputText, inputScript, outputScript string, inputLocale *string, confidenceScore float64) (*TransliterationResponse, error) {
-	var id string
+// isStaleTransliteration reports whether a result computed at computedAt should be recomputed
+// rather than served, given when the relevant mappings last changed (nil if never)
+func isStaleTransliteration(computedAt time.Time, mappingsUpdatedAt *time.Time, ttl time.Duration, now time.Time) bool {
+	if mappingsUpdatedAt != nil && computedAt.Before(*mappingsUpdatedAt) {
+		return true
+	}
+	return ttl > 0 && now.Sub(computedAt) > ttl
+}
+
+// storeTransliteration stores a fresh transliteration, scored from the conversion's
+// per-character confidence
+func storeTransliteration(ctx context.Context, inputText, outputText, inputScript, outputScript string, inputLocale *string, optionsHash string, characterConfidence float64) (*TransliterationResponse, error) {
+	result := newTransliterationResponse("", inputText, outputText, inputScript, outputScript, inputLocale)
+	applyConfidence(result, characterConfidence)
+
 	err := db.QueryRow(ctx, `
-		INSERT INTO transliterations (input_text, output_text, input_script, output_script, input_locale, confidence_score)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO transliterations (input_text, output_text, input_script, output_script, input_locale, options_hash, confidence_score, character_confidence)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
-	`, inputText, outputText, inputScript, outputScript, inputLocale, confidenceScore).Scan(&id)
+	`, inputText, outputText, inputScript, outputScript, inputLocale, optionsHash, *result.ConfidenceScore, characterConfidence).Scan(&result.ID)
 
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
+
+// refreshTransliteration replaces the output of a stale cached transliteration with a freshly
+// computed one, keeping its ID and counting the use
+func refreshTransliteration(ctx context.Context, cached *TransliterationResponse, outputText string, characterConfidence float64) (*TransliterationResponse, error) {
+	result := newTransliterationResponse(cached.ID, cached.InputText, outputText, cached.InputScript, cached.OutputScript, cached.InputLocale)
+	applyConfidence(result, characterConfidence)
+
+	_, err := db.Exec(ctx, `
+		UPDATE transliterations
+		SET output_text = $2, confidence_score = $3, character_confidence = $4, computed_at = NOW(),
+			usage_count = usage_count + 1, updated_at = NOW()
+		WHERE id = $1
+	`, cached.ID, outputText, *result.ConfidenceScore, characterConfidence)
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
 
+// newTransliterationResponse builds the response for a stored or previewed transliteration
+func newTransliterationResponse(id, inputText, outputText, inputScript, outputScript string, inputLocale *string) *TransliterationResponse {
 	return &TransliterationResponse{
-		ID:              id,
-		InputText:       inputText,
-		OutputText:      outputText,
-		InputScript:     inputScript,
-		OutputScript:    outputScript,
-		InputLocale:     inputLocale,
-		ConfidenceScore: &confidenceScore,
-	}, nil
+		ID:           id,
+		InputText:    inputText,
+		OutputText:   outputText,
+		InputScript:  inputScript,
+		OutputScript: outputScript,
+		InputLocale:  inputLocale,
+	}
 }
 
 
@@ -333,141 +960,145 @@ func applyBuiltinRules(r rune, inputScript, outputScript string) string {
 	return ""
 }
 
+// cyrillicLatinMap is the standard Cyrillic transliteration
+var cyrillicLatinMap = map[rune]string{
+	// Uppercase
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo",
+	'Ж': "Zh", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
+	'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
+	'Ф': "F", 'Х': "Kh", 'Ц': "Ts", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch",
+	'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu", 'Я': "Ya",
+	// Lowercase
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
 // transliterateCyrillicToLatin provides standard Cyrillic transliteration
 func transliterateCyrillicToLatin(r rune) string {
-	cyrillicMap := map[rune]string{
-		// Uppercase
-		'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo",
-		'Ж': "Zh", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
-		'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
-		'Ф': "F", 'Х': "Kh", 'Ц': "Ts", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch",
-		'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu", 'Я': "Ya",
-		// Lowercase
-		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
-		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
-		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
-		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
-		'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
-	}
-
-	if mapped, exists := cyrillicMap[r]; exists {
+	if mapped, exists := cyrillicLatinMap[r]; exists {
 		return mapped
 	}
 	return ""
 }
 
+// chineseLatinMap holds pinyin for common name and everyday characters
+var chineseLatinMap = map[rune]string{
+	// Common characters
+	'你': "ni", '好': "hao", '是': "shi", '的': "de", '我': "wo",
+	'他': "ta", '她': "ta", '们': "men", '有': "you", '在': "zai",
+	'了': "le", '不': "bu", '就': "jiu", '人': "ren", '都': "dou",
+	'一': "yi", '二': "er", '三': "san", '四': "si", '五': "wu",
+	'六': "liu", '七': "qi", '八': "ba", '九': "jiu", '十': "shi",
+	
+	// Common surname characters
+	'李': "Li", '王': "Wang", '张': "Zhang", '刘': "Liu", '陈': "Chen",
+	'杨': "Yang", '赵': "Zhao", '黄': "Huang", '周': "Zhou", '吴': "Wu",
+	'徐': "Xu", '孙': "Sun", '胡': "Hu", '朱': "Zhu", '高': "Gao",
+	'林': "Lin", '何': "He", '郭': "Guo", '马': "Ma", '罗': "Luo",
+	'梁': "Liang", '宋': "Song", '郑': "Zheng", '谢': "Xie", '韩': "Han",
+	'唐': "Tang", '冯': "Feng", '于': "Yu", '董': "Dong", '萧': "Xiao",
+	'程': "Cheng", '曹': "Cao", '袁': "Yuan", '邓': "Deng", '许': "Xu",
+	'傅': "Fu", '沈': "Shen", '曾': "Zeng", '彭': "Peng", '吕': "Lu",
+	
+	// Common given name characters 
+	'小': "Xiao", '大': "Da", '中': "Zhong", '文': "Wen", '明': "Ming",
+	'华': "Hua", '建': "Jian", '国': "Guo", '民': "Min", '伟': "Wei",
+	'龍': "Long", '龙': "Long", '凤': "Feng", '鳳': "Feng", '玉': "Yu",
+	'金': "Jin", '春': "Chun", '红': "Hong", '军': "Jun", '强': "Qiang",
+	'云': "Yun", '平': "Ping", '志': "Zhi", '刚': "Gang", '勇': "Yong",
+	'磊': "Lei", '娜': "Na", '静': "Jing", '丽': "Li", '敏': "Min",
+	'秀': "Xiu", '英': "Ying", '芳': "Fang", '燕': "Yan", '雪': "Xue",
+	'琴': "Qin", '梅': "Mei", '莉': "Li", '兰': "Lan", '翠': "Cui",
+	
+	// Additional useful characters
+	'东': "Dong", '南': "Nan", '西': "Xi", '北': "Bei", '上': "Shang",
+	'下': "Xia", '左': "Zuo", '右': "You", '前': "Qian", '后': "Hou",
+	'新': "Xin", '老': "Lao", '长': "Chang", '短': "Duan",
+	'低': "Di", '快': "Kuai", '慢': "Man", '早': "Zao", '晚': "Wan",
+}
+
 // transliterateChineseToLatin provides basic Chinese character mappings
 func transliterateChineseToLatin(r rune) string {
-	// Comprehensive Chinese character mappings including names and common characters
-	chineseMap := map[rune]string{
-		// Common characters
-		'你': "ni", '好': "hao", '是': "shi", '的': "de", '我': "wo",
-		'他': "ta", '她': "ta", '们': "men", '有': "you", '在': "zai",
-		'了': "le", '不': "bu", '就': "jiu", '人': "ren", '都': "dou",
-		'一': "yi", '二': "er", '三': "san", '四': "si", '五': "wu",
-		'六': "liu", '七': "qi", '八': "ba", '九': "jiu", '十': "shi",
-		
-		// Common surname characters
-		'李': "Li", '王': "Wang", '张': "Zhang", '刘': "Liu", '陈': "Chen",
-		'杨': "Yang", '赵': "Zhao", '黄': "Huang", '周': "Zhou", '吴': "Wu",
-		'徐': "Xu", '孙': "Sun", '胡': "Hu", '朱': "Zhu", '高': "Gao",
-		'林': "Lin", '何': "He", '郭': "Guo", '马': "Ma", '罗': "Luo",
-		'梁': "Liang", '宋': "Song", '郑': "Zheng", '谢': "Xie", '韩': "Han",
-		'唐': "Tang", '冯': "Feng", '于': "Yu", '董': "Dong", '萧': "Xiao",
-		'程': "Cheng", '曹': "Cao", '袁': "Yuan", '邓': "Deng", '许': "Xu",
-		'傅': "Fu", '沈': "Shen", '曾': "Zeng", '彭': "Peng", '吕': "Lu",
-		
-		// Common given name characters 
-		'小': "Xiao", '大': "Da", '中': "Zhong", '文': "Wen", '明': "Ming",
-		'华': "Hua", '建': "Jian", '国': "Guo", '民': "Min", '伟': "Wei",
-		'龍': "Long", '龙': "Long", '凤': "Feng", '鳳': "Feng", '玉': "Yu",
-		'金': "Jin", '春': "Chun", '红': "Hong", '军': "Jun", '强': "Qiang",
-		'云': "Yun", '平': "Ping", '志': "Zhi", '刚': "Gang", '勇': "Yong",
-		'磊': "Lei", '娜': "Na", '静': "Jing", '丽': "Li", '敏': "Min",
-		'秀': "Xiu", '英': "Ying", '芳': "Fang", '燕': "Yan", '雪': "Xue",
-		'琴': "Qin", '梅': "Mei", '莉': "Li", '兰': "Lan", '翠': "Cui",
-		
-		// Additional useful characters
-		'东': "Dong", '南': "Nan", '西': "Xi", '北': "Bei", '上': "Shang",
-		'下': "Xia", '左': "Zuo", '右': "You", '前': "Qian", '后': "Hou",
-		'新': "Xin", '老': "Lao", '长': "Chang", '短': "Duan",
-		'低': "Di", '快': "Kuai", '慢': "Man", '早': "Zao", '晚': "Wan",
-	}
-
-	if mapped, exists := chineseMap[r]; exists {
+	if mapped, exists := chineseLatinMap[r]; exists {
 		return mapped
 	}
 	return ""
 }
 
+// arabicLatinMap is the Arabic transliteration
+var arabicLatinMap = map[rune]string{
+	'ا': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h",
+	'خ': "kh", 'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s",
+	'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z", 'ع': "'",
+	'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m",
+	'ن': "n", 'ه': "h", 'و': "w", 'ي': "y",
+}
+
 // transliterateArabicToLatin provides Arabic transliteration
 func transliterateArabicToLatin(r rune) string {
-	arabicMap := map[rune]string{
-		'ا': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h",
-		'خ': "kh", 'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s",
-		'ش': "sh", 'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z", 'ع': "'",
-		'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m",
-		'ن': "n", 'ه': "h", 'و': "w", 'ي': "y",
-	}
-
-	if mapped, exists := arabicMap[r]; exists {
+	if mapped, exists := arabicLatinMap[r]; exists {
 		return mapped
 	}
 	return ""
 }
 
+// greekLatinMap is the Greek transliteration
+var greekLatinMap = map[rune]string{
+	'Α': "A", 'Β': "B", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z",
+	'Η': "H", 'Θ': "Th", 'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M",
+	'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P", 'Ρ': "R", 'Σ': "S",
+	'Τ': "T", 'Υ': "Y", 'Φ': "Ph", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
+	'α': "a", 'β': "b", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z",
+	'η': "h", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m",
+	'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s",
+	'τ': "t", 'υ': "y", 'φ': "ph", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
 // transliterateGreekToLatin provides Greek transliteration
 func transliterateGreekToLatin(r rune) string {
-	greekMap := map[rune]string{
-		'Α': "A", 'Β': "B", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z",
-		'Η': "H", 'Θ': "Th", 'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M",
-		'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P", 'Ρ': "R", 'Σ': "S",
-		'Τ': "T", 'Υ': "Y", 'Φ': "Ph", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
-		'α': "a", 'β': "b", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z",
-		'η': "h", 'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m",
-		'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s",
-		'τ': "t", 'υ': "y", 'φ': "ph", 'χ': "ch", 'ψ': "ps", 'ω': "o",
-	}
-
-	if mapped, exists := greekMap[r]; exists {
+	if mapped, exists := greekLatinMap[r]; exists {
 		return mapped
 	}
 	return ""
 }
 
+// asciiApproximationMap maps accented and other common characters to ASCII
+var asciiApproximationMap = map[rune]string{
+	// Basic accented vowels
+	'á': "a", 'à': "a", 'â': "a", 'ã': "a", 'ā': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e", 'ē': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ī': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ō': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ū': "u",
+	// Uppercase versions
+	'Á': "A", 'À': "A", 'Â': "A", 'Ã': "A", 'Ā': "A",
+	'É': "E", 'È': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E",
+	'Í': "I", 'Ì': "I", 'Î': "I", 'Ï': "I", 'Ī': "I",
+	'Ó': "O", 'Ò': "O", 'Ô': "O", 'Õ': "O", 'Ō': "O",
+	'Ú': "U", 'Ù': "U", 'Û': "U", 'Ū': "U",
+	
+	// Vietnamese diacritics (key ones)
+	'ă': "a", 'Ă': "A", 'đ': "d", 'Đ': "D",
+	'ư': "u", 'Ư': "U", 'ơ': "o", 'Ơ': "O",
+	
+	// Other common characters
+	'ç': "c", 'Ç': "C", 'ñ': "n", 'Ñ': "N",
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	
+	// Scandinavian
+	'å': "aa", 'Å': "AA", 'ø': "oe", 'Ø': "OE",
+}
+
 // approximateToASCII converts Unicode characters to closest ASCII equivalents
 func approximateToASCII(r rune) string {
-	// Handle accented characters and diacritics
-	asciiMap := map[rune]string{
-		// Basic accented vowels
-		'á': "a", 'à': "a", 'â': "a", 'ã': "a", 'ā': "a",
-		'é': "e", 'è': "e", 'ê': "e", 'ë': "e", 'ē': "e",
-		'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ī': "i",
-		'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ō': "o",
-		'ú': "u", 'ù': "u", 'û': "u", 'ū': "u",
-		// Uppercase versions
-		'Á': "A", 'À': "A", 'Â': "A", 'Ã': "A", 'Ā': "A",
-		'É': "E", 'È': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E",
-		'Í': "I", 'Ì': "I", 'Î': "I", 'Ï': "I", 'Ī': "I",
-		'Ó': "O", 'Ò': "O", 'Ô': "O", 'Õ': "O", 'Ō': "O",
-		'Ú': "U", 'Ù': "U", 'Û': "U", 'Ū': "U",
-		
-		// Vietnamese diacritics (key ones)
-		'ă': "a", 'Ă': "A", 'đ': "d", 'Đ': "D",
-		'ư': "u", 'Ư': "U", 'ơ': "o", 'Ơ': "O",
-		
-		// Other common characters
-		'ç': "c", 'Ç': "C", 'ñ': "n", 'Ñ': "N",
-		'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
-		
-		// German umlauts 
-		'ä': "ae", 'Ä': "AE", 'ö': "oe", 'Ö': "OE", 'ü': "ue", 'Ü': "UE",
-		
-		// Scandinavian
-		'å': "aa", 'Å': "AA", 'ø': "oe", 'Ø': "OE",
-	}
-
-	if mapped, exists := asciiMap[r]; exists {
+	if umlaut, ok := textnorm.UmlautToASCII(r, true); ok {
+		return umlaut
+	}
+
+	if mapped, exists := asciiApproximationMap[r]; exists {
 		return mapped
 	}
 
@@ -493,6 +1124,72 @@ func approximateToASCII(r rune) string {
 	return "" // Skip other characters
 }
 
+// ConfidenceFactors breaks a confidence score into its contributions
+type ConfidenceFactors struct {
+	Base                float64 `json:"base"`                 // Starting confidence for any conversion
+	ScriptCompatibility float64 `json:"script_compatibility"` // Bonus for easier script pairs
+	Coverage            float64 `json:"coverage"`             // Bonus or penalty for how much of the input survived
+	Length              float64 `json:"length"`               // Bonus when the output length is plausible for the input
+	Unmapped            float64 `json:"unmapped"`             // Penalty for "?" placeholders, in proportion to the input
+	Characters          float64 `json:"characters"`           // Penalty for characters the rules read uncertainly (approximated, guessed or left as written)
+	Score               float64 `json:"score"`                // Sum of the factors, clamped to the script pair's confidence bounds; the confidence_score
+}
+
+// calculateConfidence scores a conversion from the script pair, the shape of the output and
+// the conversion's per-character confidence (1.0 when every character had a certain reading)
+func calculateConfidence(inputText, outputText, inputScript, outputScript string, characterConfidence float64) ConfidenceFactors {
+	factors := ConfidenceFactors{
+		Base:                0.5,
+		ScriptCompatibility: calculateScriptCompatibility(inputScript, outputScript),
+		Coverage:            calculateCharacterCoverage(inputText, outputText),
+		Length:              calculateLengthPlausibility(inputText, outputText),
+		Unmapped:            calculateUnmappedPenalty(inputText, outputText),
+		Characters:          math.Min(0, characterConfidence-1.0),
+	}
+
+	score := factors.Base + factors.ScriptCompatibility + factors.Coverage + factors.Length + factors.Unmapped + factors.Characters
+	factors.Score = boundConfidence(inputScript, outputScript, score)
+	return factors
+}
+
+// applyConfidence sets a result's confidence_score and the factors that explain it
+func applyConfidence(resp *TransliterationResponse, characterConfidence float64) {
+	factors := calculateConfidence(resp.InputText, resp.OutputText, resp.InputScript, resp.OutputScript, characterConfidence)
+	resp.ConfidenceScore = &factors.Score
+	resp.ConfidenceFactors = &factors
+}
+
+// unmappedPenalty is the penalty for output made entirely of "?" placeholders
+const unmappedPenalty = 0.5
+
+// countUnmapped counts the "?" placeholders in the output beyond any question marks in the input
+func countUnmapped(inputText, outputText string) int {
+	return max(0, strings.Count(outputText, "?")-strings.Count(inputText, "?"))
+}
+
+// calculateUnmappedPenalty penalizes "?" placeholders in proportion to the input's characters,
+// so output riddled with them cannot score well on shape alone
+func calculateUnmappedPenalty(inputText, outputText string) float64 {
+	unmapped := countUnmapped(inputText, outputText)
+	inputChars := countNonWhitespaceChars(inputText)
+	if unmapped == 0 || inputChars == 0 {
+		return 0.0
+	}
+	return -unmappedPenalty * math.Min(1.0, float64(unmapped)/float64(inputChars))
+}
+
+// calculateLengthPlausibility rewards outputs whose length is plausible for the input;
+// romanization rarely needs more than four characters per source character
+func calculateLengthPlausibility(inputText, outputText string) float64 {
+	inputLength := utf8.RuneCountInString(inputText)
+	outputLength := utf8.RuneCountInString(outputText)
+
+	if inputLength == 0 || outputLength == 0 || outputLength > 4*inputLength {
+		return 0.0
+	}
+	return 0.1
+}
+
 // calculateScriptCompatibility returns a bonus based on script pairing difficulty
 func calculateScriptCompatibility(inputScript, outputScript string) float64 {
 	// High compatibility pairs
@@ -563,99 +1260,443 @@ func countNonWhitespaceChars(text string) int {
 
 // Validation functions
 
-// validateTransliterationRequest validates the input request
+// applyInlineOriginal replaces the output with the inline template when requested; the
+// stored record keeps the plain output
+func applyInlineOriginal(resp *TransliterationResponse, req *TransliterationRequest) {
+	if !req.InlineOriginal {
+		return
+	}
+
+	template := req.InlineTemplate
+	if template == "" {
+		template = defaultInlineTemplate
+	}
+	resp.OutputText = formatInline(template, resp.InputText, resp.OutputText)
+}
+
+// formatInline fills a template's {input} and {output} placeholders, trimming surrounding
+// whitespace from both so spacing comes only from the template
+func formatInline(template, input, output string) string {
+	replacer := strings.NewReplacer("{input}", strings.TrimSpace(input), "{output}", strings.TrimSpace(output))
+	return replacer.Replace(template)
+}
+
+// responseLanguageHint returns the language hint for the response, or nil when the language is unknown
+func responseLanguageHint(hint detection.LanguageHint) *LanguageHint {
+	if hint.Language == "unknown" {
+		return nil
+	}
+	return &hint
+}
+
+// applyCodePointPolicy strips private-use and unassigned code points under the strip policy,
+// returning the text to transliterate and any warnings for the response notes
+func applyCodePointPolicy(text, policy string) (string, []string, error) {
+	if policy != codePointsStrip {
+		return text, nil, nil
+	}
+
+	stripped, removed := textnorm.StripPrivateOrUnassigned(text)
+	if removed == 0 {
+		return text, nil, nil
+	}
+	if strings.TrimSpace(stripped) == "" {
+		return "", nil, invalidArgument(ReasonTextEmpty, "text contains only private-use or unassigned code points")
+	}
+
+	return stripped, []string{fmt.Sprintf("Removed %d private-use or unassigned code points", removed)}, nil
+}
+
+// stripBidiControls removes bidirectional control characters (LRM, RLM, embeddings,
+// overrides and isolates) that Arabic and Hebrew input often carries
+func stripBidiControls(text string) (string, error) {
+	stripped, removed := textnorm.StripBidiControls(text)
+	if removed > 0 && strings.TrimSpace(stripped) == "" {
+		return "", invalidArgument(ReasonTextEmpty, "text contains only bidirectional control characters")
+	}
+	return stripped, nil
+}
+
+// detectInputScript picks the detected script for a request without input_script, rejecting
+// detection that is inconclusive or less confident than the client requires
+func detectInputScript(detected detection.ScriptInfo, minConfidence float64) (string, error) {
+	if detected.Script == "unknown" {
+		return "", invalidArgument(ReasonScriptUndetectable, "unable to detect input script")
+	}
+	if detected.Confidence < minConfidence {
+		return "", invalidArgument(ReasonDetectionConfidenceLow,
+			"detected %s with confidence %.2f, below min_detection_confidence %.2f; supply input_script explicitly",
+			detected.Script, detected.Confidence, minConfidence)
+	}
+	return detected.Script, nil
+}
+
+// applyScriptMismatchPolicy resolves a provided input_script that disagrees with high-confidence
+// detection, returning the script to use and any warning for the response notes
+func applyScriptMismatchPolicy(provided, policy string, detected detection.ScriptInfo) (string, string, error) {
+	if detected.Confidence < scriptMismatchConfidence || scriptsAgree(provided, detected.Script) {
+		return provided, "", nil
+	}
+
+	switch policy {
+	case scriptMismatchTrustDetection:
+		return detected.Script, fmt.Sprintf("input_script %s replaced by detected script %s (%.2f confidence)", provided, detected.Script, detected.Confidence), nil
+	case scriptMismatchWarn:
+		return provided, fmt.Sprintf("input_script %s disagrees with detected script %s (%.2f confidence)", provided, detected.Script, detected.Confidence), nil
+	case scriptMismatchError:
+		return "", "", invalidArgument(ReasonScriptMismatch, "input_script %s disagrees with detected script %s (%.2f confidence)", provided, detected.Script, detected.Confidence)
+	default:
+		return provided, "", nil
+	}
+}
+
+// latinFamilyScripts lists scripts written in Latin letters, which never disagree with each other
+var latinFamilyScripts = map[string]bool{
+	"latin": true, "ascii": true, "german": true, "vietnamese": true, "indonesian": true,
+}
+
+// scriptsAgree reports whether a provided script is consistent with the detected one
+func scriptsAgree(provided, detected string) bool {
+	switch {
+	case provided == detected || detected == "unknown":
+		return true
+	case latinFamilyScripts[provided] && latinFamilyScripts[detected]:
+		return true
+	case provided == "japanese" && detected == "chinese":
+		// Names written only in kanji are detected as Chinese
+		return true
+	default:
+		return false
+	}
+}
+
+// validScripts lists the script names accepted for input_script and output_script
+var validScripts = map[string]bool{
+	"latin": true, "ascii": true, "cyrillic": true,
+	"chinese": true, "japanese": true, "arabic": true, "greek": true,
+	"vietnamese": true, "indonesian": true, "malayalam": true, "mongolian": true,
+	"hebrew": true, "armenian": true, "ethiopic": true,
+}
+
+// validFeedbackTypes lists the accepted feedback_type values
+var validFeedbackTypes = map[string]bool{
+	"correction": true, "alternative": true, "preferred": true,
+}
+
+// validBoundarySpacings lists the accepted boundary_spacing values
+var validBoundarySpacings = map[string]bool{
+	transliteration.BoundarySpacingAlways: true, transliteration.BoundarySpacingNever: true, transliteration.BoundarySpacingSmart: true,
+}
+
+// validLongVowels lists the accepted long_vowels values
+var validLongVowels = map[string]bool{
+	transliteration.LongVowelsDoubled: true, transliteration.LongVowelsMacron: true,
+}
+
+// validUnmappedPolicies lists the accepted unmapped_policy values
+var validUnmappedPolicies = map[string]bool{
+	transliteration.UnmappedQuestion: true, transliteration.UnmappedDrop: true,
+	transliteration.UnmappedKeep: true, transliteration.UnmappedUnicodeName: true,
+}
+
+// validCodePointPolicies lists the accepted invalid_code_points values
+var validCodePointPolicies = map[string]bool{
+	codePointsAllow: true, codePointsReject: true, codePointsStrip: true,
+}
+
+// validScriptMismatchPolicies lists the accepted script_mismatch values
+var validScriptMismatchPolicies = map[string]bool{
+	scriptMismatchTrustClient: true, scriptMismatchTrustDetection: true, scriptMismatchWarn: true, scriptMismatchError: true,
+}
+
+// validateTransliterationRequest validates the input request, reporting every invalid field at once
 func validateTransliterationRequest(req *TransliterationRequest) error {
 	if req == nil {
-		return errors.New("request cannot be nil")
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
 	}
 
-	if strings.TrimSpace(req.Text) == "" {
-		return errors.New("text cannot be empty")
+	var problems validationErrors
+
+	maxLength := defaultMaxTextLength
+	if req.MaxLength < 0 || req.MaxLength > maxTextLengthCap {
+		problems.add("max_length", ReasonInvalidMaxLength, "max_length must be between 1 and %d", maxTextLengthCap)
+	} else if req.MaxLength > 0 {
+		maxLength = req.MaxLength
 	}
 
-	if len(req.Text) > 10000 { // Reasonable limit
-		return errors.New("text too long (maximum 10,000 characters)")
+	if strings.TrimSpace(req.Text) == "" {
+		problems.add("text", ReasonTextEmpty, "text cannot be empty")
+	} else if utf8.RuneCountInString(req.Text) > maxLength {
+		problems.add("text", ReasonTextTooLong, "text too long (maximum %d characters)", maxLength)
 	}
 
 	if !utf8.ValidString(req.Text) {
-		return errors.New("text contains invalid UTF-8 sequences")
+		problems.add("text", ReasonInvalidUTF8, "text contains invalid UTF-8 sequences")
 	}
 
-	if req.OutputScript == "" {
-		return errors.New("output_script is required")
+	// Validate script names
+	if req.InputScript != "" && !validScripts[req.InputScript] {
+		problems.add("input_script", ReasonUnsupportedInputScript, "unsupported input script: %s", req.InputScript)
 	}
 
-	// Validate script names
-	validScripts := map[string]bool{
-		"latin": true, "ascii": true, "cyrillic": true,
-		"chinese": true, "japanese": true, "arabic": true, "greek": true,
-		"vietnamese": true, "indonesian": true, "malayalam": true,
+	if req.OutputScript == "" {
+		problems.add("output_script", ReasonOutputScriptRequired, "output_script is required")
+	} else if !validScripts[req.OutputScript] {
+		problems.add("output_script", ReasonUnsupportedOutputScript, "unsupported output script: %s", req.OutputScript)
+	}
+
+	if len(req.AdditionalOutputScripts) > maxAdditionalOutputScripts {
+		problems.add("additional_output_scripts", ReasonTooManyOutputScripts, "at most %d additional output scripts are allowed", maxAdditionalOutputScripts)
+	}
+	seenScripts := map[string]bool{req.OutputScript: true}
+	for i, script := range req.AdditionalOutputScripts {
+		field := fmt.Sprintf("additional_output_scripts[%d]", i)
+		switch {
+		case !validScripts[script]:
+			problems.add(field, ReasonUnsupportedOutputScript, "unsupported output script: %s", script)
+		case seenScripts[script]:
+			problems.add(field, ReasonDuplicateOutputScript, "output script %s is already requested", script)
+		}
+		seenScripts[script] = true
 	}
 
-	if req.InputScript != "" && !validScripts[req.InputScript] {
-		return fmt.Errorf("unsupported input script: %s", req.InputScript)
+	if req.MinDetectionConfidence < 0 || req.MinDetectionConfidence > 1 {
+		problems.add("min_detection_confidence", ReasonInvalidMinDetectionConfidence, "min_detection_confidence must be between 0 and 1")
 	}
 
-	if !validScripts[req.OutputScript] {
-		return fmt.Errorf("unsupported output script: %s", req.OutputScript)
+	if req.MaxAlternatives < 0 || req.MaxAlternatives > maxAlternativesCap {
+		problems.add("max_alternatives", ReasonInvalidMaxAlternatives, "max_alternatives must be between 1 and %d", maxAlternativesCap)
 	}
 
 	// Validate locale format if provided
 	if req.InputLocale != nil && !isValidLocale(*req.InputLocale) {
-		return fmt.Errorf("invalid locale format: %s", *req.InputLocale)
+		problems.add("input_locale", ReasonInvalidLocale, "invalid locale format: %s", *req.InputLocale)
 	}
 
-	return nil
+	if req.BoundarySpacing != "" && !validBoundarySpacings[req.BoundarySpacing] {
+		problems.add("boundary_spacing", ReasonInvalidBoundarySpacing, "invalid boundary_spacing: %s (expected always, never or smart)", req.BoundarySpacing)
+	}
+
+	if req.LongVowels != "" && !validLongVowels[req.LongVowels] {
+		problems.add("long_vowels", ReasonInvalidLongVowels, "invalid long_vowels: %s (expected doubled or macron)", req.LongVowels)
+	}
+
+	if req.UnmappedPolicy != "" && !validUnmappedPolicies[req.UnmappedPolicy] {
+		problems.add("unmapped_policy", ReasonInvalidUnmappedPolicy, "invalid unmapped_policy: %s (expected question, drop, keep or unicode-name)", req.UnmappedPolicy)
+	}
+
+	if req.NameFormat != "" && !nameparser.NameFormats[req.NameFormat] {
+		problems.add("name_format", ReasonInvalidNameFormat, "invalid name_format: %s (expected given-first, family-first or sortable)", req.NameFormat)
+	}
+
+	if req.InvalidCodePoints != "" && !validCodePointPolicies[req.InvalidCodePoints] {
+		problems.add("invalid_code_points", ReasonInvalidCodePointPolicy, "invalid invalid_code_points: %s (expected allow, reject or strip)", req.InvalidCodePoints)
+	} else if req.InvalidCodePoints == codePointsReject {
+		if found := textnorm.FindPrivateOrUnassigned(req.Text); len(found) > 0 {
+			codes := make([]string, len(found))
+			for i, r := range found {
+				codes[i] = fmt.Sprintf("U+%04X", r)
+			}
+			problems.add("text", ReasonInvalidCodePoints, "text contains private-use or unassigned code points: %s", strings.Join(codes, ", "))
+		}
+	}
+
+	if req.CacheOnly && req.Verbose {
+		problems.add("cache_only", ReasonInvalidCacheOnly, "cache_only cannot be combined with verbose, which bypasses the cache")
+	}
+
+	checkGenderOptions(&problems, req.InferGender, req.GenderDistribution)
+
+	if req.DomainSafe {
+		switch {
+		case req.OutputScript != "" && req.OutputScript != "ascii":
+			problems.add("domain_safe", ReasonInvalidDomainSafe, "domain_safe requires output_script ascii")
+		case req.InlineOriginal:
+			problems.add("domain_safe", ReasonInvalidDomainSafe, "domain_safe cannot be combined with inline_original")
+		}
+	}
+
+	if req.InlineTemplate != "" {
+		switch {
+		case !req.InlineOriginal:
+			problems.add("inline_template", ReasonInvalidInlineTemplate, "inline_template requires inline_original")
+		case !strings.Contains(req.InlineTemplate, "{output}"):
+			problems.add("inline_template", ReasonInvalidInlineTemplate, "inline_template must contain {output}")
+		case len(req.InlineTemplate) > 100:
+			problems.add("inline_template", ReasonInvalidInlineTemplate, "inline_template too long (maximum 100 characters)")
+		}
+	}
+
+	if req.ScriptMismatch != "" && !validScriptMismatchPolicies[req.ScriptMismatch] {
+		problems.add("script_mismatch", ReasonInvalidScriptMismatchPolicy, "invalid script_mismatch: %s (expected trust_client, trust_detection, warn or error)", req.ScriptMismatch)
+	}
+
+	if req.Standard != "" {
+		script, ok := transliteration.StandardScripts[req.Standard]
+		if !ok {
+			problems.add("standard", ReasonUnsupportedStandard, "unsupported standard: %s", req.Standard)
+		} else if req.InputScript != "" && req.InputScript != script {
+			problems.add("standard", ReasonStandardScriptMismatch, "standard %s applies to %s input, not %s", req.Standard, script, req.InputScript)
+		}
+	}
+
+	validateAPIVersion(&problems, req.APIVersion, req.APIVersionQuery)
+
+	return problems.err()
 }
 
-// validateFeedbackRequest validates feedback input
-func validateFeedbackRequest(req *FeedbackRequest) error {
+// expandsGermanUmlauts reports whether umlauts are written ae/oe/ue: only for German input,
+// by locale or detection, and unless the request turned expansion off
+func expandsGermanUmlauts(requested *bool, locale *string) bool {
+	if requested != nil && !*requested {
+		return false
+	}
+	return locale != nil && strings.HasPrefix(strings.ToLower(*locale), "de")
+}
+
+// cacheOptions is the canonical form of the request options that change the output. Each
+// field's zero value is the default, so default requests share the empty options hash.
+type cacheOptions struct {
+	NumberWords        bool   `json:"number_words,omitempty"`
+	BoundarySpacing    string `json:"boundary_spacing,omitempty"`
+	Standard           string `json:"standard,omitempty"`
+	LongVowels         string `json:"long_vowels,omitempty"`
+	PreserveDiacritics bool   `json:"preserve_diacritics,omitempty"`
+	NoUmlautExpansion  bool   `json:"no_umlaut_expansion,omitempty"`
+	UnmappedPolicy     string `json:"unmapped_policy,omitempty"`
+	PreserveEmoji      bool   `json:"preserve_emoji,omitempty"`
+	PreserveSymbols    bool   `json:"preserve_symbols,omitempty"`
+}
+
+// transliterationOptionsHash returns the cache key for the request's output-affecting options:
+// empty for the defaults, otherwise the SHA-256 of the canonical options as JSON
+func transliterationOptionsHash(req *TransliterationRequest) string {
+	options := cacheOptions{
+		NumberWords:        req.NumberWords,
+		BoundarySpacing:    req.BoundarySpacing,
+		Standard:           req.Standard,
+		LongVowels:         req.LongVowels,
+		PreserveDiacritics: req.PreserveDiacritics,
+		NoUmlautExpansion:  req.GermanUmlautExpansion != nil && !*req.GermanUmlautExpansion,
+		UnmappedPolicy:     req.UnmappedPolicy,
+		PreserveEmoji:      req.PreserveEmoji && !req.PreserveSymbols,
+		PreserveSymbols:    req.PreserveSymbols,
+	}
+	if options.BoundarySpacing == transliteration.BoundarySpacingSmart {
+		options.BoundarySpacing = ""
+	}
+	if options.LongVowels == transliteration.LongVowelsDoubled {
+		options.LongVowels = ""
+	}
+	if options.UnmappedPolicy == transliteration.DefaultUnmappedPolicy(req.OutputScript) {
+		options.UnmappedPolicy = ""
+	}
+	if options == (cacheOptions{}) {
+		return ""
+	}
+
+	canonical, _ := json.Marshal(options) // Cannot fail for a struct of strings and bools
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// validateParseNameRequest validates the name parsing request
+func validateParseNameRequest(req *ParseNameRequest) error {
 	if req == nil {
-		return errors.New("feedback request cannot be nil")
+		return invalidArgument(ReasonRequestMissing, "request cannot be nil")
 	}
 
-	if strings.TrimSpace(req.SuggestedOutput) == "" {
-		return errors.New("suggested_output cannot be empty")
+	if strings.TrimSpace(req.Text) == "" {
+		return invalidArgument(ReasonTextEmpty, "text cannot be empty")
+	}
+
+	if len(req.Text) > 1000 { // Names are short; reject documents
+		return invalidArgument(ReasonTextTooLong, "text too long (maximum 1,000 characters)")
 	}
 
-	if len(req.SuggestedOutput) > 10000 {
-		return errors.New("suggested_output too long")
+	if !utf8.ValidString(req.Text) {
+		return invalidArgument(ReasonInvalidUTF8, "text contains invalid UTF-8 sequences")
+	}
+
+	if req.NameFormat != "" && !nameparser.NameFormats[req.NameFormat] {
+		return invalidArgument(ReasonInvalidNameFormat, "invalid name_format: %s (expected given-first, family-first or sortable)", req.NameFormat)
 	}
 
-	validFeedbackTypes := map[string]bool{
-		"correction": true, "alternative": true, "preferred": true,
+	var problems validationErrors
+	checkGenderOptions(&problems, req.InferGender, req.GenderDistribution)
+	return problems.err()
+}
+
+// checkGenderOptions rejects asking for gender inference where the deployment has turned it
+// off, and a gender distribution without inference
+func checkGenderOptions(problems *validationErrors, inferGender *bool, distribution bool) {
+	if inferGender != nil && *inferGender && genderInferenceDisabled() {
+		problems.add("infer_gender", ReasonGenderInferenceDisabled, "gender inference is disabled for this deployment")
+	} else if distribution && !infersGender(inferGender) {
+		problems.add("gender_distribution", ReasonGenderInferenceDisabled, "gender_distribution requires gender inference")
+	}
+}
+
+// validateFeedbackRequest validates feedback input, reporting every invalid field at once
+func validateFeedbackRequest(req *FeedbackRequest) error {
+	if req == nil {
+		return invalidArgument(ReasonRequestMissing, "feedback request cannot be nil")
+	}
+
+	var problems validationErrors
+
+	if strings.TrimSpace(req.SuggestedOutput) == "" {
+		problems.add("suggested_output", ReasonSuggestedOutputEmpty, "suggested_output cannot be empty")
+	} else if len(req.SuggestedOutput) > 10000 {
+		problems.add("suggested_output", ReasonSuggestedOutputTooLong, "suggested_output too long")
 	}
 
 	if !validFeedbackTypes[req.FeedbackType] {
-		return fmt.Errorf("invalid feedback_type: %s (must be 'correction', 'alternative', or 'preferred')", req.FeedbackType)
+		problems.add("feedback_type", ReasonInvalidFeedbackType, "invalid feedback_type: %s (must be 'correction', 'alternative', or 'preferred')", req.FeedbackType)
 	}
 
-	return nil
+	return problems.err()
+}
+
+// supportedScriptPairs lists the output scripts each input script can be transliterated to
+var supportedScriptPairs = map[string]map[string]bool{
+	"latin":      {"ascii": true, "latin": true, "greek": true, "vietnamese": true},
+	"ascii":      {"latin": true, "ascii": true, "vietnamese": true},
+	"cyrillic":   {"latin": true, "ascii": true},
+	"chinese":    {"latin": true, "ascii": true},
+	"japanese":   {"latin": true, "ascii": true},
+	"arabic":     {"latin": true, "ascii": true},
+	"greek":      {"latin": true, "ascii": true},
+	"vietnamese": {"latin": true, "ascii": true},
+	"german":     {"latin": true, "ascii": true},
+	"indonesian": {"latin": true, "ascii": true},
+	"malayalam":  {"latin": true, "ascii": true},
+	"mongolian":  {"latin": true, "ascii": true},
+	"hebrew":     {"latin": true, "ascii": true},
+	"armenian":   {"latin": true, "ascii": true},
+	"ethiopic":   {"latin": true, "ascii": true},
 }
 
 // isSupportedScriptPair checks if the script conversion is supported
 func isSupportedScriptPair(inputScript, outputScript string) bool {
-	supportedPairs := map[string]map[string]bool{
-		"latin":      {"ascii": true, "latin": true},
-		"ascii":      {"latin": true, "ascii": true},
-		"cyrillic":   {"latin": true, "ascii": true},
-		"chinese":    {"latin": true, "ascii": true},
-		"japanese":   {"latin": true, "ascii": true},
-		"arabic":     {"latin": true, "ascii": true},
-		"greek":      {"latin": true, "ascii": true},
-		"vietnamese": {"latin": true, "ascii": true},
-		"german":     {"latin": true, "ascii": true},
-		"indonesian": {"latin": true, "ascii": true},
-		"malayalam":  {"latin": true, "ascii": true},
-	}
-
-	if targets, exists := supportedPairs[inputScript]; exists {
+	if targets, exists := supportedScriptPairs[inputScript]; exists {
 		return targets[outputScript]
 	}
 
 	return false
 }
 
+// supportedOutputScripts lists, sorted, the output scripts inputScript can be transliterated to
+func supportedOutputScripts(inputScript string) []string {
+	var targets []string
+	for _, target := range sortedKeys(supportedScriptPairs[inputScript]) {
+		if supportedScriptPairs[inputScript][target] {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
 // isValidUUID checks if a string is a valid UUID format
 func isValidUUID(uuid string) bool {
 	// Basic UUID format validation (36 characters with hyphens in right places)
@@ -723,6 +1764,8 @@ func isValidLocale(locale string) bool {
 
 // performTransliterationWithValidation wraps transliteration with error handling
 func performTransliterationWithValidation(text, inputScript, outputScript string, inputLocale *string) (string, error) {
+	text, _ = textnorm.StripBidiControls(text)
+	text = textnorm.ComposeNFC(text)
 	if text == "" {
 		return "", errors.New("empty input text")
 	}
@@ -741,8 +1784,9 @@ func performTransliterationWithValidation(text, inputScript, outputScript string
 		return "", err
 	}
 
-	// Validate output
-	if result.Output == "" {
+	// Validate output; input whose every character converts to nothing (ъь) legitimately
+	// gives empty output, which the engine reports with the "empty" method
+	if result.Output == "" && result.Method != "empty" {
 		return "", errors.New("transliteration produced empty result")
 	}
 
@@ -753,344 +1797,11 @@ func performTransliterationWithValidation(text, inputScript, outputScript string
 	return result.Output, nil
 }
 
-// parseName extracts structured name components from transliterated text
-func parseName(originalText, transliteratedText, inputScript string) *NameStructure {
-	if transliteratedText == "" {
-		return nil
-	}
-
-	// Extract titles first
-	titles := extractTitles(transliteratedText)
-	textWithoutTitles := removeTitles(transliteratedText, titles)
-
-	// Handle cultural naming conventions
-	switch inputScript {
-	case "chinese":
-		return parseChineseName(textWithoutTitles, titles)
-	case "vietnamese":
-		return parseVietnameseName(originalText, textWithoutTitles, titles)
-	case "arabic":
-		return parseArabicName(textWithoutTitles, titles)
-	case "indonesian", "malayalam":
-		return parseMononymOrPatronymic(textWithoutTitles, titles, inputScript)
-	default:
-		return parseWesternName(textWithoutTitles, titles)
-	}
-}
-
-// extractTitles identifies and extracts titles from text
-func extractTitles(text string) []string {
-	titlePatterns := []string{
-		"DR", "DOCTOR", "PROF", "PROFESSOR", "MR", "MRS", "MS", "MISS",
-		"SIR", "DAME", "LORD", "LADY", "HON", "HONOURABLE", "REV", "REVEREND",
-	}
-
-	var titles []string
-	words := strings.Fields(strings.ToUpper(text))
-
-	for _, word := range words {
-		cleanWord := strings.Trim(word, ".,")
-		for _, pattern := range titlePatterns {
-			if cleanWord == pattern {
-				titles = append(titles, formatTitle(cleanWord))
-				break
-			}
-		}
-	}
-
-	return titles
-}
-
-// removeTitles removes identified titles from text
-func removeTitles(text string, titles []string) string {
-	if len(titles) == 0 {
-		return text
-	}
-	
-	// Convert to words for better matching
-	words := strings.Fields(text)
-	var resultWords []string
-	
-	// Create a set of title patterns to check against
-	titleSet := make(map[string]bool)
-	for _, title := range titles {
-		titleSet[strings.ToUpper(strings.Trim(title, "."))] = true
-	}
-	
-	// Also add common variations
-	titleVariations := map[string]bool{
-		"DR": true, "DOCTOR": true, "PROF": true, "PROFESSOR": true,
-		"MR": true, "MRS": true, "MS": true, "MISS": true,
-		"SIR": true, "DAME": true, "LORD": true, "LADY": true,
-		"HON": true, "HONOURABLE": true, "REV": true, "REVEREND": true,
-	}
-	
-	for _, word := range words {
-		cleanWord := strings.ToUpper(strings.Trim(word, ".,"))
-		// Skip if this word is a title
-		if titleSet[cleanWord] || titleVariations[cleanWord] {
-			continue
-		}
-		resultWords = append(resultWords, word)
-	}
-
-	return strings.TrimSpace(strings.Join(resultWords, " "))
-}
-
-// formatTitle formats title for display
-func formatTitle(title string) string {
-	switch strings.ToUpper(title) {
-	case "DR", "DOCTOR":
-		return "DR"
-	case "PROF", "PROFESSOR":
-		return "PROF"
-	case "REV", "REVEREND":
-		return "REV"
-	case "HON", "HONOURABLE":
-		return "HON"
-	default:
-		return strings.ToUpper(title)
-	}
-}
-
-// parseVietnameseName handles Vietnamese naming conventions
-func parseVietnameseName(original, transliterated string, titles []string) *NameStructure {
-	parts := strings.Fields(transliterated)
-	if len(parts) == 0 {
-		return &NameStructure{FullASCII: transliterated, Titles: titles}
-	}
-
-	// Gender markers are handled in gender inference, not needed here for name structure
-	_ = strings.Contains(strings.ToLower(original), "văn") || strings.Contains(strings.ToLower(transliterated), "van")
-	_ = strings.Contains(strings.ToLower(original), "thị") || strings.Contains(strings.ToLower(transliterated), "thi")
-
-	var family, first string
-	var middle []string
-
-	if len(parts) >= 2 {
-		// Vietnamese: Family name first, then middle names, then given name
-		family = strings.ToUpper(parts[0])
-		first = strings.Title(strings.ToLower(parts[len(parts)-1]))
-
-		// Middle names (excluding gender markers)
-		for i := 1; i < len(parts)-1; i++ {
-			part := parts[i]
-			partLower := strings.ToLower(part)
-
-			// Skip Vietnamese gender markers
-			if partLower != "van" && partLower != "thi" && partLower != "văn" && partLower != "thị" {
-				middle = append(middle, strings.Title(strings.ToLower(part)))
-			}
-		}
-	} else {
-		// Single name - could be family or given
-		first = strings.Title(strings.ToLower(parts[0]))
-	}
-
-	// Format full ASCII name
-	fullName := formatFullName(family, first, middle, titles)
-
-	return &NameStructure{
-		Family:    family,
-		First:     first,
-		Middle:    middle,
-		Titles:    titles,
-		FullASCII: fullName,
-	}
-}
-
-// parseChineseName handles Chinese naming conventions
-func parseChineseName(text string, titles []string) *NameStructure {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return &NameStructure{FullASCII: text, Titles: titles}
-	}
-
-	var family, first string
-	var middle []string
-
-	if len(parts) >= 2 {
-		// Chinese: Family name first, then given names
-		family = strings.ToUpper(parts[0])
-		if len(parts) == 2 {
-			first = strings.Title(strings.ToLower(parts[1]))
-		} else {
-			// Multiple given names
-			first = strings.Title(strings.ToLower(parts[len(parts)-1]))
-			for i := 1; i < len(parts)-1; i++ {
-				middle = append(middle, strings.Title(strings.ToLower(parts[i])))
-			}
-		}
-	} else {
-		// Single name
-		first = strings.Title(strings.ToLower(parts[0]))
-	}
-
-	fullName := formatFullName(family, first, middle, titles)
-
-	return &NameStructure{
-		Family:    family,
-		First:     first,
-		Middle:    middle,
-		Titles:    titles,
-		FullASCII: fullName,
-	}
-}
-
-// parseArabicName handles Arabic naming conventions
-func parseArabicName(text string, titles []string) *NameStructure {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return &NameStructure{FullASCII: text, Titles: titles}
-	}
-
-	var family, first string
-	var middle []string
-
-	if len(parts) >= 2 {
-		// Arabic: Given name first, patronymics/family name last
-		first = strings.Title(strings.ToLower(parts[0]))
-		family = strings.ToUpper(parts[len(parts)-1])
-
-		// Middle names/patronymics (ibn, bin, bint, etc.)
-		for i := 1; i < len(parts)-1; i++ {
-			middle = append(middle, strings.Title(strings.ToLower(parts[i])))
-		}
-	} else {
-		first = strings.Title(strings.ToLower(parts[0]))
-	}
-
-	fullName := formatFullName(family, first, middle, titles)
-
-	return &NameStructure{
-		Family:    family,
-		First:     first,
-		Middle:    middle,
-		Titles:    titles,
-		FullASCII: fullName,
-	}
-}
-
-// parseMononymOrPatronymic handles single names or patronymic structures
-func parseMononymOrPatronymic(text string, titles []string, script string) *NameStructure {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return &NameStructure{FullASCII: text, Titles: titles}
-	}
-
-	// Check for patronymic indicators
-	hasPatronymic := false
-	for _, part := range parts {
-		lower := strings.ToLower(part)
-		if lower == "bin" || lower == "binti" || lower == "ibn" || lower == "bint" {
-			hasPatronymic = true
-			break
-		}
-	}
-
-	var family, first string
-	var middle []string
-
-	if len(parts) == 1 {
-		// Mononym - single name
-		first = strings.Title(strings.ToLower(parts[0]))
-	} else if hasPatronymic {
-		// Handle patronymic structure
-		first = strings.Title(strings.ToLower(parts[0]))
-		for i := 1; i < len(parts); i++ {
-			middle = append(middle, strings.Title(strings.ToLower(parts[i])))
-		}
-	} else {
-		// Regular multi-part name
-		first = strings.Title(strings.ToLower(parts[0]))
-		if len(parts) > 1 {
-			family = strings.ToUpper(parts[len(parts)-1])
-		}
-		for i := 1; i < len(parts)-1; i++ {
-			middle = append(middle, strings.Title(strings.ToLower(parts[i])))
-		}
-	}
-
-	fullName := formatFullName(family, first, middle, titles)
-
-	return &NameStructure{
-		Family:    family,
-		First:     first,
-		Middle:    middle,
-		Titles:    titles,
-		FullASCII: fullName,
-	}
-}
-
-// parseWesternName handles Western naming conventions
-func parseWesternName(text string, titles []string) *NameStructure {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return &NameStructure{FullASCII: text, Titles: titles}
-	}
-
-	var family, first string
-	var middle []string
-
-	if len(parts) == 1 {
-		first = strings.Title(strings.ToLower(parts[0]))
-	} else if len(parts) == 2 {
-		first = strings.Title(strings.ToLower(parts[0]))
-		family = strings.ToUpper(parts[1])
-	} else {
-		// First + middle names + last
-		first = strings.Title(strings.ToLower(parts[0]))
-		family = strings.ToUpper(parts[len(parts)-1])
-		for i := 1; i < len(parts)-1; i++ {
-			middle = append(middle, strings.Title(strings.ToLower(parts[i])))
-		}
-	}
-
-	fullName := formatFullName(family, first, middle, titles)
-
-	return &NameStructure{
-		Family:    family,
-		First:     first,
-		Middle:    middle,
-		Titles:    titles,
-		FullASCII: fullName,
-	}
-}
-
-// formatFullName creates the complete formatted ASCII name
-func formatFullName(family, first string, middle []string, titles []string) string {
-	var parts []string
-
-	// Add titles
-	for _, title := range titles {
-		parts = append(parts, title)
-	}
-
-	// Add first name
-	if first != "" {
-		parts = append(parts, first)
-	}
-
-	// Add middle names
-	for _, m := range middle {
-		if m != "" {
-			parts = append(parts, m)
-		}
-	}
-
-	// Add family name
-	if family != "" {
-		parts = append(parts, family)
-	}
-
-	return strings.Join(parts, " ")
-}
-
 // inferGender attempts to determine gender from name and cultural markers
 func inferGender(originalText, transliteratedText, inputScript string) *GenderInference {
 	// Default to unknown
 	inference := &GenderInference{
-		Value:      "X",
+		Value:      gender.Unknown,
 		Confidence: 0.1,
 		Source:     "unknown",
 	}
@@ -1102,7 +1813,7 @@ func inferGender(originalText, transliteratedText, inputScript string) *GenderIn
 
 		if strings.Contains(original, "văn") || strings.Contains(transliterated, "van") {
 			return &GenderInference{
-				Value:      "M",
+				Value:      gender.Male,
 				Confidence: 0.85,
 				Source:     "cultural_marker",
 			}
@@ -1110,7 +1821,7 @@ func inferGender(originalText, transliteratedText, inputScript string) *GenderIn
 
 		if strings.Contains(original, "thị") || strings.Contains(transliterated, "thi") {
 			return &GenderInference{
-				Value:      "F",
+				Value:      gender.Female,
 				Confidence: 0.85,
 				Source:     "cultural_marker",
 			}
@@ -1121,11 +1832,11 @@ func inferGender(originalText, transliteratedText, inputScript string) *GenderIn
 	if inputScript == "arabic" {
 		text := strings.ToLower(transliteratedText)
 		if strings.Contains(text, "bin ") || strings.Contains(text, "ibn ") {
-			inference.Value = "M"
+			inference.Value = gender.Male
 			inference.Confidence = 0.75
 			inference.Source = "cultural_marker"
 		} else if strings.Contains(text, "bint ") {
-			inference.Value = "F"
+			inference.Value = gender.Female
 			inference.Confidence = 0.75
 			inference.Source = "cultural_marker"
 		}
@@ -1135,11 +1846,11 @@ func inferGender(originalText, transliteratedText, inputScript string) *GenderIn
 	if inputScript == "indonesian" || inputScript == "malayalam" {
 		text := strings.ToLower(transliteratedText)
 		if strings.Contains(text, "bin ") {
-			inference.Value = "M"
+			inference.Value = gender.Male
 			inference.Confidence = 0.80
 			inference.Source = "cultural_marker"
 		} else if strings.Contains(text, "binti ") {
-			inference.Value = "F"
+			inference.Value = gender.Female
 			inference.Confidence = 0.80
 			inference.Source = "cultural_marker"
 		}
@@ -2,10 +2,31 @@ package transliterate
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode"
 
 	"encore.app/transliterate/internal/detection"
+	"encore.app/transliterate/internal/gender"
+	"encore.app/transliterate/internal/learning"
+	"encore.app/transliterate/internal/nameparser"
+	"encore.app/transliterate/internal/numwords"
+	"encore.app/transliterate/internal/transliteration"
+	textnorm "encore.app/transliterate/internal/unicode"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
 )
 
 // Run tests using `encore test`, which compiles the Encore app and then runs `go test`.
@@ -48,7 +69,7 @@ func TestTransliterateKeyExamples(t *testing.T) {
 			expectedFirst:  "Juergen",
 			expectedMiddle: []string{},
 			expectedTitle:  "Prof",
-			expectedGender: "X",
+			expectedGender: "U",
 			outputScript:   "ascii",
 			inputScript:    "",
 			locale:         nil,
@@ -225,6 +246,15 @@ func TestScriptDetection(t *testing.T) {
 		{"Empty string", "", "unknown"},
 		{"Numbers only", "12345", "unknown"},
 		{"Spaces only", "   ", "unknown"},
+		{"Incidental letter in serial number", "A1-234-567-890", "unknown"},
+		{"Letters suffixing digits", "12345678 AB", "unknown"},
+		{"Unit after number", "100kg", "unknown"},
+		{"Alternating letters and digits", "A1B2C3", "latin"},
+		{"Long alphanumeric code", "A1B2C3D4E5F6G7H8", "latin"},
+		{"Enough letters among digits", "Flat 12/345", "latin"},
+		{"Short name", "Li", "latin"},
+		{"Cyrillic among digits", "5 мая 2024", "cyrillic"},
+		{"Chinese date", "2024年", "chinese"},
 	}
 
 	for _, tt := range tests {
@@ -267,12 +297,42 @@ func TestTransliterationBuiltinRules(t *testing.T) {
 	}
 }
 
+// TestEmptyConversion tests input whose every character converts to nothing
+func TestEmptyConversion(t *testing.T) {
+	output, err := performTransliterationWithValidation("ъь", "cyrillic", "latin", nil)
+	if err != nil {
+		t.Fatalf("performTransliterationWithValidation error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected empty output, got %q", output)
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	result, err := transliteration.NewEngine(config, nil).Transliterate(context.Background(), "ъь", "cyrillic", "latin", "")
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if result.Method != "empty" || result.Confidence > 0.2 {
+		t.Errorf("Expected the empty method with low confidence, got %s at %.2f", result.Method, result.Confidence)
+	}
+
+	resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "ъь", InputScript: "cyrillic", OutputScript: "latin", Preview: true})
+	if err != nil {
+		t.Fatalf("Transliterate endpoint failed: %v", err)
+	}
+	if resp.OutputText != "" || *resp.ConfidenceScore > 0.2 {
+		t.Errorf("Expected empty output with low confidence, got %q at %.2f", resp.OutputText, *resp.ConfidenceScore)
+	}
+}
+
 // TestValidation tests input validation
 func TestValidation(t *testing.T) {
 	tests := []struct {
-		name        string
-		req         *TransliterationRequest
-		expectError bool
+		name           string
+		req            *TransliterationRequest
+		expectError    bool
+		expectedReason string
 	}{
 		{
 			name: "Valid request",
@@ -283,9 +343,10 @@ func TestValidation(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name:        "Nil request",
-			req:         nil,
-			expectError: true,
+			name:           "Nil request",
+			req:            nil,
+			expectError:    true,
+			expectedReason: ReasonRequestMissing,
 		},
 		{
 			name: "Empty text",
@@ -293,14 +354,16 @@ func TestValidation(t *testing.T) {
 				Text:         "",
 				OutputScript: "ascii",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonTextEmpty,
 		},
 		{
 			name: "No output script",
 			req: &TransliterationRequest{
 				Text: "Hello",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonOutputScriptRequired,
 		},
 		{
 			name: "Invalid output script",
@@ -308,7 +371,38 @@ func TestValidation(t *testing.T) {
 				Text:         "Hello",
 				OutputScript: "klingon",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonUnsupportedOutputScript,
+		},
+		{
+			name: "Invalid additional output script",
+			req: &TransliterationRequest{
+				Text:                    "Hello",
+				OutputScript:            "ascii",
+				AdditionalOutputScripts: []string{"klingon"},
+			},
+			expectError:    true,
+			expectedReason: ReasonUnsupportedOutputScript,
+		},
+		{
+			name: "Additional output script repeats output_script",
+			req: &TransliterationRequest{
+				Text:                    "Привет",
+				OutputScript:            "latin",
+				AdditionalOutputScripts: []string{"ascii", "latin"},
+			},
+			expectError:    true,
+			expectedReason: ReasonDuplicateOutputScript,
+		},
+		{
+			name: "Too many additional output scripts",
+			req: &TransliterationRequest{
+				Text:                    "Привет",
+				OutputScript:            "latin",
+				AdditionalOutputScripts: []string{"ascii", "greek", "cyrillic", "chinese"},
+			},
+			expectError:    true,
+			expectedReason: ReasonTooManyOutputScripts,
 		},
 		{
 			name: "Too long text",
@@ -316,7 +410,8 @@ func TestValidation(t *testing.T) {
 				Text:         strings.Repeat("x", 10001),
 				OutputScript: "ascii",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonTextTooLong,
 		},
 	}
 
@@ -329,10 +424,70 @@ func TestValidation(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
+			if tt.expectError {
+				assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+			}
 		})
 	}
 }
 
+// TestValidationErrorList tests that every invalid field is reported in one error
+func TestValidationErrorList(t *testing.T) {
+	fieldErrors := func(t *testing.T, err error) []FieldError {
+		t.Helper()
+		var apiErr *errs.Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected *errs.Error, got %T", err)
+		}
+		details, ok := apiErr.Details.(ErrorDetails)
+		if !ok {
+			t.Fatalf("Details = %T, want ErrorDetails", apiErr.Details)
+		}
+		return details.Errors
+	}
+
+	t.Run("Transliteration request", func(t *testing.T) {
+		req := &TransliterationRequest{
+			Text:            "",
+			OutputScript:    "klingon",
+			BoundarySpacing: "sometimes",
+			Standard:        "unknown",
+		}
+		err := validateTransliterationRequest(req)
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonTextEmpty)
+
+		expected := []FieldError{
+			{Field: "text", Code: ReasonTextEmpty},
+			{Field: "output_script", Code: ReasonUnsupportedOutputScript},
+			{Field: "boundary_spacing", Code: ReasonInvalidBoundarySpacing},
+			{Field: "standard", Code: ReasonUnsupportedStandard},
+		}
+		got := fieldErrors(t, err)
+		if len(got) != len(expected) {
+			t.Fatalf("got %d errors %+v, want %d", len(got), got, len(expected))
+		}
+		for i, want := range expected {
+			if got[i].Field != want.Field || got[i].Code != want.Code {
+				t.Errorf("errors[%d] = %s/%s, want %s/%s", i, got[i].Field, got[i].Code, want.Field, want.Code)
+			}
+			if got[i].Message == "" {
+				t.Errorf("errors[%d] has no message", i)
+			}
+		}
+	})
+
+	t.Run("Feedback request", func(t *testing.T) {
+		req := &FeedbackRequest{SuggestedOutput: " ", FeedbackType: "invalid"}
+		err := validateFeedbackRequest(req)
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonSuggestedOutputEmpty)
+
+		got := fieldErrors(t, err)
+		if len(got) != 2 || got[0].Field != "suggested_output" || got[1].Code != ReasonInvalidFeedbackType {
+			t.Errorf("errors = %+v, want suggested_output and feedback_type", got)
+		}
+	})
+}
+
 // TestFeedback tests the feedback submission functionality
 func TestFeedback(t *testing.T) {
 	// First create a transliteration
@@ -364,9 +519,10 @@ func TestFeedback(t *testing.T) {
 // TestFeedbackValidation tests feedback validation
 func TestFeedbackValidation(t *testing.T) {
 	tests := []struct {
-		name        string
-		req         *FeedbackRequest
-		expectError bool
+		name           string
+		req            *FeedbackRequest
+		expectError    bool
+		expectedReason string
 	}{
 		{
 			name: "Valid feedback",
@@ -377,9 +533,10 @@ func TestFeedbackValidation(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name:        "Nil request",
-			req:         nil,
-			expectError: true,
+			name:           "Nil request",
+			req:            nil,
+			expectError:    true,
+			expectedReason: ReasonRequestMissing,
 		},
 		{
 			name: "Empty suggested output",
@@ -387,7 +544,8 @@ func TestFeedbackValidation(t *testing.T) {
 				SuggestedOutput: "",
 				FeedbackType:    "correction",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonSuggestedOutputEmpty,
 		},
 		{
 			name: "Invalid feedback type",
@@ -395,7 +553,8 @@ func TestFeedbackValidation(t *testing.T) {
 				SuggestedOutput: "Better output",
 				FeedbackType:    "invalid",
 			},
-			expectError: true,
+			expectError:    true,
+			expectedReason: ReasonInvalidFeedbackType,
 		},
 	}
 
@@ -408,13 +567,159 @@ func TestFeedbackValidation(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
+			if tt.expectError {
+				assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+			}
+		})
+	}
+}
+
+// TestErrorCodes tests the error code and reason returned by each endpoint failure path
+func TestErrorCodes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		call           func() error
+		expectedCode   errs.ErrCode
+		expectedReason string
+	}{
+		{
+			name: "Unsupported input script",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Hello", InputScript: "klingon", OutputScript: "ascii"})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonUnsupportedInputScript,
+		},
+		{
+			name: "Invalid locale",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Hello", OutputScript: "ascii", InputLocale: stringPtr("not a locale")})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidLocale,
+		},
+		{
+			name: "Script undetectable",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "12345", OutputScript: "ascii"})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonScriptUndetectable,
+		},
+		{
+			name: "Detection confidence too low",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Привет мир hello world", OutputScript: "ascii", MinDetectionConfidence: 0.9})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonDetectionConfidenceLow,
+		},
+		{
+			name: "Invalid min detection confidence",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Hello", OutputScript: "ascii", MinDetectionConfidence: 1.5})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidMinDetectionConfidence,
+		},
+		{
+			name: "Cache only with verbose",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Hello", OutputScript: "ascii", CacheOnly: true, Verbose: true})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidCacheOnly,
+		},
+		{
+			name: "Unsupported script pair",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Hello", InputScript: "latin", OutputScript: "chinese"})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonUnsupportedScriptPair,
+		},
+		{
+			name: "Invalid code points rejected",
+			call: func() error {
+				_, err := Transliterate(ctx, &TransliterationRequest{Text: "Ivan\uE000", OutputScript: "ascii", InvalidCodePoints: codePointsReject})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidCodePoints,
+		},
+		{
+			name: "Invalid UUID on retrieval",
+			call: func() error {
+				_, err := GetTransliteration(ctx, "not-a-uuid")
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidID,
+		},
+		{
+			name: "Invalid feedback type",
+			call: func() error {
+				return SubmitFeedback(ctx, "123e4567-e89b-12d3-a456-426614174000", &FeedbackRequest{SuggestedOutput: "Li", FeedbackType: "invalid"})
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidFeedbackType,
+		},
+		{
+			name: "Invalid UUID on feedback",
+			call: func() error {
+				return SubmitFeedback(ctx, "not-a-uuid", &FeedbackRequest{SuggestedOutput: "Li", FeedbackType: "correction"})
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonInvalidID,
+		},
+		{
+			name: "Empty name to parse",
+			call: func() error {
+				_, err := ParseName(ctx, &ParseNameRequest{Text: "  "})
+				return err
+			},
+			expectedCode:   errs.InvalidArgument,
+			expectedReason: ReasonTextEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrorReason(t, tt.call(), tt.expectedCode, tt.expectedReason)
 		})
 	}
 }
 
+// assertErrorReason checks that err is an *errs.Error with the expected code and reason
+func assertErrorReason(t *testing.T, err error, expectedCode errs.ErrCode, expectedReason string) {
+	t.Helper()
+
+	var apiErr *errs.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *errs.Error, got %T", err)
+	}
+	if apiErr.Code != expectedCode {
+		t.Errorf("Code = %v, want %v", apiErr.Code, expectedCode)
+	}
+	details, ok := apiErr.Details.(ErrorDetails)
+	if !ok {
+		t.Fatalf("Details = %T, want ErrorDetails", apiErr.Details)
+	}
+	if details.Reason != expectedReason {
+		t.Errorf("Reason = %q, want %q", details.Reason, expectedReason)
+	}
+}
+
 // TestConfidenceCalculation tests confidence score calculation
-// TestConfidenceCalculation - commented out as calculateConfidence is now internal
-/*
 func TestConfidenceCalculation(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -430,20 +735,180 @@ func TestConfidenceCalculation(t *testing.T) {
 		{"Cyrillic to Latin", "привет", "privet", "cyrillic", "latin", 0.6, 0.9},
 		{"Empty output", "test", "", "latin", "ascii", 0.59, 0.61},
 		{"Reasonable length preservation", "hello", "world", "latin", "ascii", 0.7, 1.0},
+		{"Unmapped placeholders", "你好世界", "ni ? ? jie", "chinese", "ascii", 0.3, 0.5},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			confidence := calculateConfidence(tt.inputText, tt.outputText, tt.inputScript, tt.outputScript)
+			factors := calculateConfidence(tt.inputText, tt.outputText, tt.inputScript, tt.outputScript, 1.0)
+			confidence := factors.Score
 			if confidence < tt.expectedMin || confidence > tt.expectedMax {
-				t.Errorf("calculateConfidence(%q, %q, %q, %q) = %f, want between %f and %f",
+				t.Errorf("calculateConfidence(%q, %q, %q, %q, 1.0) = %f, want between %f and %f",
 					tt.inputText, tt.outputText, tt.inputScript, tt.outputScript,
 					confidence, tt.expectedMin, tt.expectedMax)
 			}
+
+			// The factors must explain the score, up to clamping to the pair's bounds
+			sum := factors.Base + factors.ScriptCompatibility + factors.Coverage + factors.Length + factors.Unmapped + factors.Characters
+			clamped := boundConfidence(tt.inputScript, tt.outputScript, sum)
+			if math.Abs(clamped-factors.Score) > 1e-9 {
+				t.Errorf("Factors %+v sum to %f, but score is %f", factors, sum, factors.Score)
+			}
+		})
+	}
+}
+
+// TestConfidenceFactorsExplainScore tests that a transliteration's confidence_factors sum to its
+// confidence_score, including the penalty for characters read uncertainly
+func TestConfidenceFactorsExplainScore(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		inputScript  string
+		outputScript string
+	}{
+		{"Cyrillic", "Пётр Чайковский", "cyrillic", "latin"},
+		{"Chinese", "李小明", "chinese", "latin"},
+		{"Latin", "Anna Smith", "latin", "ascii"},
+		{"Approximated", "Zoë Ångström", "latin", "ascii"},
+		{"Unmapped", "Привет ☃", "cyrillic", "ascii"},
+		{"Empty output", "ъь", "cyrillic", "latin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: tt.text, InputScript: tt.inputScript, OutputScript: tt.outputScript, Preview: true})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			factors := resp.ConfidenceFactors
+			sum := factors.Base + factors.ScriptCompatibility + factors.Coverage + factors.Length + factors.Unmapped + factors.Characters
+			if clamped := boundConfidence(tt.inputScript, tt.outputScript, sum); math.Abs(clamped-*resp.ConfidenceScore) > 1e-9 {
+				t.Errorf("Factors %+v sum to %f, but confidence_score is %f", *factors, clamped, *resp.ConfidenceScore)
+			}
+			if factors.Score != *resp.ConfidenceScore {
+				t.Errorf("Factors score %f differs from confidence_score %f", factors.Score, *resp.ConfidenceScore)
+			}
+		})
+	}
+
+	// Characters the rules only approximate cost confidence that a clean output shape can't hide
+	clean := calculateConfidence("Анна", "Anna", "cyrillic", "latin", 1.0)
+	uncertain := calculateConfidence("Анна", "Anna", "cyrillic", "latin", 0.6)
+	if math.Abs(uncertain.Characters+0.4) > 1e-9 || uncertain.Score >= clean.Score {
+		t.Errorf("Expected a -0.4 characters penalty to lower %f, got %+v", clean.Score, uncertain)
+	}
+}
+
+// TestLengthPlausibilityCountsRunes tests that the length factor compares characters, not bytes
+func TestLengthPlausibilityCountsRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		output   string
+		expected float64
+	}{
+		// 6 bytes in, 6 bytes out either way, but 2 characters become 6
+		{"Chinese greeting", "你好", "ni hao", 0.1},
+		// 3 bytes in, 6 out would pass a byte ratio; 1 character becoming 6 does not
+		{"Implausible expansion", "你", "zhuang", 0.0},
+		// 12 bytes in, 6 out; 6 characters in, 6 out
+		{"Cyrillic", "привет", "privet", 0.1},
+		{"Empty output", "你好", "", 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateLengthPlausibility(tt.input, tt.output); got != tt.expected {
+				t.Errorf("calculateLengthPlausibility(%q, %q) = %v, want %v", tt.input, tt.output, got, tt.expected)
+			}
 		})
 	}
+
+	factors := calculateConfidence("你好", "ni hao", "chinese", "latin", 1.0)
+	if factors.Length != 0.1 || math.Abs(factors.Score-0.7) > 1e-9 {
+		t.Errorf("calculateConfidence(你好, ni hao, 1.0) = %+v, want length 0.1 and score 0.7", factors)
+	}
+}
+
+// TestUnmappedPenalty tests that "?" placeholders lower the confidence score in proportion
+func TestUnmappedPenalty(t *testing.T) {
+	mapped := calculateConfidence("你好世界朋友", "ni hao shi jie peng you", "chinese", "ascii", 1.0)
+	partial := calculateConfidence("你好世界朋友", "ni ? shi ? peng ?", "chinese", "ascii", 1.0)
+	unmapped := calculateConfidence("你好世界朋友", "? ? ? ? ? ?", "chinese", "ascii", 1.0)
+
+	if mapped.Unmapped != 0 {
+		t.Errorf("Fully mapped output has unmapped penalty %f", mapped.Unmapped)
+	}
+	if math.Abs(partial.Unmapped+0.25) > 1e-9 {
+		t.Errorf("Half-unmapped output has penalty %f, want -0.25", partial.Unmapped)
+	}
+	if !(mapped.Score > partial.Score && partial.Score > unmapped.Score) {
+		t.Errorf("Expected scores to fall with unmapped characters, got %f, %f and %f", mapped.Score, partial.Score, unmapped.Score)
+	}
+
+	tests := []struct {
+		input    string
+		output   string
+		expected int
+	}{
+		{"你好世界", "ni hao shi jie", 0},
+		{"你好世界", "ni ? ? jie", 2},
+		{"谁?", "shui?", 0},
+		{"谁?", "??", 1},
+	}
+	for _, tt := range tests {
+		if got := countUnmapped(tt.input, tt.output); got != tt.expected {
+			t.Errorf("countUnmapped(%q, %q) = %d, want %d", tt.input, tt.output, got, tt.expected)
+		}
+	}
+
+	// A question mark carried over from the input is not a placeholder
+	if factors := calculateConfidence("Кто?", "Kto?", "cyrillic", "latin", 1.0); factors.Unmapped != 0 {
+		t.Errorf("Input question mark penalized: %+v", factors)
+	}
+}
+
+// TestConfidenceBounds tests that each script pair's confidence stays within its bounds
+func TestConfidenceBounds(t *testing.T) {
+	// A clean Latin to ASCII conversion can be fully trusted
+	if factors := calculateConfidence("hello", "hello", "latin", "ascii", 1.0); factors.Score != 1.0 {
+		t.Errorf("Expected Latin to ASCII to reach 1.0, got %+v", factors)
+	}
+	resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Anna Smith", InputScript: "latin", OutputScript: "ascii", Preview: true})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if *resp.ConfidenceScore != 1.0 {
+		t.Errorf("Expected Latin to ASCII confidence 1.0, got %f", *resp.ConfidenceScore)
+	}
+
+	// Chinese readings are ambiguous, however clean the output looks
+	ceiling := confidenceBoundFor("chinese", "latin").Ceiling
+	if ceiling != 0.8 {
+		t.Errorf("Expected Chinese to Latin ceiling 0.8, got %f", ceiling)
+	}
+	for _, text := range []string{"李小明", "王芳", "欧阳娜娜", "你好"} {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: text, InputScript: "chinese", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate(%s) failed: %v", text, err)
+		}
+		if *resp.ConfidenceScore > ceiling {
+			t.Errorf("%s: confidence %f exceeds the ceiling %f", text, *resp.ConfidenceScore, ceiling)
+		}
+		if resp.ConfidenceFactors.Score > ceiling {
+			t.Errorf("%s: structural score %f exceeds the ceiling %f", text, resp.ConfidenceFactors.Score, ceiling)
+		}
+	}
+
+	// A sum beyond the ceiling is clamped, not reported
+	if got := boundConfidence("chinese", "ascii", 0.95); got != 0.8 {
+		t.Errorf("boundConfidence(chinese, ascii, 0.95) = %f, want 0.8", got)
+	}
+	if got := boundConfidence("latin", "ascii", -0.2); got != 0.0 {
+		t.Errorf("boundConfidence(latin, ascii, -0.2) = %f, want 0.0", got)
+	}
 }
-*/
 
 // TestUUIDValidation tests UUID format validation
 func TestUUIDValidation(t *testing.T) {
@@ -516,9 +981,9 @@ func TestNameParsing(t *testing.T) {
 			expected: NameStructure{
 				Family:    "NGUYEN",
 				First:     "Minh",
-				Middle:    []string{},
+				Middle:    []string{"Van"},
 				Titles:    []string{},
-				FullASCII: "Minh NGUYEN",
+				FullASCII: "NGUYEN Van Minh",
 			},
 		},
 		{
@@ -529,9 +994,48 @@ func TestNameParsing(t *testing.T) {
 			expected: NameStructure{
 				Family:    "TRAN",
 				First:     "Lan",
+				Middle:    []string{"Thi"},
+				Titles:    []string{},
+				FullASCII: "TRAN Thi Lan",
+			},
+		},
+		{
+			name:           "Vietnamese compound given name with Thi marker",
+			originalText:   "Nguyễn Thị Ngọc Lan",
+			transliterated: "Nguyen Thi Ngoc Lan",
+			inputScript:    "vietnamese",
+			expected: NameStructure{
+				Family:    "NGUYEN",
+				First:     "Ngoc Lan",
+				Middle:    []string{"Thi"},
+				Titles:    []string{},
+				FullASCII: "NGUYEN Thi Ngoc Lan",
+			},
+		},
+		{
+			name:           "Vietnamese compound given name with Van marker",
+			originalText:   "Lê Văn Đức Anh",
+			transliterated: "Le Van Duc Anh",
+			inputScript:    "vietnamese",
+			expected: NameStructure{
+				Family:    "LE",
+				First:     "Duc Anh",
+				Middle:    []string{"Van"},
+				Titles:    []string{},
+				FullASCII: "LE Van Duc Anh",
+			},
+		},
+		{
+			name:           "Vietnamese compound given name without marker",
+			originalText:   "Phạm Minh Tuấn",
+			transliterated: "Pham Minh Tuan",
+			inputScript:    "vietnamese",
+			expected: NameStructure{
+				Family:    "PHAM",
+				First:     "Minh Tuan",
 				Middle:    []string{},
 				Titles:    []string{},
-				FullASCII: "Lan TRAN",
+				FullASCII: "PHAM Minh Tuan",
 			},
 		},
 		{
@@ -544,7 +1048,7 @@ func TestNameParsing(t *testing.T) {
 				First:     "Xiaoming",
 				Middle:    []string{},
 				Titles:    []string{},
-				FullASCII: "Xiaoming LI",
+				FullASCII: "LI Xiaoming",
 			},
 		},
 		{
@@ -595,8 +1099,8 @@ func TestNameParsing(t *testing.T) {
 				Family:    "SMITH",
 				First:     "John",
 				Middle:    []string{},
-				Titles:    []string{"DR"},
-				FullASCII: "DR John SMITH",
+				Titles:    []string{"Dr"},
+				FullASCII: "Dr John SMITH",
 			},
 		},
 		{
@@ -612,81 +1116,356 @@ func TestNameParsing(t *testing.T) {
 				FullASCII: "Mary Jane WATSON",
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := parseName(tt.originalText, tt.transliterated, tt.inputScript)
-			if result == nil {
-				t.Fatal("parseName returned nil")
-			}
-
-			if result.Family != tt.expected.Family {
-				t.Errorf("Family = %q, want %q", result.Family, tt.expected.Family)
-			}
-			if result.First != tt.expected.First {
-				t.Errorf("First = %q, want %q", result.First, tt.expected.First)
-			}
-			if len(result.Middle) != len(tt.expected.Middle) {
-				t.Errorf("Middle length = %d, want %d", len(result.Middle), len(tt.expected.Middle))
-			} else {
-				for i, middle := range result.Middle {
-					if middle != tt.expected.Middle[i] {
-						t.Errorf("Middle[%d] = %q, want %q", i, middle, tt.expected.Middle[i])
-					}
-				}
-			}
-			if len(result.Titles) != len(tt.expected.Titles) {
-				t.Errorf("Titles length = %d, want %d", len(result.Titles), len(tt.expected.Titles))
-			} else {
-				for i, title := range result.Titles {
-					if title != tt.expected.Titles[i] {
-						t.Errorf("Titles[%d] = %q, want %q", i, title, tt.expected.Titles[i])
-					}
-				}
-			}
-			if result.FullASCII != tt.expected.FullASCII {
-				t.Errorf("FullASCII = %q, want %q", result.FullASCII, tt.expected.FullASCII)
-			}
-		})
-	}
-}
-
-// TestGenderInference tests gender inference from cultural markers
-func TestGenderInference(t *testing.T) {
-	tests := []struct {
-		name           string
-		originalText   string
-		transliterated string
-		inputScript    string
-		expectedGender string
-		minConfidence  float64
-		expectedSource string
-	}{
 		{
-			name:           "Vietnamese Male Văn marker",
-			originalText:   "Nguyễn Văn Minh",
-			transliterated: "Nguyen Van Minh",
-			inputScript:    "vietnamese",
-			expectedGender: "M",
-			minConfidence:  0.8,
-			expectedSource: "cultural_marker",
+			name:           "Western with Suffix",
+			originalText:   "Martin Luther King Jr.",
+			transliterated: "Martin Luther King Jr.",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "KING",
+				First:     "Martin",
+				Middle:    []string{"Luther"},
+				Titles:    []string{},
+				Suffixes:  []string{"Jr"},
+				FullASCII: "Martin Luther KING Jr",
+			},
 		},
 		{
-			name:           "Vietnamese Female Thị marker",
-			originalText:   "Trần Thị Lan",
-			transliterated: "Tran Thi Lan",
-			inputScript:    "vietnamese",
-			expectedGender: "F",
-			minConfidence:  0.8,
-			expectedSource: "cultural_marker",
+			name:           "Dutch Particle van",
+			originalText:   "Ludwig van Beethoven",
+			transliterated: "Ludwig van Beethoven",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "VAN BEETHOVEN",
+				First:     "Ludwig",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"van"},
+				FullASCII: "Ludwig VAN BEETHOVEN",
+			},
 		},
 		{
-			name:           "Arabic Male bin patronymic",
-			originalText:   "أحمد بن محمد",
-			transliterated: "Ahmed bin Mohammed",
-			inputScript:    "arabic",
-			expectedGender: "M",
+			name:           "French Particle de",
+			originalText:   "Charles de Gaulle",
+			transliterated: "Charles de Gaulle",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "DE GAULLE",
+				First:     "Charles",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"de"},
+				FullASCII: "Charles DE GAULLE",
+			},
+		},
+		{
+			name:           "Spanish Particle del",
+			originalText:   "Maria del Carmen Lopez",
+			transliterated: "Maria del Carmen Lopez",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "LOPEZ",
+				First:     "Maria",
+				Middle:    []string{"del", "Carmen"},
+				Titles:    []string{},
+				Particles: []string{"del"},
+				FullASCII: "Maria del Carmen LOPEZ",
+			},
+		},
+		{
+			name:           "Hyphenated family name",
+			originalText:   "Daniel Day-Lewis",
+			transliterated: "Daniel Day-Lewis",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "DAY-LEWIS",
+				First:     "Daniel",
+				Middle:    []string{},
+				Titles:    []string{},
+				FullASCII: "Daniel DAY-LEWIS",
+			},
+		},
+		{
+			name:           "Apostrophe family name",
+			originalText:   "Ronald O'Sullivan",
+			transliterated: "Ronald O'Sullivan",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "O'SULLIVAN",
+				First:     "Ronald",
+				Middle:    []string{},
+				Titles:    []string{},
+				FullASCII: "Ronald O'SULLIVAN",
+			},
+		},
+		{
+			name:           "Arabic article hyphenated",
+			originalText:   "أحمد المصري",
+			transliterated: "Ahmed al-Masri",
+			inputScript:    "arabic",
+			expected: NameStructure{
+				Family:    "AL-MASRI",
+				First:     "Ahmed",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"al"},
+				FullASCII: "Ahmed AL-MASRI",
+			},
+		},
+		{
+			name:           "Arabic article el",
+			originalText:   "فاطمة السيد",
+			transliterated: "Fatima El-Sayed",
+			inputScript:    "arabic",
+			expected: NameStructure{
+				Family:    "EL-SAYED",
+				First:     "Fatima",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"el"},
+				FullASCII: "Fatima EL-SAYED",
+			},
+		},
+		{
+			name:           "Arabic article written apart",
+			originalText:   "أحمد المصري",
+			transliterated: "Ahmed al Masri",
+			inputScript:    "arabic",
+			expected: NameStructure{
+				Family:    "AL-MASRI",
+				First:     "Ahmed",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"al"},
+				FullASCII: "Ahmed AL-MASRI",
+			},
+		},
+		{
+			name:           "Arabic article inside the name",
+			originalText:   "محمد الأمين حداد",
+			transliterated: "Muhammad al Amin Haddad",
+			inputScript:    "arabic",
+			expected: NameStructure{
+				Family:    "HADDAD",
+				First:     "Muhammad",
+				Middle:    []string{"al-Amin"},
+				Titles:    []string{},
+				Particles: []string{"al"},
+				FullASCII: "Muhammad al-Amin HADDAD",
+			},
+		},
+		{
+			name:           "Multi-word particle family name",
+			originalText:   "Oscar de la Hoya",
+			transliterated: "Oscar de la Hoya",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "DE LA HOYA",
+				First:     "Oscar",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"de", "la"},
+				FullASCII: "Oscar DE LA HOYA",
+			},
+		},
+		{
+			name:           "Hyphenated given name",
+			originalText:   "jean-luc picard",
+			transliterated: "jean-luc picard",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "PICARD",
+				First:     "Jean-Luc",
+				Middle:    []string{},
+				Titles:    []string{},
+				FullASCII: "Jean-Luc PICARD",
+			},
+		},
+		{
+			name:           "Mc and apostrophe casing",
+			originalText:   "mary mcdonald d'angelo smith",
+			transliterated: "mary mcdonald d'angelo smith",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "SMITH",
+				First:     "Mary",
+				Middle:    []string{"McDonald", "D'Angelo"},
+				Titles:    []string{},
+				FullASCII: "Mary McDonald D'Angelo SMITH",
+			},
+		},
+		{
+			name:           "Mc, O' and known Mac prefixes",
+			originalText:   "angus mcdonald o'brien macleod mackenzie",
+			transliterated: "angus mcdonald o'brien macleod mackenzie",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "MACKENZIE",
+				First:     "Angus",
+				Middle:    []string{"McDonald", "O'Brien", "MacLeod"},
+				Titles:    []string{},
+				FullASCII: "Angus McDonald O'Brien MacLeod MACKENZIE",
+			},
+		},
+		{
+			name:           "Particles stay lowercase",
+			originalText:   "MARIA DEL CARMEN DE VRIES",
+			transliterated: "MARIA DEL CARMEN DE VRIES",
+			inputScript:    "latin",
+			expected: NameStructure{
+				Family:    "DE VRIES",
+				First:     "Maria",
+				Middle:    []string{"del", "Carmen"},
+				Titles:    []string{},
+				Particles: []string{"del", "de"},
+				FullASCII: "Maria del Carmen DE VRIES",
+			},
+		},
+		{
+			name:           "Arabic apostrophe is not a prefix",
+			originalText:   "مأمون الرشيد",
+			transliterated: "ma'mun al-rashid",
+			inputScript:    "arabic",
+			expected: NameStructure{
+				Family:    "AL-RASHID",
+				First:     "Ma'mun",
+				Middle:    []string{},
+				Titles:    []string{},
+				Particles: []string{"al"},
+				FullASCII: "Ma'mun AL-RASHID",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := analyzeName(tt.originalText, tt.transliterated, determineCulture(tt.inputScript, ""), "", true)
+			if result == nil {
+				t.Fatal("analyzeName returned nil name")
+			}
+
+			if result.Family != tt.expected.Family {
+				t.Errorf("Family = %q, want %q", result.Family, tt.expected.Family)
+			}
+			if result.First != tt.expected.First {
+				t.Errorf("First = %q, want %q", result.First, tt.expected.First)
+			}
+			if len(result.Middle) != len(tt.expected.Middle) {
+				t.Errorf("Middle length = %d, want %d", len(result.Middle), len(tt.expected.Middle))
+			} else {
+				for i, middle := range result.Middle {
+					if middle != tt.expected.Middle[i] {
+						t.Errorf("Middle[%d] = %q, want %q", i, middle, tt.expected.Middle[i])
+					}
+				}
+			}
+			if len(result.Titles) != len(tt.expected.Titles) {
+				t.Errorf("Titles length = %d, want %d", len(result.Titles), len(tt.expected.Titles))
+			} else {
+				for i, title := range result.Titles {
+					if title != tt.expected.Titles[i] {
+						t.Errorf("Titles[%d] = %q, want %q", i, title, tt.expected.Titles[i])
+					}
+				}
+			}
+			if strings.Join(result.Suffixes, " ") != strings.Join(tt.expected.Suffixes, " ") {
+				t.Errorf("Suffixes = %v, want %v", result.Suffixes, tt.expected.Suffixes)
+			}
+			if strings.Join(result.Particles, " ") != strings.Join(tt.expected.Particles, " ") {
+				t.Errorf("Particles = %v, want %v", result.Particles, tt.expected.Particles)
+			}
+			if result.FullASCII != tt.expected.FullASCII {
+				t.Errorf("FullASCII = %q, want %q", result.FullASCII, tt.expected.FullASCII)
+			}
+		})
+	}
+}
+
+// TestRussianPatronymics tests patronymic parsing and the gender it signals
+func TestRussianPatronymics(t *testing.T) {
+	tests := []struct {
+		name               string
+		originalText       string
+		transliterated     string
+		expectedFamily     string
+		expectedFirst      string
+		expectedPatronymic string
+		expectedFullASCII  string
+		expectedGender     string
+	}{
+		{"Male given first", "Иван Иванович Петров", "Ivan Ivanovich Petrov", "PETROV", "Ivan", "Ivanovich", "Ivan Ivanovich PETROV", gender.Male},
+		{"Female given first", "Анна Сергеевна Волкова", "Anna Sergeevna Volkova", "VOLKOVA", "Anna", "Sergeevna", "Anna Sergeevna VOLKOVA", gender.Female},
+		{"Official family first", "Петров Иван Иванович", "Petrov Ivan Ivanovich", "PETROV", "Ivan", "Ivanovich", "Ivan Ivanovich PETROV", gender.Male},
+		{"Ilyich", "Владимир Ильич Ленин", "Vladimir Ilyich Lenin", "LENIN", "Vladimir", "Ilyich", "Vladimir Ilyich LENIN", gender.Male},
+		{"No patronymic", "Анна Каренина", "Anna Karenina", "KARENINA", "Anna", "", "Anna KARENINA", gender.Female},
+		{"Surname ending in -ich", "Иван Карпович", "Ivan Karpovich", "KARPOVICH", "Ivan", "", "Ivan KARPOVICH", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, inferred := analyzeName(tt.originalText, tt.transliterated, determineCulture("cyrillic", ""), "", true)
+			if name.Family != tt.expectedFamily || name.First != tt.expectedFirst || name.Patronymic != tt.expectedPatronymic {
+				t.Errorf("Parsed %q as family %q, first %q, patronymic %q", tt.transliterated, name.Family, name.First, name.Patronymic)
+			}
+			if len(name.Middle) != 0 {
+				t.Errorf("Middle = %v, want none", name.Middle)
+			}
+			if name.FullASCII != tt.expectedFullASCII {
+				t.Errorf("FullASCII = %q, want %q", name.FullASCII, tt.expectedFullASCII)
+			}
+			if tt.expectedGender != "" && inferred.Value != tt.expectedGender {
+				t.Errorf("Gender = %q (%s), want %q", inferred.Value, inferred.Reason, tt.expectedGender)
+			}
+		})
+	}
+
+	name, _ := analyzeName("Анна Сергеевна Волкова", "Anna Sergeevna Volkova", "russian", "ru", true)
+	if got := nameparser.FormatName(name, nameparser.FormatFamilyFirst); got != "VOLKOVA Anna Sergeevna" {
+		t.Errorf("Family-first format = %q, want %q", got, "VOLKOVA Anna Sergeevna")
+	}
+	if got := nameparser.FormatName(name, nameparser.FormatSortable); got != "VOLKOVA, Anna Sergeevna" {
+		t.Errorf("Sortable format = %q, want %q", got, "VOLKOVA, Anna Sergeevna")
+	}
+	if tokens := buildSearchTokens(name, "", nil); !slices.Contains(tokens, "sergeevna") {
+		t.Errorf("Search tokens %v should include the patronymic", tokens)
+	}
+}
+
+// TestGenderInference tests gender inference from cultural markers
+func TestGenderInference(t *testing.T) {
+	tests := []struct {
+		name           string
+		originalText   string
+		transliterated string
+		inputScript    string
+		expectedGender string
+		minConfidence  float64
+		expectedSource string
+	}{
+		{
+			name:           "Vietnamese Male Văn marker",
+			originalText:   "Nguyễn Văn Minh",
+			transliterated: "Nguyen Van Minh",
+			inputScript:    "vietnamese",
+			expectedGender: "M",
+			minConfidence:  0.8,
+			expectedSource: "cultural_marker",
+		},
+		{
+			name:           "Vietnamese Female Thị marker",
+			originalText:   "Trần Thị Lan",
+			transliterated: "Tran Thi Lan",
+			inputScript:    "vietnamese",
+			expectedGender: "F",
+			minConfidence:  0.8,
+			expectedSource: "cultural_marker",
+		},
+		{
+			name:           "Arabic Male bin patronymic",
+			originalText:   "أحمد بن محمد",
+			transliterated: "Ahmed bin Mohammed",
+			inputScript:    "arabic",
+			expectedGender: "M",
 			minConfidence:  0.7,
 			expectedSource: "cultural_marker",
 		},
@@ -713,7 +1492,7 @@ func TestGenderInference(t *testing.T) {
 			originalText:   "Suharto",
 			transliterated: "Suharto",
 			inputScript:    "indonesian",
-			expectedGender: "X",
+			expectedGender: "U",
 			minConfidence:  0.0,
 			expectedSource: "unknown",
 		},
@@ -722,7 +1501,7 @@ func TestGenderInference(t *testing.T) {
 			originalText:   "李小明",
 			transliterated: "Li Xiaoming",
 			inputScript:    "chinese",
-			expectedGender: "X",
+			expectedGender: "U",
 			minConfidence:  0.0,
 			expectedSource: "unknown",
 		},
@@ -820,64 +1599,4920 @@ func TestFullTransliterationWithNameParsing(t *testing.T) {
 	}
 }
 
-// TestScriptPairSupport tests supported script combinations
-func TestScriptPairSupport(t *testing.T) {
+// TestNameFormat tests rendering one parsed name in each requested order
+func TestNameFormat(t *testing.T) {
+	name, _ := analyzeName("李小明", "Li Xiaoming", "chinese", "zh", true)
+
 	tests := []struct {
-		name         string
-		inputScript  string
-		outputScript string
-		expected     bool
+		format   string
+		expected string
 	}{
-		{"Latin to ASCII", "latin", "ascii", true},
-		{"Cyrillic to Latin", "cyrillic", "latin", true},
-		{"Chinese to Latin", "chinese", "latin", true},
-		{"Unsupported - Latin to Chinese", "latin", "chinese", false},
-		{"Unsupported - Unknown script", "klingon", "latin", false},
+		{nameparser.FormatGivenFirst, "Xiaoming LI"},
+		{nameparser.FormatFamilyFirst, "LI Xiaoming"},
+		{nameparser.FormatSortable, "LI, Xiaoming"},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := isSupportedScriptPair(tt.inputScript, tt.outputScript)
-			if result != tt.expected {
-				t.Errorf("isSupportedScriptPair(%q, %q) = %v, want %v",
-					tt.inputScript, tt.outputScript, result, tt.expected)
+		t.Run(tt.format, func(t *testing.T) {
+			if got := nameparser.FormatName(name, tt.format); got != tt.expected {
+				t.Errorf("FormatName(%s) = %q, want %q", tt.format, got, tt.expected)
 			}
 		})
 	}
-}
-
-// TestCaching tests that identical requests are cached
-func TestCaching(t *testing.T) {
-	req := TransliterationRequest{
-		Text:         "Hello",
-		InputScript:  "latin",
-		OutputScript: "ascii",
-	}
 
-	// First request
-	resp1, err := Transliterate(context.Background(), &req)
+	// The detected cultural order is kept regardless of the requested format
+	resp, err := ParseName(context.Background(), &ParseNameRequest{Text: "Dr John Smith", NameFormat: nameparser.FormatSortable})
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("ParseName failed: %v", err)
 	}
-
-	// Second identical request should return cached result
-	resp2, err := Transliterate(context.Background(), &req)
-	if err != nil {
-		t.Fatal(err)
+	if resp.Name.FullASCII != "SMITH, John" || resp.Name.Order != "given-first" {
+		t.Errorf("Expected sortable 'SMITH, John' with given-first order, got %q (%s)", resp.Name.FullASCII, resp.Name.Order)
 	}
 
-	// Should be the same result (cached)
-	if resp1.ID != resp2.ID {
-		t.Error("expected cached result to have same ID")
-	}
+	_, err = ParseName(context.Background(), &ParseNameRequest{Text: "John Smith", NameFormat: "surname-last"})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidNameFormat)
+}
 
-	if resp1.OutputText != resp2.OutputText {
-		t.Error("expected cached result to have same output")
+// TestParseNameEndpoint tests name parsing without transliteration
+func TestParseNameEndpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            ParseNameRequest
+		expectedFamily string
+		expectedFirst  string
+		expectedMiddle []string
+		expectedTitles []string
+		expectedGender string
+	}{
+		{
+			name:           "Western with title",
+			req:            ParseNameRequest{Text: "Dr John Smith"},
+			expectedFamily: "SMITH",
+			expectedFirst:  "John",
+			expectedTitles: []string{"Dr"},
+			expectedGender: "M",
+		},
+		{
+			name:           "Chinese romanized",
+			req:            ParseNameRequest{Text: "Li Xiaoming", Culture: "chinese", Language: "zh"},
+			expectedFamily: "LI",
+			expectedFirst:  "Xiaoming",
+		},
+		{
+			name:           "Arabic with patronymic",
+			req:            ParseNameRequest{Text: "Ahmed bin Mohammed Alali", Culture: "arabic"},
+			expectedFamily: "ALALI",
+			expectedFirst:  "Ahmed",
+			expectedMiddle: []string{"Bin", "Mohammed"},
+			expectedGender: "M",
+		},
+		{
+			name:           "Vietnamese with diacritics",
+			req:            ParseNameRequest{Text: "Nguyễn Văn Minh"},
+			expectedFamily: "NGUYEN",
+			expectedFirst:  "Minh",
+			expectedMiddle: []string{"Van"},
+			expectedGender: "M",
+		},
 	}
-}
 
-// TestAutoScriptDetection tests transliteration with auto-detection
-func TestAutoScriptDetection(t *testing.T) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ParseName(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatalf("ParseName() error = %v", err)
+			}
+			if resp.Name == nil || resp.Gender == nil {
+				t.Fatal("expected name and gender in response")
+			}
+
+			if resp.Name.Family != tt.expectedFamily {
+				t.Errorf("Family = %q, want %q", resp.Name.Family, tt.expectedFamily)
+			}
+			if resp.Name.First != tt.expectedFirst {
+				t.Errorf("First = %q, want %q", resp.Name.First, tt.expectedFirst)
+			}
+			if strings.Join(resp.Name.Middle, " ") != strings.Join(tt.expectedMiddle, " ") {
+				t.Errorf("Middle = %v, want %v", resp.Name.Middle, tt.expectedMiddle)
+			}
+			if strings.Join(resp.Name.Titles, " ") != strings.Join(tt.expectedTitles, " ") {
+				t.Errorf("Titles = %v, want %v", resp.Name.Titles, tt.expectedTitles)
+			}
+			if tt.expectedGender != "" && resp.Gender.Value != tt.expectedGender {
+				t.Errorf("Gender = %q, want %q", resp.Gender.Value, tt.expectedGender)
+			}
+
+			// The structure must match what the transliteration endpoint derives
+			scriptInfo := detection.DetectScript(tt.req.Text)
+			language := tt.req.Language
+			if language == "" {
+				language = detection.DetectLanguage(tt.req.Text, scriptInfo).Language
+			}
+			culture := tt.req.Culture
+			if culture == "" {
+				culture = determineCulture(scriptInfo.Script, language)
+			}
+			romanized, err := performTransliterationWithValidation(tt.req.Text, scriptInfo.Script, "ascii", nil)
+			if err != nil {
+				t.Fatalf("performTransliterationWithValidation error: %v", err)
+			}
+			expected, _ := analyzeName(tt.req.Text, romanized, culture, language, true)
+			if expected.FullASCII != resp.Name.FullASCII {
+				t.Errorf("FullASCII = %q, transliteration path gives %q", resp.Name.FullASCII, expected.FullASCII)
+			}
+		})
+	}
+
+	if _, err := ParseName(context.Background(), &ParseNameRequest{Text: "   "}); err == nil {
+		t.Error("expected error for empty text")
+	}
+}
+
+// TestNumberWords tests conversion of spelled-out Chinese and Russian numbers to digits
+func TestNumberWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Chinese tens", "三十五", "35"},
+		{"Chinese leading ten", "十二", "12"},
+		{"Chinese hundreds with zero", "一百零五", "105"},
+		{"Chinese ten thousands", "两万三千", "23000"},
+		{"Chinese digit-by-digit year", "二零二五年", "2025年"},
+		{"Chinese in context", "第三十五号", "第35号"},
+		{"Russian compound", "двадцать пять", "25"},
+		{"Russian thousands", "две тысячи двадцать пять", "2025"},
+		{"Russian bare thousand", "тысяча", "1000"},
+		{"Russian capitalised", "Сорок два", "42"},
+		{"Russian in context", "дом двадцать три, квартира пять", "дом 23, квартира 5"},
+		{"No number words", "Иван Петров", "Иван Петров"},
+		{"Chinese repeated place value", "三百百", "三百百"},
+		{"Chinese surname Wan", "万", "万"},
+		{"Chinese given name with qian", "千寻", "千寻"},
+		{"Chinese place value without digit", "王千", "王千"},
+		{"Chinese zero before place value", "零百", "零百"},
+		{"Russian repeated units", "пять пять", "пять пять"},
+		{"Russian units before tens", "два двадцать", "два двадцать"},
+		{"Russian teen after units", "пять двенадцать", "пять двенадцать"},
+		{"Russian rising scales", "тысяча миллион", "тысяча миллион"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := numwords.Replace(tt.input); got != tt.expected {
+				t.Errorf("numwords.Replace(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Engine option", func(t *testing.T) {
+		config := transliteration.DefaultConfig()
+		config.UseDatabase = false
+		config.NumberWords = true
+		engine := transliteration.NewEngine(config, nil)
+
+		result, err := engine.Transliterate(context.Background(), "三十五", "chinese", "latin", "zh-CN")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if result.Output != "35" {
+			t.Errorf("Expected '35' with number_words enabled, got %q", result.Output)
+		}
+
+		config.NumberWords = false
+		engine = transliteration.NewEngine(config, nil)
+		result, err = engine.Transliterate(context.Background(), "三十五", "chinese", "latin", "zh-CN")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if result.Output == "35" {
+			t.Errorf("Number words should not be converted unless the option is enabled")
+		}
+	})
+}
+
+// TestBoundarySpacing tests space insertion where romanized CJK meets Latin text
+func TestBoundarySpacing(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		fromScript string
+		toScript   string
+		mode       string
+		expected   string
+	}{
+		{"CJK then Latin", "李Smith", "latin", "latin", transliteration.BoundarySpacingSmart, "Li Smith"},
+		{"Latin then CJK", "Smith李", "latin", "latin", transliteration.BoundarySpacingSmart, "Smith Li"},
+		{"Chinese input script", "李Smith", "chinese", "ascii", transliteration.BoundarySpacingSmart, "Li Smith"},
+		{"Existing space kept single", "李 Smith", "latin", "latin", transliteration.BoundarySpacingSmart, "Li Smith"},
+		{"Same script not split", "李小明", "chinese", "latin", transliteration.BoundarySpacingSmart, "LiXiaoMing"},
+		{"Smart ignores Cyrillic", "ИванSmith", "cyrillic", "latin", transliteration.BoundarySpacingSmart, "IvanSmith"},
+		{"Always splits Cyrillic", "ИванSmith", "cyrillic", "latin", transliteration.BoundarySpacingAlways, "Ivan Smith"},
+		{"Never", "李Smith", "latin", "latin", transliteration.BoundarySpacingNever, "LiSmith"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := transliteration.DefaultConfig()
+			config.UseDatabase = false
+			config.BoundarySpacing = tt.mode
+			engine := transliteration.NewEngine(config, nil)
+
+			result, err := engine.Transliterate(context.Background(), tt.input, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) with %s spacing = %q, want %q", tt.input, tt.mode, result.Output, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Invalid mode rejected", func(t *testing.T) {
+		req := &TransliterationRequest{Text: "李Smith", OutputScript: "latin", BoundarySpacing: "sometimes"}
+		if err := validateTransliterationRequest(req); err == nil {
+			t.Error("Expected error for invalid boundary_spacing")
+		}
+	})
+}
+
+// TestMixedScriptNames tests names writing a Chinese surname in Han characters beside Latin
+// given names, each word parsed by the rules of its own script
+func TestMixedScriptNames(t *testing.T) {
+	tests := []struct {
+		input     string
+		family    string
+		first     string
+		middle    []string
+		order     string
+		fullASCII string
+	}{
+		{"李 John", "LI", "John", nil, "family-first", "LI John"},
+		{"John 李", "LI", "John", nil, "given-first", "John LI"},
+		{"王 Mary Anne", "WANG", "Anne", []string{"Mary"}, "family-first", "WANG Mary Anne"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: tt.input, OutputScript: "latin", Preview: true})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			name := resp.Name
+			if name == nil {
+				t.Fatal("Expected a parsed name")
+			}
+			if name.Family != tt.family || name.First != tt.first || !slices.Equal(name.Middle, tt.middle) {
+				t.Errorf("Expected family %q, first %q, middle %v; got %q, %q, %v", tt.family, tt.first, tt.middle, name.Family, name.First, name.Middle)
+			}
+			if name.Order != tt.order {
+				t.Errorf("Expected %s order, got %q", tt.order, name.Order)
+			}
+			if name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected full ASCII %q, got %q", tt.fullASCII, name.FullASCII)
+			}
+			if name.FamilyOriginal == "" || name.FamilyOriginal == name.Family {
+				t.Errorf("Expected the Han surname as the original family name, got %q", name.FamilyOriginal)
+			}
+		})
+	}
+
+	t.Run("Given names in Han characters", func(t *testing.T) {
+		parser := nameparser.NewParser(true, true)
+		name := parser.ParseName("李小明", "Li Xiaoming", "chinese", "zh")
+		if name.Family != "LI" || name.First != "Xiaoming" {
+			t.Errorf("Expected LI Xiaoming, got %q %q", name.Family, name.First)
+		}
+	})
+}
+
+// TestZeroWidthJoiners tests that joiners are kept where they shape letters and never
+// become unmapped characters
+func TestZeroWidthJoiners(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		fromScript string
+		toScript   string
+		expected   string
+	}{
+		{"Persian morpheme break", "نامه\u200cها", "arabic", "ascii", "namh-ha"},
+		{"Indic conjunct kept as written", "क्\u200dष", "latin", "latin", "क्\u200dष"},
+		{"Indic conjunct in ascii", "क्\u200dष", "latin", "ascii", "kss"},
+		{"Stray joiner between Latin letters", "John\u200cSmith", "latin", "ascii", "JohnSmith"},
+		{"Joiner ending the text", "Smith\u200d", "latin", "ascii", "Smith"},
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSchemaDocument tests that API payloads validate against the reflected schema
+func TestSchemaDocument(t *testing.T) {
+	doc, err := GetSchema(context.Background())
+	if err != nil {
+		t.Fatalf("GetSchema failed: %v", err)
+	}
+
+	requestSchema := doc.Definitions["TransliterationRequest"]
+	if requestSchema == nil {
+		t.Fatal("TransliterationRequest definition missing")
+	}
+	for _, field := range []string{"number_words", "boundary_spacing"} {
+		if requestSchema.Properties[field] == nil {
+			t.Errorf("TransliterationRequest schema missing optional field %q", field)
+		}
+	}
+	if strings.Join(requestSchema.Required, ",") != "text,output_script" {
+		t.Errorf("Required = %v, want [text output_script]", requestSchema.Required)
+	}
+	if !containsString(requestSchema.Properties["output_script"].Enum, "cyrillic") {
+		t.Errorf("output_script enum should list supported scripts, got %v", requestSchema.Properties["output_script"].Enum)
+	}
+
+	confidence := 0.92
+	locale := "zh-CN"
+	name, genderInference := analyzeName("李小明", "Li Xiaoming", "chinese", "zh-CN", true)
+	sample := &TransliterationResponse{
+		ID:               "123e4567-e89b-12d3-a456-426614174000",
+		InputText:        "李小明",
+		OutputText:       "Li Xiaoming",
+		InputScript:      "chinese",
+		OutputScript:     "latin",
+		InputLocale:      &locale,
+		ConfidenceScore:  &confidence,
+		AlternativeForms: []string{"Li Xiaomin"},
+		Notes:            []string{"Script detected: chinese (0.95 confidence)"},
+		Name:             name,
+		Gender:           genderInference,
+	}
+
+	t.Run("Valid response", func(t *testing.T) {
+		if problems := validateAgainstSchema(t, doc, "TransliterationResponse", sample); len(problems) > 0 {
+			t.Errorf("Sample response does not match schema: %v", problems)
+		}
+	})
+
+	t.Run("Null confidence allowed", func(t *testing.T) {
+		nullable := *sample
+		nullable.ConfidenceScore = nil
+		if problems := validateAgainstSchema(t, doc, "TransliterationResponse", &nullable); len(problems) > 0 {
+			t.Errorf("Response with null confidence does not match schema: %v", problems)
+		}
+	})
+
+	t.Run("Enums name existing fields", func(t *testing.T) {
+		for key := range schemaEnums() {
+			definition, field, _ := strings.Cut(key, ".")
+			if _, ok := schemaTypes[definition]; !ok {
+				t.Errorf("Enum %q names unknown type %q", key, definition)
+				continue
+			}
+			property := doc.Definitions[definition].Properties[field]
+			if property == nil {
+				t.Errorf("Enum %q names no JSON field of %s", key, definition)
+				continue
+			}
+			if len(property.Enum) == 0 && (property.Items == nil || len(property.Items.Enum) == 0) {
+				t.Errorf("Enum %q was not applied to %s.%s", key, definition, field)
+			}
+		}
+	})
+
+	t.Run("Invalid enum rejected", func(t *testing.T) {
+		invalid := *sample
+		invalid.Gender = &GenderInference{Value: "Q", Source: "unknown"}
+		if problems := validateAgainstSchema(t, doc, "TransliterationResponse", &invalid); len(problems) == 0 {
+			t.Error("Expected schema violation for unknown gender value")
+		}
+	})
+}
+
+// validateAgainstSchema marshals value to JSON and checks it against a schema definition
+func validateAgainstSchema(t *testing.T, doc *SchemaDocument, definition string, value any) []string {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	return checkSchema(doc, &JSONSchema{Ref: "#/definitions/" + definition}, decoded, "$")
+}
+
+// checkSchema is a minimal JSON Schema validator covering the keywords the API schema uses
+func checkSchema(doc *SchemaDocument, schema *JSONSchema, value any, path string) []string {
+	if schema.Ref != "" {
+		resolved := doc.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		if resolved == nil {
+			return []string{fmt.Sprintf("%s: unresolved $ref %s", path, schema.Ref)}
+		}
+		return checkSchema(doc, resolved, value, path)
+	}
+
+	var allowed []string
+	switch typ := schema.Type.(type) {
+	case string:
+		allowed = []string{typ}
+	case []string:
+		allowed = typ
+	}
+
+	actual := "null"
+	switch v := value.(type) {
+	case bool:
+		actual = "boolean"
+	case float64:
+		actual = "number"
+		if v == float64(int64(v)) && containsString(allowed, "integer") {
+			actual = "integer"
+		}
+	case string:
+		actual = "string"
+	case []any:
+		actual = "array"
+	case map[string]any:
+		actual = "object"
+	}
+	if len(allowed) > 0 && !containsString(allowed, actual) {
+		return []string{fmt.Sprintf("%s: type %s, want %v", path, actual, allowed)}
+	}
+
+	var problems []string
+	if str, ok := value.(string); ok && len(schema.Enum) > 0 && !containsString(schema.Enum, str) {
+		problems = append(problems, fmt.Sprintf("%s: %q not in enum %v", path, str, schema.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, required := range schema.Required {
+			if _, ok := v[required]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required %q", path, required))
+			}
+		}
+		for key, child := range v {
+			property := schema.Properties[key]
+			if property == nil {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					problems = append(problems, fmt.Sprintf("%s: unexpected property %q", path, key))
+				}
+				continue
+			}
+			problems = append(problems, checkSchema(doc, property, child, path+"."+key)...)
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range v {
+				problems = append(problems, checkSchema(doc, schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return problems
+}
+
+// containsString reports whether values contains target
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TestBuckwalterStandard tests the Buckwalter Arabic romanization standard
+func TestBuckwalterStandard(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain letters", "محمد", "mHmd"},
+		{"Diacritics and shadda", "مُحَمَّد", "muHama~d"},
+		{"Ta marbuta", "فاطمة", "fATmp"},
+		{"Hamza on alif", "أحمد", ">Hmd"},
+		{"Hamza below alif", "إسلام", "<slAm"},
+		{"Hamza on waw", "سؤال", "s&Al"},
+		{"Hamza on ya", "مسئول", "ms}wl"},
+		{"Madda and lone hamza", "آمن شيء", "|mn $y'"},
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	config.Standard = transliteration.StandardBuckwalter
+	buckwalter := transliteration.NewEngine(config, nil)
+
+	defaultConfig := transliteration.DefaultConfig()
+	defaultConfig.UseDatabase = false
+	phonetic := transliteration.NewEngine(defaultConfig, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := buckwalter.Transliterate(context.Background(), tt.input, "arabic", "ascii", "ar")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Buckwalter(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+
+			if reversed := transliteration.BuckwalterToArabic(result.Output); reversed != tt.input {
+				t.Errorf("BuckwalterToArabic(%q) = %q, want %q", result.Output, reversed, tt.input)
+			}
+
+			defaultResult, err := phonetic.Transliterate(context.Background(), tt.input, "arabic", "ascii", "ar")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if defaultResult.Output == result.Output {
+				t.Errorf("Buckwalter output %q should differ from the default phonetic output", result.Output)
+			}
+		})
+	}
+
+	t.Run("Standard validated against input script", func(t *testing.T) {
+		req := &TransliterationRequest{Text: "Привет", InputScript: "cyrillic", OutputScript: "latin", Standard: transliteration.StandardBuckwalter}
+		if err := validateTransliterationRequest(req); err == nil {
+			t.Error("Expected error for Buckwalter on Cyrillic input")
+		}
+
+		req = &TransliterationRequest{Text: "محمد", OutputScript: "latin", Standard: "klingon"}
+		if err := validateTransliterationRequest(req); err == nil {
+			t.Error("Expected error for unknown standard")
+		}
+	})
+}
+
+// TestGreekStandards tests the selectable Greek romanization standards
+func TestGreekStandards(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		standard string
+		output   string
+		expected string
+	}{
+		{"Default Giorgos", "Γιώργος", "", "latin", "Giorgos"},
+		{"ELOT 743 Giorgos", "Γιώργος", transliteration.StandardELOT743, "latin", "Giorgos"},
+		{"Classical Giorgos", "Γιώργος", transliteration.StandardClassical, "latin", "Giōrgos"},
+		{"Classical macrons stripped for ASCII", "Γιώργος", transliteration.StandardClassical, "ascii", "Giorgos"},
+		{"Double gamma", "άγγελος", "", "latin", "angelos"},
+		{"ELOT 743 double gamma", "άγγελος", transliteration.StandardELOT743, "latin", "angelos"},
+		{"Classical double gamma", "άγγελος", transliteration.StandardClassical, "latin", "angelos"},
+		{"Capital double gamma", "ΑΓΓΕΛΟΣ", transliteration.StandardELOT743, "ascii", "ANGELOS"},
+		{"Gamma before xi", "Σφίγξ", transliteration.StandardELOT743, "latin", "Sfinx"},
+		{"ELOT 743 gamma kappa", "Αγκυρα", transliteration.StandardELOT743, "latin", "Agkyra"},
+		{"Classical gamma kappa", "Αγκυρα", transliteration.StandardClassical, "latin", "Ankyra"},
+		{"ELOT 743 eta", "Ομηρος", transliteration.StandardELOT743, "latin", "Omiros"},
+		{"Classical eta", "Ομηρος", transliteration.StandardClassical, "latin", "Omēros"},
+		{"ELOT 743 ou", "Αύγουστος", transliteration.StandardELOT743, "latin", "Avgoustos"},
+		{"Classical au", "Αύγουστος", transliteration.StandardClassical, "latin", "Augoustos"},
+		{"ELOT 743 ef before voiceless", "Ευθύμιος", transliteration.StandardELOT743, "latin", "Efthymios"},
+		{"ELOT 743 ev before vowel", "Ευαγγέλιο", transliteration.StandardELOT743, "latin", "Evangelio"},
+		{"Classical eu", "Ζεύς", transliteration.StandardClassical, "latin", "Zeus"},
+		{"Diaeresis keeps vowels apart", "Κοϊ", transliteration.StandardELOT743, "latin", "Koi"},
+		{"ELOT 743 beta and phi", "Βαρβάρα Φωτίου", transliteration.StandardELOT743, "latin", "Varvara Fotiou"},
+		{"Classical beta and phi", "Βαρβάρα Φωτίου", transliteration.StandardClassical, "latin", "Barbara Phōtiou"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := transliteration.DefaultConfig()
+			config.UseDatabase = false
+			config.Standard = tt.standard
+			engine := transliteration.NewEngine(config, nil)
+
+			result, err := engine.Transliterate(context.Background(), tt.input, "greek", tt.output, "el")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q, standard %q) = %q, want %q", tt.input, tt.standard, result.Output, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Greek standards validated against input script", func(t *testing.T) {
+		req := &TransliterationRequest{Text: "Γιώργος", InputScript: "greek", OutputScript: "latin", Standard: transliteration.StandardELOT743}
+		if err := validateTransliterationRequest(req); err != nil {
+			t.Errorf("Unexpected error for ELOT 743 on Greek input: %v", err)
+		}
+
+		req = &TransliterationRequest{Text: "محمد", InputScript: "arabic", OutputScript: "latin", Standard: transliteration.StandardClassical}
+		assertErrorReason(t, validateTransliterationRequest(req), errs.InvalidArgument, ReasonStandardScriptMismatch)
+	})
+}
+
+// TestInvalidCodePoints tests reject and strip handling of private-use and unassigned code points
+func TestInvalidCodePoints(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		policy         string
+		expectError    bool
+		expectedText   string
+		expectWarnings bool
+	}{
+		{"Reject PUA glyph", "Ivan\uE000 Petrov", codePointsReject, true, "", false},
+		{"Reject supplementary PUA", "李\U000F0001小明", codePointsReject, true, "", false},
+		{"Reject unassigned", "Ivan\U000E0080", codePointsReject, true, "", false},
+		{"Reject clean text", "Иван Петров", codePointsReject, false, "Иван Петров", false},
+		{"Strip PUA glyph", "Ivan\uE000 Petrov\uF8FF", codePointsStrip, false, "Ivan Petrov", true},
+		{"Strip unassigned", "李\U000E0080小明", codePointsStrip, false, "李小明", true},
+		{"Strip only PUA", "\uE000\uE001", codePointsStrip, true, "", false},
+		{"Allow by default", "Ivan\uE000", "", false, "Ivan\uE000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &TransliterationRequest{Text: tt.text, OutputScript: "latin", InvalidCodePoints: tt.policy}
+			err := validateTransliterationRequest(req)
+			if err == nil {
+				var warnings []string
+				var text string
+				text, warnings, err = applyCodePointPolicy(req.Text, req.InvalidCodePoints)
+				if err == nil {
+					if text != tt.expectedText {
+						t.Errorf("Text = %q, want %q", text, tt.expectedText)
+					}
+					if (len(warnings) > 0) != tt.expectWarnings {
+						t.Errorf("Warnings = %v, expect warnings: %v", warnings, tt.expectWarnings)
+					}
+				}
+			}
+
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			var apiErr *errs.Error
+			if tt.expectError && tt.policy == codePointsReject && errors.As(err, &apiErr) && !strings.Contains(apiErr.Message, "U+") {
+				t.Errorf("Reject error should name the offending code points, got %q", apiErr.Message)
+			}
+		})
+	}
+}
+
+// TestGenderValues tests that an explicit gender-neutral signal is distinguished from no signal
+func TestGenderValues(t *testing.T) {
+	parser := nameparser.NewParser(true, true)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Gender-neutral title", "Mx Taylor", gender.NonBinary},
+		{"Male title", "Mr Smith", gender.Male},
+		{"Female title", "Mrs Smith", gender.Female},
+		{"Neutral professional title", "Dr Smith", gender.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := parser.ParseName(tt.input, tt.input, "western", "en")
+			if len(name.Titles) != 1 {
+				t.Fatalf("Expected one title in %q, got %v", tt.input, name.Titles)
+			}
+			if inferred := gender.GetGenderFromTitle(name.Titles[0]); inferred.Value != tt.expected {
+				t.Errorf("GetGenderFromTitle(%q) = %q, want %q", name.Titles[0], inferred.Value, tt.expected)
+			}
+		})
+	}
+
+	t.Run("No signal", func(t *testing.T) {
+		_, inferred := analyzeName("Taylor", "Taylor", "western", "en", true)
+		if inferred.Value != gender.Unknown || inferred.Confidence > 0.2 {
+			t.Errorf("Expected unknown gender with low confidence, got %q (%.2f)", inferred.Value, inferred.Confidence)
+		}
+		if fallback := inferGender("Taylor", "Taylor", "latin"); fallback.Value != gender.Unknown {
+			t.Errorf("inferGender = %q, want %q", fallback.Value, gender.Unknown)
+		}
+	})
+}
+
+// TestTitleGender tests that gendered titles drive gender inference over the name itself
+func TestTitleGender(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		culture    string
+		language   string
+		expected   string
+		conflicted bool
+	}{
+		{"Mrs with unknown name", "Mrs. Jane Smith", "western", "en", gender.Female, false},
+		{"Mr with unknown name", "Mr John Smith", "western", "en", gender.Male, false},
+		{"Mx", "Mx Taylor", "western", "en", gender.NonBinary, false},
+		{"Title agrees with marker", "Mrs. Nguyễn Thị Mai", "vietnamese", "vi", gender.Female, false},
+		{"Title conflicts with name", "Mr. Maria", "western", "en", gender.Male, true},
+		{"Title conflicts with marker", "Mr. Nguyễn Thị Mai", "vietnamese", "vi", gender.Male, true},
+		{"Neutral title", "Dr. Jane Smith", "western", "en", gender.Unknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			romanized, err := textnorm.ToASCII(tt.input)
+			if err != nil {
+				t.Fatalf("ToASCII failed: %v", err)
+			}
+
+			_, inferred := analyzeName(tt.input, romanized, tt.culture, tt.language, true)
+			if inferred.Value != tt.expected {
+				t.Errorf("Gender = %q, want %q (%s)", inferred.Value, tt.expected, inferred.Reason)
+			}
+			if tt.expected != gender.Unknown && inferred.Confidence < 0.95 {
+				t.Errorf("Expected title-level confidence, got %.2f", inferred.Confidence)
+			}
+			if conflicted := strings.Contains(inferred.Reason, "conflicts"); conflicted != tt.conflicted {
+				t.Errorf("Reason %q: conflict recorded = %v, want %v", inferred.Reason, conflicted, tt.conflicted)
+			}
+		})
+	}
+}
+
+// TestNativeHonorifics tests CJK honorifics recognised before transliteration
+func TestNativeHonorifics(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		culture        string
+		language       string
+		expectedName   string
+		expectedTitle  string
+		expectedGender string
+	}{
+		{"Chinese Ms", "王女士", "chinese", "zh-CN", "王", "Ms", "F"},
+		{"Chinese Mr", "李先生", "chinese", "zh-CN", "李", "Mr", "M"},
+		{"Chinese Miss", "陈小姐", "chinese", "zh-CN", "陈", "Miss", "F"},
+		{"Japanese san is neutral", "やまもとさん", "japanese", "ja", "やまもと", "", "U"},
+		{"Japanese sensei is neutral", "やまもと先生", "japanese", "ja", "やまもと", "Sensei", "U"},
+	}
+
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nameText, honorific := nameparser.ExtractNativeHonorific(tt.input)
+			if honorific == nil {
+				t.Fatalf("Expected honorific in %q", tt.input)
+			}
+			if nameText != tt.expectedName {
+				t.Errorf("Name text = %q, want %q", nameText, tt.expectedName)
+			}
+			if honorific.Title != tt.expectedTitle {
+				t.Errorf("Title = %q, want %q", honorific.Title, tt.expectedTitle)
+			}
+
+			result, err := engine.Transliterate(context.Background(), nameText, tt.culture, "latin", tt.language)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+
+			name, genderInference := analyzeName(tt.input, result.Output, tt.culture, tt.language, true)
+			if genderInference.Value != tt.expectedGender {
+				t.Errorf("Gender = %q, want %q (%s)", genderInference.Value, tt.expectedGender, genderInference.Reason)
+			}
+			if tt.expectedTitle != "" && !containsString(name.Titles, tt.expectedTitle) {
+				t.Errorf("Titles = %v, want to include %q", name.Titles, tt.expectedTitle)
+			}
+			if tt.expectedTitle == "" && len(name.Titles) > 0 {
+				t.Errorf("Titles = %v, want none for a politeness-only honorific", name.Titles)
+			}
+		})
+	}
+
+	t.Run("Sama follows kanji names", func(t *testing.T) {
+		for _, tt := range []struct{ input, name, output string }{
+			{"田中様", "田中", "Tanaka"},
+			{"山田様", "山田", "Yamada"},
+		} {
+			text, honorific := nameparser.ExtractNativeHonorific(tt.input)
+			if honorific == nil || honorific.Native != "様" || text != tt.name {
+				t.Errorf("ExtractNativeHonorific(%q) = %q, %v, want %s with 様", tt.input, text, honorific, tt.name)
+			}
+
+			result, _, err := transliterateName(context.Background(), engine, tt.input, "japanese", "latin", "ja")
+			if err != nil {
+				t.Fatalf("transliterateName(%q) failed: %v", tt.input, err)
+			}
+			if result.Output != tt.output {
+				t.Errorf("transliterateName(%q) = %q, want %q", tt.input, result.Output, tt.output)
+			}
+
+			name, _ := analyzeName(tt.input, result.Output, "japanese", "ja", false)
+			if name.FullASCII != tt.output || len(name.Titles) > 0 {
+				t.Errorf("analyzeName(%q) = %+v, want %s without titles", tt.input, name, tt.output)
+			}
+		}
+	})
+
+	t.Run("Kanji honorifics need kana", func(t *testing.T) {
+		if text, honorific := nameparser.ExtractNativeHonorific("たなか君"); honorific == nil || text != "たなか" {
+			t.Errorf("ExtractNativeHonorific(\"たなか君\") = %q, %v, want たなか with 君", text, honorific)
+		}
+		for _, name := range []string{"张丽君", "王淑君"} {
+			if text, honorific := nameparser.ExtractNativeHonorific(name); honorific != nil || text != name {
+				t.Errorf("ExtractNativeHonorific(%q) = %q, %v, want unchanged", name, text, honorific)
+			}
+			if inferred := nameparser.FindHonorific(name); inferred != nil {
+				t.Errorf("FindHonorific(%q) = %+v, want none", name, inferred)
+			}
+		}
+	})
+
+	t.Run("Bare honorific is not stripped", func(t *testing.T) {
+		if text, honorific := nameparser.ExtractNativeHonorific("先生"); honorific != nil || text != "先生" {
+			t.Errorf("ExtractNativeHonorific(\"先生\") = %q, %v, want unchanged", text, honorific)
+		}
+	})
+}
+
+// TestSearchTokens tests index tokens assembled from parsed multi-part names
+func TestSearchTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		original  string
+		romanized string
+		culture   string
+		language  string
+		expected  []string
+	}{
+		{"Vietnamese three-part name", "Nguyễn Văn Minh", "Nguyen Van Minh", "vietnamese", "vi", []string{"minh", "van", "nguyen", "mn", "nkyn"}},
+		{"Spanish compound surname", "José María García-López", "José María García-López", "western", "es", []string{"jose", "maria", "garcia", "lopez", "js", "krx", "lps"}},
+		{"Title and suffix excluded", "Dr. Martin Luther King Jr.", "Dr Martin Luther King Jr.", "western", "en", []string{"martin", "luther", "king", "mrtn", "knk"}},
+		{"Particle kept with family name", "Ludwig van Beethoven", "Ludwig van Beethoven", "western", "de", []string{"ludwig", "van", "beethoven", "ltwk", "fn", "b0fn"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, _ := analyzeName(tt.original, tt.romanized, tt.culture, tt.language, true)
+			tokens := buildSearchTokens(name, tt.romanized, buildPhoneticKeys(name, "latin"))
+			if strings.Join(tokens, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("SearchTokens = %v, want %v", tokens, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Includes phonetic keys", func(t *testing.T) {
+		name, _ := analyzeName("Nguyễn Văn Minh", "Nguyen Van Minh", "vietnamese", "vi", false)
+		keys := buildPhoneticKeys(name, "latin")
+		if keys == nil || keys.Family == "" {
+			t.Fatalf("Expected phonetic keys for %+v", name)
+		}
+		tokens := buildSearchTokens(name, "Nguyen Van Minh", keys)
+		if !slices.Contains(tokens, strings.ToLower(keys.Family)) {
+			t.Errorf("SearchTokens = %v, want to include the family name key %q lowercased", tokens, keys.Family)
+		}
+	})
+
+	t.Run("Falls back to output text", func(t *testing.T) {
+		tokens := buildSearchTokens(nil, "Privet Mir", nil)
+		if strings.Join(tokens, " ") != "privet mir" {
+			t.Errorf("SearchTokens = %v, want [privet mir]", tokens)
+		}
+	})
+}
+
+// TestLargeInput tests document-sized input limits and streaming conversion
+func TestLargeInput(t *testing.T) {
+	// 100k characters of Cyrillic prose
+	text := string([]rune(strings.Repeat("Привет мир, как дела у тебя сегодня? ", 2800))[:100000])
+
+	t.Run("Validation", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			req            *TransliterationRequest
+			expectedReason string
+		}{
+			{
+				name:           "Default limit",
+				req:            &TransliterationRequest{Text: text, OutputScript: "latin"},
+				expectedReason: ReasonTextTooLong,
+			},
+			{
+				name: "Raised limit",
+				req:  &TransliterationRequest{Text: text, OutputScript: "latin", MaxLength: 100000},
+			},
+			{
+				name:           "Raised limit still too small",
+				req:            &TransliterationRequest{Text: text, OutputScript: "latin", MaxLength: 50000},
+				expectedReason: ReasonTextTooLong,
+			},
+			{
+				name:           "Above hard cap",
+				req:            &TransliterationRequest{Text: "Привет", OutputScript: "latin", MaxLength: maxTextLengthCap + 1},
+				expectedReason: ReasonInvalidMaxLength,
+			},
+			{
+				name:           "Negative limit",
+				req:            &TransliterationRequest{Text: "Привет", OutputScript: "latin", MaxLength: -1},
+				expectedReason: ReasonInvalidMaxLength,
+			},
+			{
+				// The default limit counts characters, not bytes
+				name: "Multi-byte text under default limit",
+				req:  &TransliterationRequest{Text: string([]rune(text)[:defaultMaxTextLength]), OutputScript: "latin"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := validateTransliterationRequest(tt.req)
+				if tt.expectedReason == "" {
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					return
+				}
+				assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+			})
+		}
+	})
+
+	t.Run("Transliterate", func(t *testing.T) {
+		config := transliteration.DefaultConfig()
+		config.UseDatabase = false
+		engine := transliteration.NewEngine(config, nil)
+
+		result, err := engine.Transliterate(context.Background(), text, "cyrillic", "latin", "ru")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+
+		if !strings.HasPrefix(result.Output, "Privet mir, kak dela u tebya segodnya? ") {
+			t.Errorf("Unexpected output prefix: %q", result.Output[:60])
+		}
+		for _, r := range result.Output {
+			if r > unicode.MaxASCII {
+				t.Fatalf("Output contains non-ASCII character %q", r)
+			}
+		}
+		if result.Confidence <= 0 || result.Confidence > 1 {
+			t.Errorf("Confidence out of range: %f", result.Confidence)
+		}
+		if len(result.Notes) > 10 {
+			t.Errorf("Expected deduplicated notes, got %d", len(result.Notes))
+		}
+
+		// Streaming produces the same output without building it in the result
+		var streamed strings.Builder
+		streamResult, err := engine.TransliterateTo(context.Background(), &streamed, text, "cyrillic", "latin", "ru")
+		if err != nil {
+			t.Fatalf("TransliterateTo failed: %v", err)
+		}
+		if streamed.String() != result.Output {
+			t.Error("Streamed output differs from Transliterate output")
+		}
+		if streamResult.Confidence != result.Confidence {
+			t.Errorf("Streamed confidence %f differs from %f", streamResult.Confidence, result.Confidence)
+		}
+	})
+}
+
+// BenchmarkTransliterateLargeCyrillic measures engine throughput on document-sized input
+func BenchmarkTransliterateLargeCyrillic(b *testing.B) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+	text := strings.Repeat("Привет мир, как дела у тебя сегодня? ", 3200)
+
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.Transliterate(context.Background(), text, "cyrillic", "latin", "ru"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestPrefetchedMappings tests that database mappings are loaded in one query per conversion
+func TestPrefetchedMappings(t *testing.T) {
+	engine := transliteration.NewEngine(transliteration.DefaultConfig(), db)
+	text := strings.Repeat("Съешь же ещё этих мягких французских булок, да выпей чаю. ", 500)
+
+	t.Run("Single query for long text", func(t *testing.T) {
+		result, err := engine.Transliterate(context.Background(), text, "cyrillic", "latin", "ru")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if result.Queries != 1 {
+			t.Errorf("Expected 1 database query for the whole text, got %d", result.Queries)
+		}
+	})
+
+	t.Run("No query without characters to look up", func(t *testing.T) {
+		result, err := engine.Transliterate(context.Background(), "hello world 123", "latin", "ascii", "")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if result.Queries != 0 {
+			t.Errorf("Expected no database queries for ASCII text, got %d", result.Queries)
+		}
+	})
+
+	t.Run("Cancelled request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := engine.Transliterate(ctx, text, "cyrillic", "latin", "ru"); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+// BenchmarkTransliterateWithDatabase measures a conversion that looks its mappings up in the database
+func BenchmarkTransliterateWithDatabase(b *testing.B) {
+	engine := transliteration.NewEngine(transliteration.DefaultConfig(), db)
+	text := strings.Repeat("Привет мир, как дела у тебя сегодня? ", 3200)
+
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.Transliterate(context.Background(), text, "cyrillic", "latin", "ru"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCyrillicRuneMapping measures per-rune map lookups without the engine's memo,
+// which only stay allocation-free while the character maps are package-level
+func BenchmarkCyrillicRuneMapping(b *testing.B) {
+	text := strings.Repeat("Привет мир, как дела у тебя сегодня? ", 3200)
+
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, r := range text {
+			transliterateCyrillicToLatin(r)
+		}
+	}
+}
+
+// TestSampledScriptDetection tests that detection stopping at a sample of a large input
+// classifies short inputs exactly as counting every letter does
+func TestSampledScriptDetection(t *testing.T) {
+	t.Run("Short inputs unchanged", func(t *testing.T) {
+		for _, text := range []string{
+			"", "12345", "A1-234-567-890", "Li", "Hello world", "Hello мир", "Привет мир",
+			"你好世界", "مرحبا بالعالم", "Müller", "Nguyễn Văn Minh", "5 мая 2024",
+			strings.Repeat("Привет мир ", 300) + strings.Repeat("Hello ", 400),
+		} {
+			sampled, full := detection.DetectScript(text), detection.DetectScriptFull(text)
+			if sampled.Script != full.Script || sampled.Confidence != full.Confidence || !maps.Equal(sampled.Details, full.Details) {
+				t.Errorf("DetectScript(%.20q) = %+v, counting every letter gives %+v", text, sampled, full)
+			}
+		}
+	})
+
+	t.Run("Large single-script input", func(t *testing.T) {
+		text := strings.Repeat("Привет мир, как дела у тебя сегодня? ", 30000)
+		sampled, full := detection.DetectScript(text), detection.DetectScriptFull(text)
+		if sampled.Script != "cyrillic" || sampled.Confidence != full.Confidence {
+			t.Errorf("Expected cyrillic at confidence %v, got %+v", full.Confidence, sampled)
+		}
+		if sampled.Details["cyrillic"] >= full.Details["cyrillic"] {
+			t.Errorf("Expected detection to stop at a sample, counted %d of %d letters", sampled.Details["cyrillic"], full.Details["cyrillic"])
+		}
+	})
+
+	t.Run("Large mixed input counted to the end", func(t *testing.T) {
+		text := strings.Repeat("Привет мир Hello world ", 20000)
+		sampled, full := detection.DetectScript(text), detection.DetectScriptFull(text)
+		if !maps.Equal(sampled.Details, full.Details) {
+			t.Errorf("Expected every letter of mixed text counted, got %v want %v", sampled.Details, full.Details)
+		}
+	})
+}
+
+// BenchmarkDetectScript compares detection of a 1MB document stopping at a sample with
+// counting every letter
+func BenchmarkDetectScript(b *testing.B) {
+	sentence := "Привет мир, как дела у тебя сегодня? "
+	text := strings.Repeat(sentence, (1<<20)/len(sentence))
+
+	for _, bm := range []struct {
+		name   string
+		detect func(string) detection.ScriptInfo
+	}{
+		{"Sampled", detection.DetectScript},
+		{"Full", detection.DetectScriptFull},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bm.detect(text)
+			}
+		})
+	}
+}
+
+// TestDiffCorrection tests aligning a suggested output to the source characters
+func TestDiffCorrection(t *testing.T) {
+	segments := func(pairs ...string) []transliteration.Segment {
+		result := make([]transliteration.Segment, 0, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			result = append(result, transliteration.Segment{Source: pairs[i], Script: "cyrillic", Output: pairs[i+1]})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		segments   []transliteration.Segment
+		suggested  string
+		expectOK   bool
+		expectChar string
+		expectTo   string
+	}{
+		{
+			name:       "Single character changed",
+			segments:   segments("П", "P", "р", "r", "и", "i", "в", "v", "е", "e", "т", "t"),
+			suggested:  "Pryvet",
+			expectOK:   true,
+			expectChar: "и",
+			expectTo:   "y",
+		},
+		{
+			name:       "Longer replacement",
+			segments:   segments("х", "kh", "л", "l", "ѣ", "ѣ", "б", "b"),
+			suggested:  "khlieb",
+			expectOK:   true,
+			expectChar: "ѣ",
+			expectTo:   "ie",
+		},
+		{
+			name:       "Empty output replaced",
+			segments:   segments("с", "s", "ъ", "", "е", "e", "л", "l"),
+			suggested:  "s'el",
+			expectOK:   true,
+			expectChar: "ъ",
+			expectTo:   "'",
+		},
+		{
+			name:      "Unchanged",
+			segments:  segments("м", "m", "и", "i", "р", "r"),
+			suggested: "mir",
+		},
+		{
+			name:      "Several characters changed",
+			segments:  segments("П", "P", "р", "r", "и", "i", "в", "v", "е", "e", "т", "t"),
+			suggested: "Pryvit",
+		},
+		{
+			name:      "Repeated character changed once",
+			segments:  segments("А", "A", "н", "n", "н", "n", "а", "a"),
+			suggested: "Anya",
+		},
+		{
+			name:      "Deletion",
+			segments:  segments("м", "m", "и", "i", "р", "r"),
+			suggested: "mr",
+		},
+		{
+			name: "Character read from its neighbours",
+			segments: []transliteration.Segment{
+				{Source: "а", Script: "cyrillic", Output: "a"},
+				{Source: "е", Script: "cyrillic", Output: "ye", Contextual: true},
+				{Source: "в", Script: "cyrillic", Output: "v"},
+			},
+			suggested: "aev",
+		},
+		{
+			name:      "Several source characters",
+			segments:  segments("к", "k", "ья", "ya"),
+			suggested: "kia",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, ok := learning.DiffCorrection(tt.segments, tt.suggested)
+			if ok != tt.expectOK {
+				t.Fatalf("Expected ok=%v, got %v (%+v)", tt.expectOK, ok, mapping)
+			}
+			if !ok {
+				return
+			}
+			if mapping.Source != tt.expectChar || mapping.Target != tt.expectTo {
+				t.Errorf("Expected %s -> %s, got %s -> %s", tt.expectChar, tt.expectTo, mapping.Source, mapping.Target)
+			}
+			if mapping.Script != "cyrillic" {
+				t.Errorf("Expected script cyrillic, got %s", mapping.Script)
+			}
+		})
+	}
+}
+
+// TestSegmentsMatchOutput tests that segments align with the output of the same conversion,
+// including characters read with their neighbours
+func TestSegmentsMatchOutput(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name        string
+		text        string
+		inputScript string
+		locale      string
+		expected    string
+		contextual  string
+	}{
+		{"Sokuon", "がっこう", "japanese", "ja", "gakkou", "っ"},
+		{"Iotated е", "Алексеева", "cyrillic", "ru", "Alekseyeva", "е"},
+		{"Armenian word-initial ե", "Երևան", "armenian", "hy", "Yerevan", "Ե"},
+		{"Hebrew vav as a vowel", "שלום", "hebrew", "he", "shlom", "ו"},
+		{"Chinese word", "银行", "chinese", "zh-CN", "Yinhang", "银行"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.text, tt.inputScript, "latin", tt.locale)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			segments, err := engine.Segments(context.Background(), tt.text, tt.inputScript, "latin", tt.locale)
+			if err != nil {
+				t.Fatalf("Segments failed: %v", err)
+			}
+
+			var joined strings.Builder
+			contextual := false
+			for _, segment := range segments {
+				joined.WriteString(segment.Output)
+				if segment.Source == tt.contextual {
+					contextual = segment.Contextual
+				}
+			}
+			if result.Output != tt.expected || joined.String() != result.Output {
+				t.Errorf("Expected %q from both, got output %q and segments %q", tt.expected, result.Output, joined.String())
+			}
+			if !contextual {
+				t.Errorf("Expected %s to be marked contextual, got %+v", tt.contextual, segments)
+			}
+		})
+	}
+}
+
+// TestFeedbackClient tests that clients are told apart by the peer address the gateway
+// forwarded, not by hops the caller wrote
+func TestFeedbackClient(t *testing.T) {
+	headers := func(pairs ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i < len(pairs); i += 2 {
+			h.Add(pairs[i], pairs[i+1])
+		}
+		return h
+	}
+
+	peer := clientFromHeaders(headers("X-Forwarded-For", "203.0.113.7"))
+	if peer == "" {
+		t.Fatal("Expected a client for a forwarded peer address")
+	}
+	if strings.Contains(peer, "203.0.113.7") {
+		t.Errorf("Expected the address to be hashed, got %q", peer)
+	}
+
+	// Hops before the peer address are the caller's own and can't make it another client
+	spoofed := []http.Header{
+		headers("X-Forwarded-For", "198.51.100.1, 203.0.113.7"),
+		headers("X-Forwarded-For", "192.0.2.99, 198.51.100.2,203.0.113.7"),
+		headers("X-Forwarded-For", "198.51.100.3", "X-Forwarded-For", "203.0.113.7"),
+		headers("X-Forwarded-For", "203.0.113.7", "X-Real-IP", "198.51.100.4"),
+	}
+	for _, h := range spoofed {
+		if got := clientFromHeaders(h); got != peer {
+			t.Errorf("Expected %v to count as the peer's client %q, got %q", h, peer, got)
+		}
+	}
+
+	if other := clientFromHeaders(headers("X-Forwarded-For", "198.51.100.1, 203.0.113.8")); other == peer {
+		t.Error("Expected another peer address to be another client")
+	}
+
+	// Without a forwarded peer address the client is unknown, whatever the caller claims
+	for _, h := range []http.Header{headers(), headers("X-Real-IP", "203.0.113.7"), headers("X-Forwarded-For", " ")} {
+		if got := clientFromHeaders(h); got != "" {
+			t.Errorf("Expected no client for %v, got %q", h, got)
+		}
+	}
+}
+
+// TestFeedbackLearning tests that corroborated corrections change later transliterations
+func TestFeedbackLearning(t *testing.T) {
+	ctx := context.Background()
+
+	// ѣ (yat) has no built-in mapping, so it passes through unchanged until learned
+	transliterateText := func(text string) *TransliterationResponse {
+		resp, err := Transliterate(ctx, &TransliterationRequest{
+			Text:         text,
+			InputScript:  "cyrillic",
+			OutputScript: "latin",
+		})
+		if err != nil {
+			t.Fatalf("Transliterate(%s) failed: %v", text, err)
+		}
+		return resp
+	}
+
+	correct := func(resp *TransliterationResponse, suggested, client string) {
+		submitAs(t, client)
+		err := SubmitFeedback(ctx, resp.ID, &FeedbackRequest{
+			SuggestedOutput: suggested,
+			FeedbackType:    "correction",
+		})
+		if err != nil {
+			t.Fatalf("SubmitFeedback failed: %v", err)
+		}
+	}
+
+	bread := transliterateText("хлѣб")
+	if bread.OutputText != "khlѣb" {
+		t.Fatalf("Expected unlearned output 'khlѣb', got %q", bread.OutputText)
+	}
+
+	// A single correction is not enough to promote the mapping
+	correct(bread, "khlieb", "client-a")
+	if again := transliterateText("хлѣб"); again.OutputText != "khlѣb" {
+		t.Errorf("Expected mapping to need corroboration, got %q", again.OutputText)
+	}
+
+	// Nor is the same client correcting a second transliteration
+	price := transliterateText("цѣна")
+	correct(price, "tsiena", "client-a")
+	if again := transliterateText("хлѣб"); again.OutputText != "khlѣb" {
+		t.Errorf("Expected mapping to need a second client, got %q", again.OutputText)
+	}
+
+	// Another client agreeing on ѣ -> ie promotes it
+	measure := transliterateText("мѣра")
+	correct(measure, "miera", "client-b")
+
+	if learned := transliterateText("хлѣб"); learned.OutputText != "khlieb" {
+		t.Errorf("Expected learned output 'khlieb', got %q", learned.OutputText)
+	}
+}
+
+// TestPreferredFeedback tests corroborated "preferred" feedback replacing the cached output
+func TestPreferredFeedback(t *testing.T) {
+	ctx := context.Background()
+	req := &TransliterationRequest{Text: "Пётр Чайковский", InputScript: "cyrillic", OutputScript: "latin"}
+
+	original, err := Transliterate(ctx, req)
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	computed := original.OutputText
+	preferred := "Pyotr Tchaikovsky"
+	t.Cleanup(func() {
+		db.Exec(ctx, `UPDATE transliterations SET preferred_output = NULL WHERE id = $1`, original.ID)
+	})
+
+	prefer := func(client string) {
+		t.Helper()
+		submitAs(t, client)
+		if err := SubmitFeedback(ctx, original.ID, &FeedbackRequest{SuggestedOutput: preferred, FeedbackType: "preferred"}); err != nil {
+			t.Fatalf("SubmitFeedback failed: %v", err)
+		}
+	}
+
+	// One client preferring it, however often, is not enough
+	prefer("client-a")
+	prefer("client-a")
+	// Nor are clients without a known peer address, who could be anyone
+	prefer("")
+	prefer("")
+	if stored, err := GetTransliteration(ctx, original.ID); err != nil || stored.OutputText != computed || stored.Preferred {
+		t.Fatalf("Expected the computed output %q before corroboration, got %+v (%v)", computed, stored, err)
+	}
+
+	// A second client's agreeing preference replaces the cached output
+	prefer("client-b")
+	stored, err := GetTransliteration(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetTransliteration failed: %v", err)
+	}
+	if stored.OutputText != preferred || !stored.Preferred {
+		t.Errorf("Expected the preferred output %q, got %q", preferred, stored.OutputText)
+	}
+
+	// Corrections are still aligned with the computed output
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM mapping_suggestions WHERE transliteration_id = $1`, original.ID)
+	})
+	corrected := strings.Replace(computed, "Chay", "Chai", 1)
+	if err := SubmitFeedback(ctx, original.ID, &FeedbackRequest{SuggestedOutput: corrected, FeedbackType: "correction"}); err != nil {
+		t.Fatalf("SubmitFeedback failed: %v", err)
+	}
+	var suggestions int
+	err = db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM mapping_suggestions
+		WHERE transliteration_id = $1 AND source_char = 'й' AND target_char = 'i'
+	`, original.ID).Scan(&suggestions)
+	if err != nil || suggestions != 1 {
+		t.Errorf("Expected the correction of %q to suggest й → i, got %d suggestions (%v)", computed, suggestions, err)
+	}
+
+	cached, err := Transliterate(ctx, req)
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if !cached.FromCache || cached.OutputText != preferred || cached.ID != original.ID {
+		t.Errorf("Expected the cache hit to return %q, got %q (from cache %v)", preferred, cached.OutputText, cached.FromCache)
+	}
+}
+
+// TestFeedbackModeration tests approving and rejecting corrections from the moderation queue
+func TestFeedbackModeration(t *testing.T) {
+	ctx := context.Background()
+
+	// ѳ (fita) and ѵ (izhitsa) have no built-in mapping, so they pass through until approved
+	correct := func(text, suggested string) PendingFeedback {
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin"})
+		if err != nil {
+			t.Fatalf("Transliterate(%s) failed: %v", text, err)
+		}
+		if err := SubmitFeedback(ctx, resp.ID, &FeedbackRequest{SuggestedOutput: suggested, FeedbackType: "correction"}); err != nil {
+			t.Fatalf("SubmitFeedback failed: %v", err)
+		}
+
+		pending, err := ListPendingFeedback(ctx, &PendingFeedbackParams{Limit: maxPendingLimit})
+		if err != nil {
+			t.Fatalf("ListPendingFeedback failed: %v", err)
+		}
+		for _, item := range pending.Feedback {
+			if item.TransliterationID == resp.ID && item.SuggestedOutput == suggested {
+				return item
+			}
+		}
+		t.Fatalf("Correction of %s not in the pending queue", text)
+		return PendingFeedback{}
+	}
+
+	// Approval promotes the mapping without waiting for corroboration
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM character_mappings WHERE source_char IN ('ѳ', 'ѯ') AND source_script = 'cyrillic'`)
+		db.Exec(ctx, `DELETE FROM mapping_suggestions WHERE source_char IN ('ѳ', 'ѯ') AND source_script = 'cyrillic'`)
+	})
+	approved := correct("ѳома", "foma")
+	if approved.Mapping == nil || approved.Mapping.SourceChar != "ѳ" || approved.Mapping.TargetChar != "f" {
+		t.Fatalf("Expected the queue to show mapping ѳ -> f, got %+v", approved.Mapping)
+	}
+	review, err := ApproveFeedback(ctx, approved.ID)
+	if err != nil {
+		t.Fatalf("ApproveFeedback failed: %v", err)
+	}
+	if review.Status != feedbackApproved || review.Mapping == nil || review.Mapping.TargetChar != "f" {
+		t.Errorf("Expected approved mapping ѳ -> f, got %+v", review)
+	}
+	learned, err := Transliterate(ctx, &TransliterationRequest{Text: "ѳеодоръ", InputScript: "cyrillic", OutputScript: "latin", Preview: true})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if !strings.HasPrefix(learned.OutputText, "feodor") {
+		t.Errorf("Expected the approved mapping to apply, got %q", learned.OutputText)
+	}
+
+	// Rejection leaves the mappings alone
+	rejected := correct("ѵера", "vera")
+	review, err = RejectFeedback(ctx, rejected.ID)
+	if err != nil {
+		t.Fatalf("RejectFeedback failed: %v", err)
+	}
+	if review.Status != feedbackRejected || review.Mapping != nil {
+		t.Errorf("Expected a rejection without mapping, got %+v", review)
+	}
+	unlearned, err := Transliterate(ctx, &TransliterationRequest{Text: "ѵмна", InputScript: "cyrillic", OutputScript: "latin", Preview: true})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if !strings.HasPrefix(unlearned.OutputText, "ѵ") {
+		t.Errorf("Expected no mapping for ѵ after rejection, got %q", unlearned.OutputText)
+	}
+
+	// Reviewed corrections leave the queue and cannot be reviewed again
+	pending, err := ListPendingFeedback(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPendingFeedback failed: %v", err)
+	}
+	for _, item := range pending.Feedback {
+		if item.ID == approved.ID || item.ID == rejected.ID {
+			t.Errorf("Reviewed feedback %s still pending", item.ID)
+		}
+	}
+	_, err = ApproveFeedback(ctx, rejected.ID)
+	assertErrorReason(t, err, errs.FailedPrecondition, ReasonFeedbackAlreadyReviewed)
+	_, err = RejectFeedback(ctx, approved.ID)
+	assertErrorReason(t, err, errs.FailedPrecondition, ReasonFeedbackAlreadyReviewed)
+
+	// Corrections promoted by corroboration are approved with their mapping
+	submitAs(t, "client-a")
+	first := correct("ѯана", "ksana")
+	submitAs(t, "client-b")
+	resp, err := Transliterate(ctx, &TransliterationRequest{Text: "ѯено", InputScript: "cyrillic", OutputScript: "latin"})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if err := SubmitFeedback(ctx, resp.ID, &FeedbackRequest{SuggestedOutput: "kseno", FeedbackType: "correction"}); err != nil {
+		t.Fatalf("SubmitFeedback failed: %v", err)
+	}
+	pending, err = ListPendingFeedback(ctx, &PendingFeedbackParams{Limit: maxPendingLimit})
+	if err != nil {
+		t.Fatalf("ListPendingFeedback failed: %v", err)
+	}
+	for _, item := range pending.Feedback {
+		if item.ID == first.ID || item.TransliterationID == resp.ID {
+			t.Errorf("Promoted correction %s still pending", item.ID)
+		}
+	}
+	_, err = RejectFeedback(ctx, first.ID)
+	assertErrorReason(t, err, errs.FailedPrecondition, ReasonFeedbackAlreadyReviewed)
+}
+
+// TestFeedbackModerationValidation tests the moderation endpoints' checks made before the database
+func TestFeedbackModerationValidation(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := ApproveFeedback(ctx, "not-a-uuid")
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidID)
+	_, err = RejectFeedback(ctx, "not-a-uuid")
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidID)
+	_, err = ListPendingFeedback(ctx, &PendingFeedbackParams{Limit: maxPendingLimit + 1})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidLimit)
+}
+
+// TestMinDetectionConfidence tests rejecting ambiguous mixed-script input around the threshold
+func TestMinDetectionConfidence(t *testing.T) {
+	// Ten Latin and nine Cyrillic letters: Latin wins at the 0.70 confidence tier
+	mixed := detection.DetectScript("Привет мир hello world")
+	if mixed.Script != "latin" || mixed.Confidence != 0.70 {
+		t.Fatalf("DetectScript = %s (%.2f), want latin (0.70)", mixed.Script, mixed.Confidence)
+	}
+
+	tests := []struct {
+		name           string
+		minConfidence  float64
+		expectedScript string
+	}{
+		{"Default accepts any detection", 0, "latin"},
+		{"Just below detection", 0.69, "latin"},
+		{"Exactly at detection", 0.70, "latin"},
+		{"Just above detection", 0.71, ""},
+		{"Strict", 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := detectInputScript(mixed, tt.minConfidence)
+			if tt.expectedScript == "" {
+				assertErrorReason(t, err, errs.InvalidArgument, ReasonDetectionConfidenceLow)
+				var apiErr *errs.Error
+				if errors.As(err, &apiErr) && !strings.Contains(apiErr.Message, "input_script") {
+					t.Errorf("Expected error to suggest input_script, got %q", apiErr.Message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if script != tt.expectedScript {
+				t.Errorf("detectInputScript = %q, want %q", script, tt.expectedScript)
+			}
+		})
+	}
+
+	// A single-script name clears a strict threshold
+	if script, err := detectInputScript(detection.DetectScript("Владимир"), 0.95); err != nil || script != "cyrillic" {
+		t.Errorf("detectInputScript(Владимир, 0.95) = %q, %v; want cyrillic", script, err)
+	}
+
+	_, err := detectInputScript(detection.DetectScript("12345"), 0)
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonScriptUndetectable)
+}
+
+// TestScriptMismatchPolicy tests handling of an input_script that disagrees with detection
+func TestScriptMismatchPolicy(t *testing.T) {
+	detected := detection.DetectScript("Привет")
+
+	tests := []struct {
+		policy         string
+		expectScript   string
+		expectWarning  bool
+		expectedReason string
+	}{
+		{policy: "", expectScript: "latin"},
+		{policy: scriptMismatchTrustClient, expectScript: "latin"},
+		{policy: scriptMismatchTrustDetection, expectScript: "cyrillic", expectWarning: true},
+		{policy: scriptMismatchWarn, expectScript: "latin", expectWarning: true},
+		{policy: scriptMismatchError, expectedReason: ReasonScriptMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run("policy "+tt.policy, func(t *testing.T) {
+			script, warning, err := applyScriptMismatchPolicy("latin", tt.policy, detected)
+			if tt.expectedReason != "" {
+				assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if script != tt.expectScript {
+				t.Errorf("Expected script %s, got %s", tt.expectScript, script)
+			}
+			if (warning != "") != tt.expectWarning {
+				t.Errorf("Expected warning=%v, got %q", tt.expectWarning, warning)
+			}
+		})
+	}
+
+	// Compatible detections are never treated as disagreement
+	agreeing := []struct {
+		text     string
+		provided string
+	}{
+		{"Nguyễn Văn Minh", "latin"},
+		{"Müller", "latin"},
+		{"山田", "japanese"},
+		{"Привет", "cyrillic"},
+	}
+	for _, tt := range agreeing {
+		script, warning, err := applyScriptMismatchPolicy(tt.provided, scriptMismatchError, detection.DetectScript(tt.text))
+		if err != nil || warning != "" || script != tt.provided {
+			t.Errorf("Expected %q as %s to be accepted, got script=%s warning=%q err=%v", tt.text, tt.provided, script, warning, err)
+		}
+	}
+
+	req := &TransliterationRequest{Text: "Привет", InputScript: "latin", OutputScript: "ascii", ScriptMismatch: "ignore"}
+	assertErrorReason(t, validateTransliterationRequest(req), errs.InvalidArgument, ReasonInvalidScriptMismatchPolicy)
+}
+
+// TestLanguageHint tests the language hint surfaced in responses
+func TestLanguageHint(t *testing.T) {
+	tests := []struct {
+		text            string
+		expectLanguage  string
+		expectIndicator string
+	}{
+		{"Nguyễn Văn Minh", "vi", "vietnamese_diacritics"},
+		{"Jürgen Müller", "de", "german_umlauts"},
+		{"Дмитрий Соловьёв", "ru", "russian_patterns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			scriptInfo := detection.DetectScript(tt.text)
+			hint := responseLanguageHint(detection.DetectLanguage(tt.text, scriptInfo))
+			if hint == nil {
+				t.Fatal("Expected a language hint")
+			}
+			if hint.Language != tt.expectLanguage {
+				t.Errorf("Expected language %s, got %s", tt.expectLanguage, hint.Language)
+			}
+			if !containsString(hint.Indicators, tt.expectIndicator) {
+				t.Errorf("Expected indicator %s, got %v", tt.expectIndicator, hint.Indicators)
+			}
+			if hint.Confidence <= 0 || hint.Confidence > 1 {
+				t.Errorf("Confidence out of range: %f", hint.Confidence)
+			}
+		})
+	}
+
+	// Unknown languages are omitted from the response
+	if hint := responseLanguageHint(detection.DetectLanguage("12345", detection.DetectScript("12345"))); hint != nil {
+		t.Errorf("Expected no hint for unknown language, got %+v", hint)
+	}
+
+	encoded, err := json.Marshal(&TransliterationResponse{LanguageHint: &LanguageHint{Language: "vi", Confidence: 0.85, Indicators: []string{"vietnamese_diacritics"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"language_hint":{"language":"vi","confidence":0.85,"indicators":["vietnamese_diacritics"]}`) {
+		t.Errorf("Unexpected language_hint encoding: %s", encoded)
+	}
+}
+
+// TestChinesePolyphonicWords tests that polyphonic characters take the reading of their word
+func TestChinesePolyphonicWords(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		text     string
+		toScript string
+		expected string
+	}{
+		{"长 as chang", "长城", "latin", "Changcheng"},
+		{"长 as zhang", "长大", "latin", "Zhangda"},
+		{"行 as hang", "银行", "latin", "Yinhang"},
+		{"行 as xing", "行人", "ascii", "Xingren"},
+		{"Traditional form", "銀行", "ascii", "Yinhang"},
+		{"Word inside a sentence", "我去银行", "ascii", "woQuYinhang"},
+		{"Place name", "重庆", "latin", "Chongqing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.text, "chinese", tt.toScript, "zh-CN")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.text, result.Output, tt.expected)
+			}
+		})
+	}
+
+	// A surname reading takes precedence over a word starting with the surname
+	result, err := engine.TransliterateName(context.Background(), "乐山", "chinese", "latin", "zh-CN", 1)
+	if err != nil {
+		t.Fatalf("TransliterateName failed: %v", err)
+	}
+	if !strings.HasPrefix(result.Output, "Yue") {
+		t.Errorf("Expected the surname reading Yue, got %q", result.Output)
+	}
+}
+
+// TestChineseSurnameReadings tests surnames whose reading differs from the common one
+func TestChineseSurnameReadings(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"单 as surname", "单小明", "ShanXiaoMing"},
+		{"单 elsewhere", "小单", "XiaoDan"},
+		{"解 as surname", "解明", "XieMing"},
+		{"解 elsewhere", "明解", "MingJie"},
+		{"仇 as surname", "仇英", "QiuYing"},
+		{"仇 elsewhere", "英仇", "YingChou"},
+		{"区 as surname", "区小明", "OuXiaoMing"},
+		{"区 elsewhere", "小区", "XiaoQu"},
+		{"Separated surname", "单 小明", "Shan XiaoMing"},
+		{"Traditional form", "單小明", "ShanXiaoMing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			surnameRunes := nameparser.ChineseSurnameLength(tt.text)
+			result, err := engine.TransliterateName(context.Background(), tt.text, "chinese", "latin", "zh-CN", surnameRunes)
+			if err != nil {
+				t.Fatalf("TransliterateName failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+		})
+	}
+
+	lengths := []struct {
+		text     string
+		expected int
+	}{
+		{"李小明", 1},
+		{"欧阳修", 2},
+		{"诸葛亮", 2},
+		{"欧阳", 1},
+		{"司马 光", 2},
+		{"你好世界再见", 0},
+		{"李 Smith", 0},
+	}
+	for _, tt := range lengths {
+		if got := nameparser.ChineseSurnameLength(tt.text); got != tt.expected {
+			t.Errorf("ChineseSurnameLength(%q) = %d, expected %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+// TestFromCache tests that repeated requests are reported as cache hits
+func TestFromCache(t *testing.T) {
+	req := TransliterationRequest{
+		Text:         "Мария Кузнецова",
+		InputScript:  "cyrillic",
+		OutputScript: "latin",
+	}
+
+	first, err := Transliterate(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+
+	second, err := Transliterate(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+
+	// The first request may itself hit rows left by earlier runs, but the second always does
+	if !second.FromCache {
+		t.Error("Expected repeated request to be served from cache")
+	}
+	if second.ID != first.ID && first.FromCache {
+		t.Errorf("Expected cache hits to return the same record, got %s and %s", first.ID, second.ID)
+	}
+
+	// Requests with other options are cached under their own options hash
+	req.NumberWords = true
+	other, err := Transliterate(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if other.ID != "" && other.ID == second.ID {
+		t.Errorf("Expected request with different options not to reuse record %s", second.ID)
+	}
+}
+
+// TestMixedScriptSegmentation tests converting each script run with its own rules
+func TestMixedScriptSegmentation(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name         string
+		text         string
+		outputScript string
+		expected     string
+	}{
+		{"Latin and Cyrillic", "John Иванов", "ascii", "John Ivanov"},
+		{"Cyrillic and Latin", "Мария Smith", "latin", "Mariya Smith"},
+		{"Latin and CJK", "Dr. 李明 Smith", "ascii", "Dr. LiMing Smith"},
+		{"CJK and Latin", "王明 Smith", "latin", "WangMing Smith"},
+		{"Vietnamese and Cyrillic", "Nguyễn Иван", "ascii", "Nguyen Ivan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Detection picks a single dominant script; the runs supply the rest
+			script := detection.DetectScript(tt.text).Script
+			result, err := engine.Transliterate(context.Background(), tt.text, script, tt.outputScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+			if strings.Contains(result.Output, "?") {
+				t.Errorf("Output contains unmapped placeholder: %q", result.Output)
+			}
+			if len(result.Notes) > 0 {
+				t.Errorf("Expected every character to be mapped, got notes %v", result.Notes)
+			}
+		})
+	}
+
+	runs := transliteration.SplitRuns("Dr. 李明 Smith", "latin")
+	expected := []transliteration.Run{
+		{Script: "latin", Text: "Dr. "},
+		{Script: "chinese", Text: "李明 "},
+		{Script: "latin", Text: "Smith"},
+	}
+	if len(runs) != len(expected) {
+		t.Fatalf("Expected %d runs, got %+v", len(expected), runs)
+	}
+	for i := range runs {
+		if runs[i] != expected[i] {
+			t.Errorf("Run %d: expected %+v, got %+v", i, expected[i], runs[i])
+		}
+	}
+}
+
+// TestFurigana tests that parenthesized kana readings override kanji readings
+func TestFurigana(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Family name", "田中(たなか)", "Tanaka"},
+		{"Full name", "田中(たなか) 花子(はなこ)", "Tanaka Hanako"},
+		{"Full-width parentheses", "田中（たなか）", "Tanaka"},
+		{"Unclosed reading left as is", "(たなか", "(tanaka"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := detection.DetectScript(tt.text).Script
+			result, err := engine.Transliterate(context.Background(), tt.text, script, "latin", "ja")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+		})
+	}
+}
+
+// TestJapaneseRomanization tests hiragana, katakana and common kanji names
+func TestJapaneseRomanization(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Hiragana", "たなか", "tanaka"},
+		{"Hiragana digraph", "きょうこ", "kyouko"},
+		{"Hiragana sh digraph", "しゃしん", "shashin"},
+		{"Katakana", "スミス", "sumisu"},
+		{"Katakana digraph", "チョコ", "choko"},
+		{"Kanji family name", "田中", "Tanaka"},
+		{"Kanji full name", "山田太郎", "YamadaTarou"},
+		{"Kanji names with space", "佐藤 花子", "Satou Hanako"},
+		{"Repeat mark", "佐々木", "Sasaki"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.text, "japanese", "ascii", "ja")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+			if strings.Contains(result.Output, "?") {
+				t.Errorf("Output contains unmapped placeholder: %q", result.Output)
+			}
+		})
+	}
+
+	if !isSupportedScriptPair("japanese", "ascii") || !validScripts["japanese"] {
+		t.Error("Expected japanese to be a supported input script")
+	}
+	if script := detection.DetectScript("スミス").Script; script != "japanese" {
+		t.Errorf("Expected katakana to be detected as japanese, got %s", script)
+	}
+}
+
+// TestKatakanaForeignNames tests the small-vowel combinations katakana uses for foreign
+// names, and how those names are parsed
+func TestKatakanaForeignNames(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"スミス", "sumisu"},
+		{"ジョン", "jon"},
+		{"ファティマ", "fatima"},
+		{"ウィリアム", "wiriamu"},
+		{"ヴィクトル", "vikutoru"},
+		{"ディラン", "diran"},
+		{"ウェンディ", "wendi"},
+		{"チェルシー", "cherushii"},
+		{"ジョン・スミス", "jon sumisu"},
+		{"ァ", "a"}, // A small vowel on its own
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.text, "japanese", "ascii", "ja")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+		})
+	}
+
+	names := []struct {
+		text      string
+		fullASCII string
+	}{
+		{"スミス", "Sumisu"},
+		{"ジョン", "Jon"},
+		{"ジョン・スミス", "Jon SUMISU"}, // Foreign names keep their given-first order
+	}
+	for _, tt := range names {
+		t.Run("name "+tt.text, func(t *testing.T) {
+			resp, err := Transliterate(context.Background(), &TransliterationRequest{
+				Text:         tt.text,
+				InputScript:  "japanese",
+				OutputScript: "ascii",
+				Preview:      true,
+			})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.Name == nil || resp.Name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected name %q, got %+v", tt.fullASCII, resp.Name)
+			}
+		})
+	}
+}
+
+// TestJapaneseLongVowels tests small-tsu gemination and long vowel styles
+func TestJapaneseLongVowels(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		style    string
+		expected string
+	}{
+		{"Sokuon", "がっこう", transliteration.LongVowelsDoubled, "gakkou"},
+		{"Sokuon macron", "がっこう", transliteration.LongVowelsMacron, "gakkō"},
+		{"Katakana sokuon", "ニッポン", transliteration.LongVowelsDoubled, "nippon"},
+		{"Sokuon before ch", "まっちゃ", transliteration.LongVowelsDoubled, "matcha"},
+		{"Prolonged sound mark", "トーキョー", transliteration.LongVowelsDoubled, "tookyoo"},
+		{"Prolonged sound mark macron", "トーキョー", transliteration.LongVowelsMacron, "tōkyō"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := transliteration.DefaultConfig()
+			config.UseDatabase = false
+			config.LongVowels = tt.style
+			engine := transliteration.NewEngine(config, nil)
+
+			result, err := engine.Transliterate(context.Background(), tt.text, "japanese", "latin", "ja")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Output)
+			}
+		})
+	}
+
+	t.Run("ASCII output stays doubled", func(t *testing.T) {
+		config := transliteration.DefaultConfig()
+		config.UseDatabase = false
+		config.LongVowels = transliteration.LongVowelsMacron
+		engine := transliteration.NewEngine(config, nil)
+
+		result, err := engine.Transliterate(context.Background(), "トーキョー", "japanese", "ascii", "ja")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if result.Output != "tookyoo" {
+			t.Errorf("Expected %q, got %q", "tookyoo", result.Output)
+		}
+	})
+
+	t.Run("Invalid style rejected", func(t *testing.T) {
+		req := &TransliterationRequest{Text: "トーキョー", OutputScript: "latin", LongVowels: "circumflex"}
+		assertErrorReason(t, validateTransliterationRequest(req), errs.InvalidArgument, ReasonInvalidLongVowels)
+	})
+}
+
+// TestUnmappedPolicy tests each output policy for a character without any mapping
+func TestUnmappedPolicy(t *testing.T) {
+	// 𪚥 is a CJK Extension B character with no reading and no ASCII approximation
+	input := "李𪚥"
+
+	tests := []struct {
+		policy   string
+		toScript string
+		expected string
+	}{
+		{"", "ascii", "Li?"},
+		{"", "latin", "Li𪚥"},
+		{transliteration.UnmappedQuestion, "latin", "Li?"},
+		{transliteration.UnmappedDrop, "ascii", "Li"},
+		{transliteration.UnmappedKeep, "ascii", "Li𪚥"},
+		{transliteration.UnmappedUnicodeName, "ascii", "Li[CJK UNIFIED IDEOGRAPH-2A6A5]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+" "+tt.toScript, func(t *testing.T) {
+			config := transliteration.DefaultConfig()
+			config.UseDatabase = false
+			config.UnmappedPolicy = tt.policy
+			engine := transliteration.NewEngine(config, nil)
+
+			result, err := engine.Transliterate(context.Background(), input, "chinese", tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) with %q = %q, want %q", input, tt.policy, result.Output, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Applied by the endpoint", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:           input,
+			InputScript:    "chinese",
+			OutputScript:   "ascii",
+			UnmappedPolicy: transliteration.UnmappedDrop,
+			Preview:        true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputText != "Li" {
+			t.Errorf("Expected the unmapped character to be dropped, got %q", resp.OutputText)
+		}
+	})
+
+	t.Run("Invalid policy rejected", func(t *testing.T) {
+		req := &TransliterationRequest{Text: input, OutputScript: "ascii", UnmappedPolicy: "omit"}
+		assertErrorReason(t, validateTransliterationRequest(req), errs.InvalidArgument, ReasonInvalidUnmappedPolicy)
+	})
+}
+
+// TestPreserveEmoji tests keeping emoji and symbols as written in ASCII output
+func TestPreserveEmoji(t *testing.T) {
+	input := "Привет 😀👍🏽 👩\u200d💻 ✓ © €5"
+
+	tests := []struct {
+		name     string
+		emoji    bool
+		symbols  bool
+		expected string
+	}{
+		{"Default", false, false, "Privet ??? ??? ? (c) EUR5"},
+		{"Emoji", true, false, "Privet 😀👍🏽 👩\u200d💻 ✓ (c) EUR5"},
+		{"Symbols", false, true, "Privet 😀👍🏽 👩\u200d💻 ✓ © €5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Transliterate(context.Background(), &TransliterationRequest{
+				Text:            input,
+				InputScript:     "cyrillic",
+				OutputScript:    "ascii",
+				PreserveEmoji:   tt.emoji,
+				PreserveSymbols: tt.symbols,
+				Preview:         true,
+			})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.OutputText != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", input, resp.OutputText, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Preserved emoji are not unmapped", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:          "Hi 😀",
+			OutputScript:  "ascii",
+			PreserveEmoji: true,
+			Preview:       true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputText != "Hi 😀" || resp.UnmappedCount != 0 {
+			t.Errorf("Expected the emoji to survive, got %q with %d unmapped", resp.OutputText, resp.UnmappedCount)
+		}
+	})
+
+	t.Run("Cached separately", func(t *testing.T) {
+		plain := transliterationOptionsHash(&TransliterationRequest{OutputScript: "ascii"})
+		emoji := transliterationOptionsHash(&TransliterationRequest{OutputScript: "ascii", PreserveEmoji: true})
+		symbols := transliterationOptionsHash(&TransliterationRequest{OutputScript: "ascii", PreserveSymbols: true})
+		both := transliterationOptionsHash(&TransliterationRequest{OutputScript: "ascii", PreserveEmoji: true, PreserveSymbols: true})
+		if plain == emoji || emoji == symbols || plain == symbols {
+			t.Errorf("Expected distinct options hashes, got %q, %q and %q", plain, emoji, symbols)
+		}
+		if both != symbols {
+			t.Errorf("preserve_symbols includes emoji, so adding preserve_emoji should not change the hash: %q != %q", both, symbols)
+		}
+	})
+}
+
+// TestAdditionalOutputScripts tests converting to several output scripts in one request
+func TestAdditionalOutputScripts(t *testing.T) {
+	t.Run("Latin and ASCII", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:                    "Привет",
+			OutputScript:            "latin",
+			AdditionalOutputScripts: []string{"ascii"},
+			Preview:                 true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputScript != "latin" || resp.OutputText != "Privet" {
+			t.Errorf("Expected the latin conversion as the response, got %s %q", resp.OutputScript, resp.OutputText)
+		}
+		want := map[string]string{"latin": "Privet", "ascii": "Privet"}
+		if len(resp.Outputs) != len(want) {
+			t.Fatalf("Outputs = %v, want %v", resp.Outputs, want)
+		}
+		for script, output := range want {
+			if resp.Outputs[script] != output {
+				t.Errorf("Outputs[%s] = %q, want %q", script, resp.Outputs[script], output)
+			}
+		}
+	})
+
+	t.Run("Outputs differ by script", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:                    "Σοφία",
+			OutputScript:            "latin",
+			AdditionalOutputScripts: []string{"ascii"},
+			PreserveDiacritics:      true,
+			Preview:                 true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.Outputs["latin"] != "Sophía" || resp.Outputs["ascii"] != "Sophia" {
+			t.Errorf("Outputs = %v, want latin Sophía and ascii Sophia", resp.Outputs)
+		}
+	})
+
+	t.Run("Single output script has no outputs", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Привет", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.Outputs != nil {
+			t.Errorf("Expected no outputs map, got %v", resp.Outputs)
+		}
+	})
+
+	t.Run("Unsupported pair rejected", func(t *testing.T) {
+		_, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:                    "Привет",
+			OutputScript:            "latin",
+			AdditionalOutputScripts: []string{"greek"},
+			Preview:                 true,
+		})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedScriptPair)
+	})
+}
+
+// TestDomainSafe tests reducing ascii output to a DNS label
+func TestDomainSafe(t *testing.T) {
+	ctx := context.Background()
+	validLabel := regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Title dropped and umlauts expanded", "Dr. Jürgen Groß", "juergen-gross"},
+		{"Cyrillic", "Владимир Путин", "vladimir-putin"},
+		{"Apostrophe and hyphen", "Seán O'Brien-Smith", "sean-obrien-smith"},
+		{"Repeated separators", "Anna  --  Maria", "anna-maria"},
+		{"Truncated to 63 characters", strings.Repeat("Abcdefghij ", 8), strings.TrimRight(strings.Repeat("abcdefghij-", 6)[:63], "-")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Transliterate(ctx, &TransliterationRequest{Text: tt.input, OutputScript: "ascii", DomainSafe: true, Preview: true})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.OutputText != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, resp.OutputText, tt.expected)
+			}
+			if !validLabel.MatchString(resp.OutputText) || len(resp.OutputText) > maxDomainLabelLength {
+				t.Errorf("%q is not a valid DNS label", resp.OutputText)
+			}
+		})
+	}
+
+	t.Run("Requires ascii output", func(t *testing.T) {
+		_, err := Transliterate(ctx, &TransliterationRequest{Text: "Jürgen", OutputScript: "latin", DomainSafe: true, Preview: true})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidDomainSafe)
+
+		_, err = Transliterate(ctx, &TransliterationRequest{Text: "Jürgen", OutputScript: "ascii", DomainSafe: true, InlineOriginal: true, Preview: true})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidDomainSafe)
+	})
+}
+
+// TestPronunciation tests the approximate IPA reading added for include_pronunciation
+func TestPronunciation(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		input    string
+		contains string
+	}{
+		{"Chinese", "李小明", "ɕjau"},
+		{"Arabic emphatic", "صالح", "sˤ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Transliterate(ctx, &TransliterationRequest{Text: tt.input, OutputScript: "latin", IncludePronunciation: true, Preview: true})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.Pronunciation == nil || resp.Pronunciation.IPA == "" {
+				t.Fatalf("Expected a pronunciation for %q", tt.input)
+			}
+			if !resp.Pronunciation.Approximate {
+				t.Error("Expected the pronunciation to be marked approximate")
+			}
+			if !strings.Contains(resp.Pronunciation.IPA, tt.contains) {
+				t.Errorf("Expected %q in the pronunciation, got %q", tt.contains, resp.Pronunciation.IPA)
+			}
+		})
+	}
+
+	t.Run("Only on request", func(t *testing.T) {
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: "李小明", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.Pronunciation != nil {
+			t.Errorf("Expected no pronunciation, got %+v", resp.Pronunciation)
+		}
+	})
+}
+
+// TestProfiles tests that a named profile fills in the options a request leaves unset
+func TestProfiles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("german-official expands umlauts", func(t *testing.T) {
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: "Jörg Müller", Profile: "german-official", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputScript != "ascii" || resp.OutputText != "Joerg Mueller" {
+			t.Errorf("Expected ascii Joerg Mueller, got %s %q", resp.OutputScript, resp.OutputText)
+		}
+
+		resolved, err := applyProfile(ctx, &TransliterationRequest{Text: "Gül Öztürk", Profile: "german-official"})
+		if err != nil {
+			t.Fatalf("applyProfile failed: %v", err)
+		}
+		if resolved.GermanUmlautExpansion == nil || !*resolved.GermanUmlautExpansion || resolved.InputLocale == nil || *resolved.InputLocale != "de-DE" {
+			t.Errorf("Expected umlaut expansion for de-DE, got %v %v", resolved.GermanUmlautExpansion, resolved.InputLocale)
+		}
+		if resolved.Profile != "" {
+			t.Errorf("Expected the resolved request to drop the profile, got %q", resolved.Profile)
+		}
+	})
+
+	t.Run("Request fields override the profile", func(t *testing.T) {
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: "Müller", Profile: "german-official", GermanUmlautExpansion: optional(false), Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputText != "Muller" {
+			t.Errorf("Expected Muller, got %q", resp.OutputText)
+		}
+
+		resp, err = Transliterate(ctx, &TransliterationRequest{Text: "Müller", OutputScript: "latin", Profile: "german-official", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.OutputScript != "latin" || resp.OutputText != "Müller" {
+			t.Errorf("Expected latin Müller, got %s %q", resp.OutputScript, resp.OutputText)
+		}
+	})
+
+	t.Run("Built-in profiles", func(t *testing.T) {
+		profile, err := GetProfile(ctx, "german-official")
+		if err != nil {
+			t.Fatalf("GetProfile failed: %v", err)
+		}
+		if !profile.BuiltIn || profile.Options.OutputScript != "ascii" {
+			t.Errorf("Expected the built-in ascii profile, got %+v", profile)
+		}
+
+		_, err = SaveProfile(ctx, "german-official", &ProfileOptions{OutputScript: "latin"})
+		assertErrorReason(t, err, errs.FailedPrecondition, ReasonProfileBuiltIn)
+		err = DeleteProfile(ctx, "german-official")
+		assertErrorReason(t, err, errs.FailedPrecondition, ReasonProfileBuiltIn)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := GetProfile(ctx, "German Official")
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidProfileName)
+
+		_, err = Transliterate(ctx, &TransliterationRequest{Text: "Müller", Profile: "-official", Preview: true})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidProfileName)
+
+		_, err = SaveProfile(ctx, "japanese-macron", &ProfileOptions{LongVowels: "circumflex"})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidLongVowels)
+	})
+}
+
+// TestInlineOriginal tests combining the original and output for bilingual display
+func TestInlineOriginal(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      TransliterationRequest
+		expected string
+	}{
+		{"Disabled", TransliterationRequest{}, "Vladimir "},
+		{"Default template", TransliterationRequest{InlineOriginal: true}, "Владимир [Vladimir]"},
+		{"Output first", TransliterationRequest{InlineOriginal: true, InlineTemplate: "{output} ({input})"}, "Vladimir (Владимир)"},
+		{"Separator template", TransliterationRequest{InlineOriginal: true, InlineTemplate: "{output} / {input}"}, "Vladimir / Владимир"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Stray whitespace around the input never leaks into the template
+			resp := &TransliterationResponse{InputText: " Владимир ", OutputText: "Vladimir "}
+			applyInlineOriginal(resp, &tt.req)
+			if resp.OutputText != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, resp.OutputText)
+			}
+		})
+	}
+
+	invalid := []TransliterationRequest{
+		{Text: "Владимир", OutputScript: "latin", InlineOriginal: true, InlineTemplate: "{input} only"},
+		{Text: "Владимир", OutputScript: "latin", InlineTemplate: "{output} ({input})"},
+	}
+	for _, req := range invalid {
+		assertErrorReason(t, validateTransliterationRequest(&req), errs.InvalidArgument, ReasonInvalidInlineTemplate)
+	}
+}
+
+// TestMongolianScript tests Traditional Mongolian romanization across positional forms
+func TestMongolianScript(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	if script := detection.ClassifyRune('ᠮ'); script != "mongolian" {
+		t.Errorf("ClassifyRune('ᠮ') = %q, expected mongolian", script)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"word", "ᠮᠣᠩᠭᠣᠯ", "monggol"},
+		{"variation selector", "ᠮᠣᠩ\u180Bᠭᠣᠯ", "monggol"},
+		{"vowel separator", "ᠬᠠᠨ\u180Eᠠ", "khana"},
+		{"suffix", "ᠮᠣᠩᠭᠣᠯ\u202Fᠤᠨ", "monggol-un"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "mongolian", "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSourceCasePreserved tests casing of multi-letter output for uppercase source letters
+func TestSourceCasePreserved(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Capitalized", "Щукин", "Shchukin"},
+		{"All caps", "ЩУКИН", "SHCHUKIN"},
+		{"All caps digraph last", "ЛЮШ", "LYUSH"},
+		{"All caps phrase", "ПРИВЕТ ЖЕНЯ", "PRIVET ZHENYA"},
+		{"Single capital", "Ж", "Zh"},
+		{"Capitalized words", "Юлия Щербакова", "Yuliya Shcherbakova"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "cyrillic", "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestStats tests the usage statistics endpoint
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Invalid limit rejected", func(t *testing.T) {
+		for _, limit := range []int{-1, maxStatsLimit + 1} {
+			_, err := GetStats(ctx, &StatsParams{Limit: limit})
+			assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidLimit)
+		}
+	})
+
+	// Seed rows whose usage counts outrank anything created by other tests
+	seed := []struct {
+		text  string
+		usage int
+	}{
+		{"stats-seed-b", 2000002},
+		{"stats-seed-a", 2000003},
+		{"stats-seed-c", 2000001},
+	}
+	for _, row := range seed {
+		_, err := db.Exec(ctx, `
+			INSERT INTO transliterations (input_text, output_text, input_script, output_script, usage_count)
+			VALUES ($1, $1, 'latin', 'ascii', $2)
+		`, row.text, row.usage)
+		if err != nil {
+			t.Fatalf("seeding %s failed: %v", row.text, err)
+		}
+	}
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM transliterations WHERE input_text LIKE 'stats-seed-%'`)
+	})
+
+	stats, err := GetStats(ctx, &StatsParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+
+	if len(stats.Top) != 2 {
+		t.Fatalf("Expected 2 top transliterations, got %d", len(stats.Top))
+	}
+	if stats.Top[0].InputText != "stats-seed-a" || stats.Top[1].InputText != "stats-seed-b" {
+		t.Errorf("Expected top order stats-seed-a, stats-seed-b, got %s, %s", stats.Top[0].InputText, stats.Top[1].InputText)
+	}
+	if stats.TotalCount < int64(len(seed)) || stats.TotalUsage < 6000006 {
+		t.Errorf("Expected totals to include seeded rows, got count %d usage %d", stats.TotalCount, stats.TotalUsage)
+	}
+
+	found := false
+	for _, pair := range stats.ScriptPairs {
+		if pair.InputScript == "latin" && pair.OutputScript == "ascii" {
+			found = true
+			if pair.Count < int64(len(seed)) || pair.UsageCount < 6000006 {
+				t.Errorf("Expected latin→ascii to include seeded rows, got count %d usage %d", pair.Count, pair.UsageCount)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a latin→ascii script pair")
+	}
+}
+
+// TestListTransliterations tests paging through stored transliterations with filters
+func TestListTransliterations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Invalid parameters rejected", func(t *testing.T) {
+		tests := []struct {
+			params ListTransliterationsParams
+			reason string
+		}{
+			{ListTransliterationsParams{Limit: maxListLimit + 1}, ReasonInvalidLimit},
+			{ListTransliterationsParams{Offset: -1}, ReasonInvalidOffset},
+			{ListTransliterationsParams{InputScript: "klingon"}, ReasonUnsupportedInputScript},
+			{ListTransliterationsParams{OutputScript: "klingon"}, ReasonUnsupportedOutputScript},
+			{ListTransliterationsParams{MinConfidence: 1.5}, ReasonInvalidMinConfidence},
+		}
+		for _, tt := range tests {
+			_, err := ListTransliterations(ctx, &tt.params)
+			assertErrorReason(t, err, errs.InvalidArgument, tt.reason)
+		}
+	})
+
+	// Seed rows updated in the future so they come first, newest first a, b, c
+	seed := []struct {
+		text       string
+		confidence float64
+		hoursAhead int
+	}{
+		{"list-seed-a", 0.9, 3},
+		{"list-seed-b", 0.4, 2},
+		{"list-seed-c", 0.8, 1},
+	}
+	for _, row := range seed {
+		_, err := db.Exec(ctx, `
+			INSERT INTO transliterations (input_text, output_text, input_script, output_script, confidence_score, updated_at)
+			VALUES ($1, $1, 'armenian', 'latin', $2, NOW() + make_interval(hours => $3))
+		`, row.text, row.confidence, row.hoursAhead)
+		if err != nil {
+			t.Fatalf("seeding %s failed: %v", row.text, err)
+		}
+	}
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM transliterations WHERE input_text LIKE 'list-seed-%'`)
+	})
+
+	list := func(params ListTransliterationsParams) *TransliterationPage {
+		t.Helper()
+		page, err := ListTransliterations(ctx, &params)
+		if err != nil {
+			t.Fatalf("ListTransliterations(%+v) failed: %v", params, err)
+		}
+		return page
+	}
+	texts := func(page *TransliterationPage) []string {
+		var texts []string
+		for _, item := range page.Transliterations {
+			texts = append(texts, item.InputText)
+		}
+		return texts
+	}
+
+	t.Run("Filter by script", func(t *testing.T) {
+		page := list(ListTransliterationsParams{InputScript: "armenian", OutputScript: "latin", Limit: 3})
+		if got := strings.Join(texts(page), ","); got != "list-seed-a,list-seed-b,list-seed-c" {
+			t.Errorf("Expected seeded rows newest first, got %s", got)
+		}
+		if page.Total < 3 {
+			t.Errorf("Expected a total of at least 3, got %d", page.Total)
+		}
+		for _, item := range list(ListTransliterationsParams{OutputScript: "ascii"}).Transliterations {
+			if item.OutputScript != "ascii" {
+				t.Errorf("Expected only ascii output, got %s for %s", item.OutputScript, item.InputText)
+			}
+		}
+	})
+
+	t.Run("Filter by confidence", func(t *testing.T) {
+		page := list(ListTransliterationsParams{InputScript: "armenian", MinConfidence: 0.8, Limit: 2})
+		if got := strings.Join(texts(page), ","); got != "list-seed-a,list-seed-c" {
+			t.Errorf("Expected rows scoring 0.8 or more, got %s", got)
+		}
+	})
+
+	t.Run("Pagination boundaries", func(t *testing.T) {
+		first := list(ListTransliterationsParams{InputScript: "armenian", Limit: 2})
+		second := list(ListTransliterationsParams{InputScript: "armenian", Limit: 2, Offset: 2})
+		if got := strings.Join(texts(first), ","); got != "list-seed-a,list-seed-b" {
+			t.Errorf("Expected the first page a, b, got %s", got)
+		}
+		if len(second.Transliterations) == 0 || second.Transliterations[0].InputText != "list-seed-c" {
+			t.Errorf("Expected the second page to start with c, got %v", texts(second))
+		}
+		if first.Total != second.Total || first.Limit != 2 || second.Offset != 2 {
+			t.Errorf("Expected the same total and the requested paging, got %+v and %+v", first, second)
+		}
+
+		past := list(ListTransliterationsParams{InputScript: "armenian", Offset: int(first.Total)})
+		if len(past.Transliterations) != 0 || past.Total != first.Total {
+			t.Errorf("Expected an empty page past the end with the total, got %d rows of %d", len(past.Transliterations), past.Total)
+		}
+		if list(ListTransliterationsParams{}).Limit != defaultListLimit {
+			t.Errorf("Expected the default limit of %d", defaultListLimit)
+		}
+	})
+}
+
+// TestHebrewScript tests Hebrew romanization, including final letter forms
+func TestHebrewScript(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Vav as vowel", "שלום", "shlom"},
+		{"Final mem and nun", "מם נן", "mm nn"},
+		{"Final kaf, pe and tsadi", "כך פף צץ", "khkh pp tsts"},
+		{"Word-initial vav", "ורד", "vrd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "hebrew", "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+
+	if !validScripts["hebrew"] || !isSupportedScriptPair("hebrew", "latin") || !isSupportedScriptPair("hebrew", "ascii") {
+		t.Error("Expected hebrew to latin and ascii to be supported")
+	}
+	if script := detection.DetectScript("שלום").Script; script != "hebrew" {
+		t.Errorf("Expected hebrew to be detected, got %s", script)
+	}
+}
+
+// TestBidiControlStripping tests removal of invisible bidirectional formatting marks
+func TestBidiControlStripping(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		plain    string
+		script   string
+		expected string
+	}{
+		{"RLM around Hebrew", "\u200Fשלום\u200F", "שלום", "hebrew", "shlom"},
+		{"LRM inside Arabic", "محمد\u200E \u200Eعلي", "محمد علي", "arabic", ""},
+		{"Embedding and override", "\u202Bשלום\u202C \u202Eמם\u202C", "שלום מם", "hebrew", "shlom mm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped, err := stripBidiControls(tt.input)
+			if err != nil {
+				t.Fatalf("stripBidiControls failed: %v", err)
+			}
+			if stripped != tt.plain {
+				t.Fatalf("stripBidiControls(%q) = %q, want %q", tt.input, stripped, tt.plain)
+			}
+
+			if script := detection.DetectScript(stripped).Script; script != tt.script {
+				t.Errorf("Expected %s to be detected, got %s", tt.script, script)
+			}
+
+			result, err := engine.Transliterate(context.Background(), stripped, tt.script, "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if tt.expected != "" && result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", stripped, result.Output, tt.expected)
+			}
+
+			// Coverage is measured against the visible characters only
+			plain, err := engine.Transliterate(context.Background(), tt.plain, tt.script, "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if got, want := calculateCharacterCoverage(stripped, result.Output), calculateCharacterCoverage(tt.plain, plain.Output); got != want {
+				t.Errorf("Coverage = %v, want %v as for unmarked text", got, want)
+			}
+			if result.Confidence != plain.Confidence {
+				t.Errorf("Confidence = %v, want %v as for unmarked text", result.Confidence, plain.Confidence)
+			}
+		})
+	}
+
+	t.Run("Only control characters", func(t *testing.T) {
+		_, err := stripBidiControls("\u200F\u200E ")
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonTextEmpty)
+	})
+}
+
+// TestPreserveDiacritics tests keeping source accents on Latin output
+func TestPreserveDiacritics(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		script   string
+		output   string
+		standard string
+		preserve bool
+		expected string
+	}{
+		{"Greek tonos dropped by default", "Σοφία", "greek", "latin", "", false, "Sophia"},
+		{"Greek tonos kept", "Σοφία", "greek", "latin", "", true, "Sophía"},
+		{"Greek dialytika kept", "Ευφροσύνη Ϊ", "greek", "latin", "", true, "Eyphrosýnh Ï"},
+		{"ASCII output always stripped", "Σοφία", "greek", "ascii", "", true, "Sophia"},
+		{"Standard output unchanged", "مُحَمَّد", "arabic", "latin", transliteration.StandardBuckwalter, true, "muHama~d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := transliteration.DefaultConfig()
+			config.UseDatabase = false
+			config.Standard = tt.standard
+			config.PreserveDiacritics = tt.preserve
+			engine := transliteration.NewEngine(config, nil)
+
+			result, err := engine.Transliterate(context.Background(), tt.text, tt.script, tt.output, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.text, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCyrillicContextualLetters tests е, ё and the soft and hard signs read with their neighbours
+func TestCyrillicContextualLetters(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		locale   string
+		output   string
+		expected string
+	}{
+		{"Word-initial ye", "Евгений", "ru-RU", "latin", "Yevgeniy"},
+		{"Soft sign before yo", "Соловьёв", "ru-RU", "latin", "Solovyov"},
+		{"Soft sign before ye", "Григорьев", "ru-RU", "latin", "Grigoryev"},
+		{"Soft sign before o", "Бульон", "ru-RU", "latin", "Bulyon"},
+		{"Silent soft sign", "Ельцин", "ru-RU", "ascii", "Yeltsin"},
+		{"Hard sign", "Подъезд", "ru-RU", "latin", "Podyezd"},
+		{"Ye after a vowel", "Анна Сергеевна", "ru-RU", "latin", "Anna Sergeyevna"},
+		{"E after a consonant", "Привет", "ru-RU", "latin", "Privet"},
+		{"All caps", "ЕЛЬЦИН", "ru-RU", "ascii", "YELTSIN"},
+		{"Russian rules by default", "Евгений", "", "latin", "Yevgeniy"},
+		{"Ukrainian keeps e", "Сергеевна", "uk-UA", "latin", "Sergeevna"},
+		{"Bulgarian hard sign is a", "България", "bg-BG", "latin", "Balgariya"},
+		{"Bulgarian soft sign before o", "Кьосев", "bg-BG", "latin", "Kyosev"},
+		{"Serbian e", "Јелена", "sr-RS", "latin", "Jelena"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "cyrillic", tt.output, tt.locale)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q, %s) = %q, want %q", tt.input, tt.locale, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSerbianMacedonianCyrillic tests the national schemes selected by Serbian and Macedonian locales
+func TestSerbianMacedonianCyrillic(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		locale   string
+		output   string
+		expected string
+	}{
+		{"Serbian to latin", "Ђоковић", "sr-RS", "latin", "Djoković"},
+		{"Serbian to ascii", "Ђоковић", "sr-RS", "ascii", "Djokovic"},
+		{"Serbian digraph letters", "Љубљана Његош Џаја", "sr-RS", "latin", "Ljubljana Njegoš Džaja"},
+		{"Serbian all caps", "ЂОКОВИЋ", "sr-RS", "latin", "DJOKOVIĆ"},
+		{"Serbian je", "Јован", "sr-RS", "ascii", "Jovan"},
+		{"Macedonian", "Ѓорѓи Ќосевски", "mk-MK", "latin", "Gjorgji Kjosevski"},
+		{"Macedonian passport digraphs", "Кочо Рацин", "mk-MK", "ascii", "Kocho Racin"},
+		{"Macedonian dze", "Ѕвезда", "mk-MK", "latin", "Dzvezda"},
+		{"Serbian letters without a locale", "Ђоковић", "", "ascii", "Djokovic"},
+		{"Russian keeps its scheme", "Йорк Цветков", "ru-RU", "latin", "York Tsvetkov"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "cyrillic", tt.output, tt.locale)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q, %s) = %q, want %q", tt.input, tt.locale, result.Output, tt.expected)
+			}
+		})
+	}
+
+	// input_locale reaches the engine rather than the detected language (ru)
+	locale := "sr-RS"
+	resp, err := Transliterate(context.Background(), &TransliterationRequest{
+		Text:         "Новак Ђоковић",
+		InputScript:  "cyrillic",
+		OutputScript: "latin",
+		InputLocale:  &locale,
+		Preview:      true,
+	})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if resp.OutputText != "Novak Djoković" {
+		t.Errorf("Expected 'Novak Djoković' for sr-RS, got %q", resp.OutputText)
+	}
+}
+
+// TestArmenianScript tests Armenian romanization, including contextual and Western readings
+func TestArmenianScript(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		locale   string
+		expected string
+	}{
+		{"Initial ye and ligature", "Երևան", "hy", "Yerevan"},
+		{"All caps", "ԵՐԵՎԱՆ", "hy", "YEREVAN"},
+		{"Initial vo", "Ոսկան", "hy", "Voskan"},
+		{"Vowel digraph", "Ղուկաս", "hy", "Ghukas"},
+		{"Eastern", "Պետրոս", "hy", "Petros"},
+		{"Western", "Պետրոս", "hyw", "Bedros"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "armenian", "ascii", tt.locale)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+			if strings.Contains(result.Output, "?") {
+				t.Errorf("Output contains unmapped placeholder: %q", result.Output)
+			}
+		})
+	}
+
+	if !validScripts["armenian"] || !isSupportedScriptPair("armenian", "latin") {
+		t.Error("Expected armenian to latin to be supported")
+	}
+	if script := detection.DetectScript("Երևան").Script; script != "armenian" {
+		t.Errorf("Expected armenian to be detected, got %s", script)
+	}
+}
+
+// TestEthiopicScript tests Ge'ez syllables split into consonant and vowel order
+func TestEthiopicScript(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Country name", "ኢትዮጵያ", "ityop'ya"},
+		{"First, fourth and sixth orders", "ሰላም", "selam"},
+		{"Initial glottal", "አዲስ አበባ", "adis abeba"},
+		{"Laryngeal first order", "ሀገር", "hager"},
+		{"Labialized row", "ቋንቋ", "qwanqwa"},
+		{"Wordspace and full stop", "ሰላም፡ነው።", "selam new."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "ethiopic", "ascii", "am")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+			if strings.Contains(result.Output, "?") {
+				t.Errorf("Output contains unmapped placeholder: %q", result.Output)
+			}
+		})
+	}
+
+	if !validScripts["ethiopic"] || !isSupportedScriptPair("ethiopic", "latin") {
+		t.Error("Expected ethiopic to latin to be supported")
+	}
+	if script := detection.DetectScript("ኢትዮጵያ").Script; script != "ethiopic" {
+		t.Errorf("Expected ethiopic to be detected, got %s", script)
+	}
+}
+
+// TestScriptsEndpoint tests the supported scripts listing and pair quality tiers
+func TestScriptsEndpoint(t *testing.T) {
+	resp, err := GetScripts(context.Background())
+	if err != nil {
+		t.Fatalf("GetScripts failed: %v", err)
+	}
+
+	quality := make(map[string]string)
+	for _, pair := range resp.Pairs {
+		if !isSupportedScriptPair(pair.InputScript, pair.OutputScript) {
+			t.Errorf("Listed unsupported pair %s→%s", pair.InputScript, pair.OutputScript)
+		}
+		quality[pair.InputScript+"→"+pair.OutputScript] = pair.Quality
+	}
+
+	expected := map[string]string{
+		"cyrillic→latin": "medium",
+		"latin→ascii":    "high",
+		"chinese→latin":  "low",
+	}
+	for pair, want := range expected {
+		if got := quality[pair]; got != want {
+			t.Errorf("Quality of %s = %q, want %q", pair, got, want)
+		}
+	}
+
+	if !slices.Contains(resp.InputScripts, "armenian") || !slices.Equal(resp.OutputScripts, []string{"ascii", "greek", "latin", "vietnamese"}) {
+		t.Errorf("Unexpected scripts: input %v, output %v", resp.InputScripts, resp.OutputScripts)
+	}
+}
+
+// TestNormalizeEndpoint tests pure Unicode normalization through the API
+func TestNormalizeEndpoint(t *testing.T) {
+	ctx := context.Background()
+	composed := "Ελένη Müller ﬁ"
+	decomposed := "Ελε\u0301νη Mu\u0308ller ﬁ"
+
+	tests := []struct {
+		name     string
+		req      NormalizeRequest
+		expected string
+	}{
+		{"NFD decomposes", NormalizeRequest{Text: composed, Form: "NFD"}, decomposed},
+		{"NFC recomposes", NormalizeRequest{Text: decomposed, Form: "NFC"}, composed},
+		{"Default form is NFC", NormalizeRequest{Text: decomposed}, composed},
+		{"NFKC folds compatibility characters", NormalizeRequest{Text: composed, Form: "NFKC"}, "Ελένη Müller fi"},
+		{"NFKD folds and decomposes", NormalizeRequest{Text: composed, Form: "NFKD"}, "Ελε\u0301νη Mu\u0308ller fi"},
+		{"Remove diacritics", NormalizeRequest{Text: "Crème brûlée", Form: "NFD", RemoveDiacritics: true}, "Creme brulee"},
+		{"Case folding", NormalizeRequest{Text: "Crème", CaseFolding: true}, "crème"},
+		{"ASCII expands umlauts", NormalizeRequest{Text: "Müller Jörg Bär", ASCIIOnly: true}, "Mueller Joerg Baer"},
+		{"ASCII after NFD", NormalizeRequest{Text: "Müller", Form: "NFD", ASCIIOnly: true}, "Mueller"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Normalize(ctx, &tt.req)
+			if err != nil {
+				t.Fatalf("Normalize failed: %v", err)
+			}
+			if resp.Text != tt.expected {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.req.Text, resp.Text, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		for _, form := range []string{"NFC", "NFD", "NFKC", "NFKD"} {
+			first, err := Normalize(ctx, &NormalizeRequest{Text: composed, Form: form})
+			if err != nil {
+				t.Fatalf("Normalize(%s) failed: %v", form, err)
+			}
+			second, err := Normalize(ctx, &NormalizeRequest{Text: first.Text, Form: form})
+			if err != nil {
+				t.Fatalf("Normalize(%s) failed: %v", form, err)
+			}
+			if first.Text != second.Text || first.Form != form {
+				t.Errorf("%s is not idempotent: %q then %q", form, first.Text, second.Text)
+			}
+		}
+	})
+
+	t.Run("Invalid form rejected", func(t *testing.T) {
+		_, err := Normalize(ctx, &NormalizeRequest{Text: "Müller", Form: "NFX"})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidNormalizationForm)
+	})
+}
+
+// TestScoreEndpoint tests scoring conversions produced elsewhere
+func TestScoreEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	score := func(output string) *ScoreResponse {
+		t.Helper()
+		resp, err := Score(ctx, &ScoreRequest{InputText: "Москва", OutputText: output, InputScript: "cyrillic", OutputScript: "latin"})
+		if err != nil {
+			t.Fatalf("Score(%q) failed: %v", output, err)
+		}
+		if resp.ConfidenceScore != resp.ConfidenceFactors.Score {
+			t.Errorf("confidence_score %.2f differs from factors score %.2f", resp.ConfidenceScore, resp.ConfidenceFactors.Score)
+		}
+		return resp
+	}
+
+	good := score("Moskva")
+	poor := score("M?s??a")
+	if good.ConfidenceScore <= poor.ConfidenceScore {
+		t.Errorf("Good romanization scored %.2f, not above poor %.2f", good.ConfidenceScore, poor.ConfidenceScore)
+	}
+	if poor.ConfidenceFactors.Unmapped >= 0 {
+		t.Errorf("Expected a penalty for placeholders, got %.2f", poor.ConfidenceFactors.Unmapped)
+	}
+
+	t.Run("Matches transliteration", func(t *testing.T) {
+		for _, text := range []string{"Москва", "Щукин Ёжиков", "Ελένη"} {
+			converted, err := Transliterate(ctx, &TransliterationRequest{Text: text, OutputScript: "latin", Preview: true})
+			if err != nil {
+				t.Fatalf("Transliterate(%q) failed: %v", text, err)
+			}
+			scored, err := Score(ctx, &ScoreRequest{InputText: text, OutputText: converted.OutputText, InputScript: converted.InputScript, OutputScript: "latin"})
+			if err != nil {
+				t.Fatalf("Score(%q) failed: %v", text, err)
+			}
+			if *scored.ConfidenceFactors != *converted.ConfidenceFactors || scored.ConfidenceScore != *converted.ConfidenceScore {
+				t.Errorf("Score of %q = %.2f %+v, want the transliteration's %.2f %+v", text, scored.ConfidenceScore, *scored.ConfidenceFactors, *converted.ConfidenceScore, *converted.ConfidenceFactors)
+			}
+		}
+	})
+
+	t.Run("Invalid requests rejected", func(t *testing.T) {
+		_, err := Score(ctx, &ScoreRequest{InputText: "Москва", OutputScript: "latin", InputScript: "cyrillic"})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonTextEmpty)
+
+		_, err = Score(ctx, &ScoreRequest{InputText: "Москва", OutputText: "Moskva", OutputScript: "latin"})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonInputScriptRequired)
+
+		_, err = Score(ctx, &ScoreRequest{InputText: "Москва", OutputText: "Moskva", InputScript: "cyrillic", OutputScript: "greek"})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedScriptPair)
+	})
+}
+
+// TestGermanUmlautExpansion tests ae/oe/ue expansion versus folding of umlauts
+func TestGermanUmlautExpansion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expanded string
+		folded   string
+	}{
+		{"Müller", "Mueller", "Muller"},
+		{"Groß", "Gross", "Gross"},
+		{"Jürgen", "Juergen", "Jurgen"},
+		{"ÄRGER", "AERGER", "ARGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			for _, expand := range []bool{true, false} {
+				config := transliteration.DefaultConfig()
+				config.UseDatabase = false
+				config.UmlautExpansion = expand
+				engine := transliteration.NewEngine(config, nil)
+
+				result, err := engine.Transliterate(context.Background(), tt.input, "german", "ascii", "de")
+				if err != nil {
+					t.Fatalf("Transliterate failed: %v", err)
+				}
+				expected := tt.folded
+				if expand {
+					expected = tt.expanded
+				}
+				if result.Output != expected {
+					t.Errorf("Transliterate(%q) with expansion %v = %q, want %q", tt.input, expand, result.Output, expected)
+				}
+			}
+
+			// The normalization package shares the umlaut table, so the two can't diverge
+			ascii, err := textnorm.ToASCII(tt.input)
+			if err != nil {
+				t.Fatalf("ToASCII failed: %v", err)
+			}
+			if ascii != tt.expanded {
+				t.Errorf("ToASCII(%q) = %q, want %q", tt.input, ascii, tt.expanded)
+			}
+		})
+	}
+
+	german, finnish := "de-DE", "fi"
+	off, on := false, true
+	policies := []struct {
+		name      string
+		requested *bool
+		locale    *string
+		expected  bool
+	}{
+		{"German by default", nil, &german, true},
+		{"German turned off", &off, &german, false},
+		{"Other languages fold", &on, &finnish, false},
+		{"Unknown language folds", nil, nil, false},
+	}
+	for _, tt := range policies {
+		if got := expandsGermanUmlauts(tt.requested, tt.locale); got != tt.expected {
+			t.Errorf("%s: expandsGermanUmlauts = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+// TestPreviewDoesNotStore tests that preview requests leave the database untouched
+func TestPreviewDoesNotStore(t *testing.T) {
+	ctx := context.Background()
+	text := "Предпросмотр Иванов"
+
+	countRows := func() (rows, usage int) {
+		t.Helper()
+		err := db.QueryRow(ctx, `
+			SELECT COUNT(*), COALESCE(SUM(usage_count), 0) FROM transliterations WHERE input_text = $1
+		`, text).Scan(&rows, &usage)
+		if err != nil {
+			t.Fatalf("count query failed: %v", err)
+		}
+		return rows, usage
+	}
+
+	rowsBefore, usageBefore := countRows()
+
+	resp, err := Transliterate(ctx, &TransliterationRequest{
+		Text:         text,
+		InputScript:  "cyrillic",
+		OutputScript: "latin",
+		Preview:      true,
+	})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+
+	if !resp.Preview {
+		t.Error("Expected preview to be set on the response")
+	}
+	if !resp.FromCache && resp.ID != "" {
+		t.Errorf("Expected an uncached preview to have no ID, got %q", resp.ID)
+	}
+	if !strings.Contains(resp.OutputText, "Ivanov") {
+		t.Errorf("Expected output to contain 'Ivanov', got %q", resp.OutputText)
+	}
+	if resp.Name == nil {
+		t.Error("Expected name parsing on a preview")
+	}
+
+	if rowsAfter, usageAfter := countRows(); rowsAfter != rowsBefore || usageAfter != usageBefore {
+		t.Errorf("Preview changed the database: %d rows (usage %d) before, %d (usage %d) after", rowsBefore, usageBefore, rowsAfter, usageAfter)
+	}
+}
+
+// TestTransliterationOptionsHash tests the canonical cache key for request options
+func TestTransliterationOptionsHash(t *testing.T) {
+	enabled, disabled := true, false
+
+	defaults := []*TransliterationRequest{
+		{},
+		{BoundarySpacing: transliteration.BoundarySpacingSmart},
+		{LongVowels: transliteration.LongVowelsDoubled},
+		{GermanUmlautExpansion: &enabled},
+		{InlineOriginal: true, Preview: true, MaxLength: 50000}, // Don't change the stored output
+		{UnmappedPolicy: transliteration.UnmappedQuestion, OutputScript: "ascii"},
+		{UnmappedPolicy: transliteration.UnmappedKeep, OutputScript: "latin"},
+	}
+	for _, req := range defaults {
+		if hash := transliterationOptionsHash(req); hash != "" {
+			t.Errorf("Expected default options %+v to hash to \"\", got %q", req, hash)
+		}
+	}
+
+	variants := []*TransliterationRequest{
+		{Standard: "buckwalter"},
+		{NumberWords: true},
+		{BoundarySpacing: transliteration.BoundarySpacingAlways},
+		{LongVowels: transliteration.LongVowelsMacron},
+		{PreserveDiacritics: true},
+		{GermanUmlautExpansion: &disabled},
+		{UnmappedPolicy: transliteration.UnmappedDrop},
+	}
+	seen := map[string]bool{"": true}
+	for _, req := range variants {
+		hash := transliterationOptionsHash(req)
+		if seen[hash] {
+			t.Errorf("Options %+v collide with another options hash %q", req, hash)
+		}
+		seen[hash] = true
+		if len(hash) != 64 {
+			t.Errorf("Expected a SHA-256 hex digest, got %q", hash)
+		}
+	}
+
+	if transliterationOptionsHash(&TransliterationRequest{Standard: "buckwalter"}) != transliterationOptionsHash(&TransliterationRequest{Standard: "buckwalter", BoundarySpacing: transliteration.BoundarySpacingSmart}) {
+		t.Error("Expected explicit defaults not to change the options hash")
+	}
+}
+
+// TestCacheKeyedOnOptions tests that requests differing only in standard don't share a cached row
+func TestCacheKeyedOnOptions(t *testing.T) {
+	ctx := context.Background()
+	text := "محمد"
+
+	request := func(standard string) *TransliterationResponse {
+		t.Helper()
+		resp, err := Transliterate(ctx, &TransliterationRequest{
+			Text:         text,
+			InputScript:  "arabic",
+			OutputScript: "ascii",
+			Standard:     standard,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		return resp
+	}
+
+	// Prime the cache for both standards, then read each back
+	request("")
+	request("buckwalter")
+
+	standard := request("")
+	buckwalter := request("buckwalter")
+	if !standard.FromCache || !buckwalter.FromCache {
+		t.Errorf("Expected repeated requests to be cached, got from_cache %v and %v", standard.FromCache, buckwalter.FromCache)
+	}
+	if standard.ID == buckwalter.ID || standard.OutputText == buckwalter.OutputText {
+		t.Errorf("Expected separate cached results, got %q (%s) and %q (%s)", standard.OutputText, standard.ID, buckwalter.OutputText, buckwalter.ID)
+	}
+	if buckwalter.OutputText != "mHmd" {
+		t.Errorf("Expected buckwalter output 'mHmd', got %q", buckwalter.OutputText)
+	}
+}
+
+// TestCacheKeyedOnLocale tests that a result stored for one input_locale is never served for
+// another, or for a request without one, since the locale selects the scheme
+func TestCacheKeyedOnLocale(t *testing.T) {
+	ctx := context.Background()
+	text := fmt.Sprintf("Јелена %d", time.Now().UnixNano()%100000)
+
+	request := func(locale *string) *TransliterationResponse {
+		t.Helper()
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin", InputLocale: locale})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		return resp
+	}
+
+	serbian := request(stringPtr("sr-RS"))
+	if again := request(stringPtr("sr-RS")); !again.FromCache || again.ID != serbian.ID {
+		t.Errorf("Expected the sr-RS result to be served from cache, got %s (from_cache %v)", again.ID, again.FromCache)
+	}
+
+	unlocalized := request(nil)
+	if unlocalized.FromCache || unlocalized.ID == serbian.ID {
+		t.Errorf("Expected a request without input_locale not to be served the sr-RS result %s", serbian.ID)
+	}
+	if macedonian := request(stringPtr("mk-MK")); macedonian.ID == serbian.ID || macedonian.ID == unlocalized.ID {
+		t.Errorf("Expected a separate mk-MK result, got %s", macedonian.ID)
+	}
+}
+
+// TestStaleTransliteration tests when a cached transliteration is recomputed rather than served
+func TestStaleTransliteration(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name              string
+		computedAt        time.Time
+		mappingsUpdatedAt *time.Time
+		ttl               time.Duration
+		expected          bool
+	}{
+		{"Fresh, no mappings", hourAgo, nil, 24 * time.Hour, false},
+		{"Computed after the latest mapping change", hourAgo, &yesterday, 24 * time.Hour, false},
+		{"Computed before a mapping change", yesterday, &hourAgo, 0, true},
+		{"Older than the TTL", now.Add(-48 * time.Hour), nil, 24 * time.Hour, true},
+		{"Old but expiry disabled", now.Add(-48 * time.Hour), nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStaleTransliteration(tt.computedAt, tt.mappingsUpdatedAt, tt.ttl, now); got != tt.expected {
+				t.Errorf("isStaleTransliteration = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCacheRefresh tests that stale cached rows are recomputed and updated in place
+func TestCacheRefresh(t *testing.T) {
+	ctx := context.Background()
+	request := func(text string) *TransliterationResponse {
+		t.Helper()
+		resp, err := Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin"})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name       string
+		computedAt string // SQL expression for the row's new computation time
+	}{
+		{"Older than the TTL", "NOW() - INTERVAL '400 days'"},
+		{"Computed before a mapping change", "(SELECT MAX(updated_at) FROM character_mappings WHERE source_script = 'cyrillic' AND target_script = 'latin') - INTERVAL '1 second'"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := fmt.Sprintf("Фёдор Белов %d%d", i, time.Now().UnixNano()%100000)
+			created := request(text)
+
+			// Corrupt the stored output and age the row, as if computed with older mappings
+			_, err := db.Exec(ctx, `
+				UPDATE transliterations SET output_text = 'stale', computed_at = `+tt.computedAt+`
+				WHERE id = $1
+			`, created.ID)
+			if err != nil {
+				t.Fatalf("Failed to age cached row: %v", err)
+			}
+
+			refreshed := request(text)
+			if refreshed.ID != created.ID {
+				t.Errorf("Expected the stale row %s to be updated, got %s", created.ID, refreshed.ID)
+			}
+			if refreshed.FromCache || refreshed.OutputText != created.OutputText {
+				t.Errorf("Expected a recomputed %q, got %q (from_cache %v)", created.OutputText, refreshed.OutputText, refreshed.FromCache)
+			}
+
+			cached := request(text)
+			if !cached.FromCache || cached.ID != created.ID || cached.OutputText != created.OutputText {
+				t.Errorf("Expected the refreshed row to be served from cache, got %q (%s, from_cache %v)", cached.OutputText, cached.ID, cached.FromCache)
+			}
+		})
+	}
+}
+
+// TestRescoreTransliteration tests recomputing a stored row's confidence with the current rules
+func TestRescoreTransliteration(t *testing.T) {
+	ctx := context.Background()
+	row := storedTransliteration{InputText: "Иван Петров", OutputText: "Ivan Petrov", InputScript: "cyrillic", OutputScript: "latin"}
+
+	factors, characterConfidence, changed, err := rescoreTransliteration(ctx, row)
+	if err != nil {
+		t.Fatalf("rescoreTransliteration failed: %v", err)
+	}
+	if changed {
+		t.Error("Expected unchanged output not to be flagged")
+	}
+	if factors.Score <= 0.5 || factors.Score > 1 {
+		t.Errorf("Expected a recomputed confidence above 0.5, got %f", factors.Score)
+	}
+	if want := calculateConfidence(row.InputText, row.OutputText, row.InputScript, row.OutputScript, characterConfidence); factors != want {
+		t.Errorf("Expected the factors a transliteration would get, %+v, got %+v", want, factors)
+	}
+
+	row.OutputText = "Iwan Petroff"
+	if _, _, changed, err := rescoreTransliteration(ctx, row); err != nil || !changed {
+		t.Errorf("Expected differing output to be flagged, got changed %v (err %v)", changed, err)
+	}
+}
+
+// TestRescoreRowFailures tests that a row failing to rescore or update is logged, skipped and
+// still stamped as rescored
+func TestRescoreRowFailures(t *testing.T) {
+	ctx := context.Background()
+	updateErr := errors.New("connection reset")
+	tests := []struct {
+		name string
+		row  storedTransliteration
+		msg  string
+	}{
+		{"Rescore", storedTransliteration{ID: "1", InputText: "Ив\xffан", OutputText: "Ivan", InputScript: "cyrillic", OutputScript: "latin"}, "failed to rescore transliteration"},
+		{"Update", storedTransliteration{ID: "2", InputText: "Иван", OutputText: "Ivan", InputScript: "cyrillic", OutputScript: "latin"}, "failed to update rescored transliteration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := useLogger(t)
+			if _, ok := rescoreRow(ctx, failingExecer{err: updateErr}, tt.row); ok {
+				t.Fatal("Expected the row to be skipped")
+			}
+
+			if len(recorder.entries) != 2 {
+				t.Fatalf("Expected the failure and the stamp to be logged, got %+v", recorder.entries)
+			}
+			failure, stamp := recorder.entries[0], recorder.entries[1]
+			if failure.msg != tt.msg || failure.value("transliteration_id") != tt.row.ID {
+				t.Errorf("Expected %q for %s, got %q %v", tt.msg, tt.row.ID, failure.msg, failure.keysAndValues)
+			}
+			if stamp.msg != "failed to mark transliteration rescored" || stamp.value("err") != updateErr {
+				t.Errorf("Expected rescored_at to be stamped, got %q %v", stamp.msg, stamp.keysAndValues)
+			}
+		})
+	}
+}
+
+// TestRescoreTransliterations tests that the cron job updates the score of a stale stored row
+func TestRescoreTransliterations(t *testing.T) {
+	ctx := context.Background()
+	text := fmt.Sprintf("Олег Соколов %d", time.Now().UnixNano()%100000)
+
+	// A row scored and converted under older rules
+	var id string
+	err := db.QueryRow(ctx, `
+		INSERT INTO transliterations (input_text, output_text, input_script, output_script, confidence_score)
+		VALUES ($1, 'stale', 'cyrillic', 'latin', 0.00)
+		RETURNING id
+	`, text).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to insert stale row: %v", err)
+	}
+	defer db.Exec(ctx, `DELETE FROM transliterations WHERE id = $1`, id)
+
+	resp, err := RescoreTransliterations(ctx)
+	if err != nil {
+		t.Fatalf("RescoreTransliterations failed: %v", err)
+	}
+	if resp.Rescored == 0 || resp.Flagged == 0 {
+		t.Errorf("Expected rows to be rescored and flagged, got %+v", resp)
+	}
+
+	var confidence float64
+	var output string
+	var needsReview, rescored bool
+	err = db.QueryRow(ctx, `
+		SELECT confidence_score, output_text, needs_review, rescored_at IS NOT NULL
+		FROM transliterations WHERE id = $1
+	`, id).Scan(&confidence, &output, &needsReview, &rescored)
+	if err != nil {
+		t.Fatalf("Failed to read rescored row: %v", err)
+	}
+	if confidence <= 0 || !rescored {
+		t.Errorf("Expected the score to be updated, got %.2f (rescored %v)", confidence, rescored)
+	}
+	if !needsReview || output != "stale" {
+		t.Errorf("Expected the changed row to be flagged with its output kept, got %q (needs_review %v)", output, needsReview)
+	}
+}
+
+// TestConfidenceHistogram tests bucketed confidence counts, overall and per input script
+func TestConfidenceHistogram(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := GetConfidenceHistogram(ctx, &ConfidenceHistogramParams{InputScript: "klingon"})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedInputScript)
+
+	histogram := func(inputScript string) *ConfidenceHistogram {
+		t.Helper()
+		result, err := GetConfidenceHistogram(ctx, &ConfidenceHistogramParams{InputScript: inputScript})
+		if err != nil {
+			t.Fatalf("GetConfidenceHistogram failed: %v", err)
+		}
+		if len(result.Buckets) != confidenceBuckets {
+			t.Fatalf("Expected %d buckets, got %d", confidenceBuckets, len(result.Buckets))
+		}
+		return result
+	}
+	allBefore, greekBefore := histogram(""), histogram("greek")
+
+	// Scores on each side of the bucket edges, plus a perfect and a missing score
+	seed := []struct {
+		script string
+		score  *float64
+	}{
+		{"latin", floatPtr(0.1)}, {"latin", floatPtr(0.2)}, {"latin", floatPtr(0.39)},
+		{"latin", floatPtr(0.5)}, {"latin", floatPtr(0.75)}, {"latin", floatPtr(1.0)},
+		{"latin", nil}, {"greek", floatPtr(0.95)},
+	}
+	for i, row := range seed {
+		_, err := db.Exec(ctx, `
+			INSERT INTO transliterations (input_text, output_text, input_script, output_script, confidence_score)
+			VALUES ($1, $1, $2, 'ascii', $3)
+		`, fmt.Sprintf("histogram-seed-%d", i), row.script, row.score)
+		if err != nil {
+			t.Fatalf("seeding row %d failed: %v", i, err)
+		}
+	}
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM transliterations WHERE input_text LIKE 'histogram-seed-%'`)
+	})
+
+	allAfter, greekAfter := histogram(""), histogram("greek")
+	wantAll := []int64{1, 2, 1, 1, 2}
+	for i, want := range wantAll {
+		if got := allAfter.Buckets[i].Count - allBefore.Buckets[i].Count; got != want {
+			t.Errorf("Expected %d more rows in bucket %.1f-%.1f, got %d", want, allAfter.Buckets[i].Min, allAfter.Buckets[i].Max, got)
+		}
+	}
+	if got := allAfter.Unscored - allBefore.Unscored; got != 1 {
+		t.Errorf("Expected 1 more unscored row, got %d", got)
+	}
+	if got := allAfter.Total - allBefore.Total; got != int64(len(seed)) {
+		t.Errorf("Expected %d more rows in total, got %d", len(seed), got)
+	}
+
+	wantGreek := []int64{0, 0, 0, 0, 1}
+	for i, want := range wantGreek {
+		if got := greekAfter.Buckets[i].Count - greekBefore.Buckets[i].Count; got != want {
+			t.Errorf("Expected %d more greek rows in bucket %.1f-%.1f, got %d", want, greekAfter.Buckets[i].Min, greekAfter.Buckets[i].Max, got)
+		}
+	}
+	if greekAfter.InputScript != "greek" {
+		t.Errorf("Expected the filter to be echoed, got %q", greekAfter.InputScript)
+	}
+}
+
+// TestLookupTransliteration tests the read-only cache probe before and after a POST
+func TestLookupTransliteration(t *testing.T) {
+	ctx := context.Background()
+	// A name unlikely to be stored by other tests, so the first lookup misses
+	text := fmt.Sprintf("Зоя Лукина %d", time.Now().UnixNano()%100000)
+	params := &LookupParams{Text: text, InputScript: "cyrillic", OutputScript: "latin"}
+
+	_, err := LookupTransliteration(ctx, params)
+	assertErrorReason(t, err, errs.NotFound, ReasonNotFound)
+
+	created, err := Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin"})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+
+	found, err := LookupTransliteration(ctx, params)
+	if err != nil {
+		t.Fatalf("LookupTransliteration failed: %v", err)
+	}
+	if found.ID != created.ID || found.OutputText != created.OutputText {
+		t.Errorf("Lookup returned %s (%q), want %s (%q)", found.ID, found.OutputText, created.ID, created.OutputText)
+	}
+	if !found.FromCache || found.Name == nil {
+		t.Errorf("Expected a cached result with name parsing, got from_cache %v and name %v", found.FromCache, found.Name)
+	}
+
+	// Auto-detection finds the same row
+	detected, err := LookupTransliteration(ctx, &LookupParams{Text: text, OutputScript: "latin"})
+	if err != nil {
+		t.Fatalf("LookupTransliteration failed: %v", err)
+	}
+	if detected.ID != created.ID {
+		t.Errorf("Lookup with detected script returned %s, want %s", detected.ID, created.ID)
+	}
+}
+
+// TestCacheOnly tests that cache_only serves cached results and misses without converting
+func TestCacheOnly(t *testing.T) {
+	ctx := context.Background()
+	// A name unlikely to be stored by other tests, so the first request misses
+	text := fmt.Sprintf("Нина Орлова %d", time.Now().UnixNano()%100000)
+	req := &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin", CacheOnly: true}
+
+	_, err := Transliterate(ctx, req)
+	assertErrorReason(t, err, errs.NotFound, ReasonCacheMiss)
+
+	// The miss stored nothing
+	_, err = LookupTransliteration(ctx, &LookupParams{Text: text, InputScript: "cyrillic", OutputScript: "latin"})
+	assertErrorReason(t, err, errs.NotFound, ReasonNotFound)
+
+	created, err := Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin"})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+
+	hit, err := Transliterate(ctx, req)
+	if err != nil {
+		t.Fatalf("Transliterate with cache_only failed: %v", err)
+	}
+	if hit.ID != created.ID || hit.OutputText != created.OutputText || !hit.FromCache {
+		t.Errorf("Expected cached %s (%q), got %s (%q) with from_cache %v", created.ID, created.OutputText, hit.ID, hit.OutputText, hit.FromCache)
+	}
+
+	// Options change the cache key, so the same text with other options misses
+	_, err = Transliterate(ctx, &TransliterationRequest{Text: text, InputScript: "cyrillic", OutputScript: "latin", NumberWords: true, CacheOnly: true})
+	assertErrorReason(t, err, errs.NotFound, ReasonCacheMiss)
+}
+
+// TestLookupValidation tests the cache lookup parameter validation
+func TestLookupValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         *LookupParams
+		expectedReason string
+	}{
+		{"Missing params", nil, ReasonRequestMissing},
+		{"Empty text", &LookupParams{Text: " ", OutputScript: "latin"}, ReasonTextEmpty},
+		{"Text longer than any cached input", &LookupParams{Text: strings.Repeat("a", maxCachedTextLength+1), OutputScript: "latin"}, ReasonTextTooLong},
+		{"Missing output script", &LookupParams{Text: "Иван"}, ReasonOutputScriptRequired},
+		{"Unsupported input script", &LookupParams{Text: "Иван", InputScript: "klingon", OutputScript: "latin"}, ReasonUnsupportedInputScript},
+		{"Invalid locale", &LookupParams{Text: "Иван", OutputScript: "latin", Locale: "not a locale"}, ReasonInvalidLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLookupParams(tt.params)
+			assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+		})
+	}
+
+	if err := validateLookupParams(&LookupParams{Text: "Иван", OutputScript: "latin", Locale: "ru-RU"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestThaiLeadingVowels tests that leading vowels are read after their consonant
+func TestThaiLeadingVowels(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Sara e", "เก", "ke"},
+		{"Sara ae with tone mark", "แม่", "mae"},
+		{"Sara ai mai muan", "ใจ", "chai"},
+		{"Leading vowel mid-word", "กรุงเทพ", "krungtheph"},
+		{"Leading vowel before a cluster", "เพลง", "phleng"},
+		{"Vowel without a consonant", "เ", "e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "thai", "latin", "th")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestInternalErrors tests that the cause of an internal or unavailable error is logged and
+// kept out of the message returned to clients
+func TestInternalErrors(t *testing.T) {
+	cause := errors.New(`pq: relation "transliterations" does not exist`)
+	tests := []struct {
+		name         string
+		err          func() error
+		expectedCode errs.ErrCode
+		reason       string
+		message      string
+	}{
+		{
+			name:         "Internal",
+			err:          func() error { return internalError(ReasonDatabaseError, cause, "failed to list transliterations") },
+			expectedCode: errs.Internal,
+			reason:       ReasonDatabaseError,
+			message:      "failed to list transliterations",
+		},
+		{
+			name:         "Unavailable",
+			err:          func() error { return unavailable(ReasonDatabaseUnavailable, cause, "database unreachable") },
+			expectedCode: errs.Unavailable,
+			reason:       ReasonDatabaseUnavailable,
+			message:      "database unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := useLogger(t)
+			err := tt.err()
+
+			assertErrorReason(t, err, tt.expectedCode, tt.reason)
+			var apiErr *errs.Error
+			if errors.As(err, &apiErr) && apiErr.Message != tt.message {
+				t.Errorf("Expected only the message %q, got %q", tt.message, apiErr.Message)
+			}
+			if len(recorder.entries) != 1 || recorder.entries[0].level != "error" || recorder.entries[0].value("err") != cause {
+				t.Errorf("Expected the cause to be logged, got %+v", recorder.entries)
+			}
+		})
+	}
+}
+
+// TestHealthz tests the health check against the test database
+func TestHealthz(t *testing.T) {
+	resp, err := Healthz(context.Background())
+	if err != nil {
+		t.Fatalf("Healthz failed: %v", err)
+	}
+	if resp.Status != "ok" || resp.DB != "up" {
+		t.Errorf("Expected status ok and db up, got %+v", resp)
+	}
+}
+
+// TestImportMappingsValidation tests that invalid mapping rows are rejected with their reasons
+func TestImportMappingsValidation(t *testing.T) {
+	_, err := ImportMappings(context.Background(), &ImportMappingsRequest{})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonRequestMissing)
+
+	tooMany := make([]CharacterMapping, maxImportMappings+1)
+	_, err = ImportMappings(context.Background(), &ImportMappingsRequest{Mappings: tooMany})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonTooManyMappings)
+
+	heavy := 1.5
+	badLocale := "not a locale"
+	resp, err := ImportMappings(context.Background(), &ImportMappingsRequest{Mappings: []CharacterMapping{
+		{SourceChar: "ѵѵ", SourceScript: "cyrillic", TargetChar: "i", TargetScript: "latin"},
+		{SourceChar: "ѵ", SourceScript: "klingon", TargetChar: " ", TargetScript: "latin"},
+		{SourceChar: "ѵ", SourceScript: "cyrillic", TargetChar: "i", TargetScript: "latin", Locale: &badLocale, FrequencyWeight: &heavy},
+	}})
+	if err != nil {
+		t.Fatalf("ImportMappings failed: %v", err)
+	}
+	if resp.Inserted != 0 || resp.Updated != 0 || resp.Rejected != 3 {
+		t.Errorf("Expected 3 rejected rows, got %+v", resp)
+	}
+
+	fields := make([]string, len(resp.Errors))
+	for i, fieldErr := range resp.Errors {
+		fields[i] = fieldErr.Field
+	}
+	expected := []string{
+		"mappings[0].source_char",
+		"mappings[1].target_char", "mappings[1].source_script",
+		"mappings[2].locale", "mappings[2].frequency_weight",
+	}
+	if !slices.Equal(fields, expected) {
+		t.Errorf("Rejected fields = %v, want %v", fields, expected)
+	}
+}
+
+// TestImportMappings tests that imported mappings are used by later transliterations
+func TestImportMappings(t *testing.T) {
+	ctx := context.Background()
+	weight := 0.99
+	mapping := CharacterMapping{SourceChar: "ѵ", SourceScript: "cyrillic", TargetChar: "i", TargetScript: "latin", FrequencyWeight: &weight}
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM character_mappings WHERE source_char = 'ѵ' AND source_script = 'cyrillic'`)
+	})
+
+	resp, err := ImportMappings(ctx, &ImportMappingsRequest{Mappings: []CharacterMapping{mapping}})
+	if err != nil {
+		t.Fatalf("ImportMappings failed: %v", err)
+	}
+	if resp.Inserted+resp.Updated != 1 || resp.Rejected != 0 {
+		t.Errorf("Expected one imported row, got %+v", resp)
+	}
+
+	result, err := Transliterate(ctx, &TransliterationRequest{Text: "мѵро", InputScript: "cyrillic", OutputScript: "latin"})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if result.OutputText != "miro" {
+		t.Errorf("Expected imported mapping in output 'miro', got %q", result.OutputText)
+	}
+
+	// Importing the same mapping again replaces it rather than adding a duplicate
+	resp, err = ImportMappings(ctx, &ImportMappingsRequest{Mappings: []CharacterMapping{mapping}})
+	if err != nil {
+		t.Fatalf("ImportMappings failed: %v", err)
+	}
+	if resp.Updated != 1 || resp.Inserted != 0 {
+		t.Errorf("Expected re-import to update, got %+v", resp)
+	}
+}
+
+// TestAdminAuth tests that the admin token is required and compared exactly
+func TestAdminAuth(t *testing.T) {
+	original := secrets.AdminToken
+	t.Cleanup(func() { secrets.AdminToken = original })
+
+	secrets.AdminToken = ""
+	_, err := AuthHandler(context.Background(), "")
+	assertErrorReason(t, err, errs.Unauthenticated, ReasonInvalidToken)
+
+	secrets.AdminToken = "s3cret"
+	_, err = AuthHandler(context.Background(), "s3cre")
+	assertErrorReason(t, err, errs.Unauthenticated, ReasonInvalidToken)
+
+	if uid, err := AuthHandler(context.Background(), "s3cret"); err != nil || uid != adminUID {
+		t.Errorf("Expected admin UID for the configured token, got %q, %v", uid, err)
+	}
+}
+
+// TestDecomposedInput tests that NFD input transliterates exactly like its NFC form
+func TestDecomposedInput(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name       string
+		nfc        string
+		nfd        string
+		fromScript string
+		toScript   string
+	}{
+		{"Vietnamese", "Nguyễn Văn Minh", "Nguye\u0302\u0303n Va\u0306n Minh", "latin", "ascii"},
+		{"German", "Jürgen", "Ju\u0308rgen", "latin", "ascii"},
+		{"Cyrillic short i", "Андрей", "Андре\u0438\u0306", "cyrillic", "latin"},
+		{"Greek tonos", "Σοφία", "Σοφι\u0301α", "greek", "latin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.nfc == tt.nfd {
+				t.Fatal("Test input is not decomposed")
+			}
+			composed, err := engine.Transliterate(context.Background(), tt.nfc, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			decomposed, err := engine.Transliterate(context.Background(), tt.nfd, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if decomposed.Output != composed.Output {
+				t.Errorf("NFD output %q, NFC output %q", decomposed.Output, composed.Output)
+			}
+			if decomposed.Confidence != composed.Confidence {
+				t.Errorf("NFD confidence %f, NFC confidence %f", decomposed.Confidence, composed.Confidence)
+			}
+		})
+	}
+
+	if textnorm.ComposeNFC("Nguye\u0302\u0303n") != "Nguyễn" {
+		t.Error("Expected ComposeNFC to precompose Vietnamese diacritics")
+	}
+}
+
+// TestGraphemeClusters tests that combining marks without a precomposed form convert with
+// the letter they follow instead of one by one
+func TestGraphemeClusters(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name       string
+		text       string
+		fromScript string
+		toScript   string
+		expected   string
+	}{
+		{"Tilde on q to ASCII", "q\u0303", "latin", "ascii", "q"},
+		{"Tilde on q kept in Latin", "q\u0303", "latin", "latin", "q\u0303"},
+		{"Stacked marks", "Ngu\u031b\u0323\u0301", "latin", "ascii", "Ngu"},
+		{"Dot below and acute", "x\u0323\u0301", "latin", "ascii", "x"},
+		{"Cyrillic stress mark", "Пу\u0301тин", "cyrillic", "latin", "Putin"},
+		{"Greek psili", "α\u0313", "greek", "ascii", "a"},
+		{"Arabic harakat", "مُحَمَّد", "arabic", "latin", "mhmd"},
+		{"Thai vowel sign", "กิ", "thai", "latin", "ki"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.text, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.text, result.Output, tt.expected)
+			}
+			if strings.Contains(result.Output, "?") {
+				t.Errorf("Combining mark converted to a placeholder: %q", result.Output)
+			}
+		})
+	}
+
+	// Dropping an accent in ASCII output is as uncertain as folding a precomposed letter
+	precomposed, _ := engine.Transliterate(context.Background(), "ñ", "latin", "ascii", "")
+	decomposed, _ := engine.Transliterate(context.Background(), "q\u0303", "latin", "ascii", "")
+	if decomposed.Confidence != precomposed.Confidence {
+		t.Errorf("Expected confidence %f for a dropped mark, got %f", precomposed.Confidence, decomposed.Confidence)
+	}
+
+	// A mark with no letter before it is still unmapped
+	if result, _ := engine.Transliterate(context.Background(), "\u0301", "latin", "ascii", ""); result.Output != "?" {
+		t.Errorf("Expected a lone combining mark to be unmapped, got %q", result.Output)
+	}
+
+	// Preserved diacritics carry Latin accents across to the converted letter
+	config.PreserveDiacritics = true
+	preserving := transliteration.NewEngine(config, nil)
+	if result, _ := preserving.Transliterate(context.Background(), "Пу\u0301тин", "cyrillic", "latin", ""); result.Output != "Pútin" {
+		t.Errorf("Expected the stress mark preserved as Pútin, got %q", result.Output)
+	}
+}
+
+// TestNumeralsAndCurrency tests digits of other numeral systems and currency symbols
+func TestNumeralsAndCurrency(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name       string
+		input      string
+		fromScript string
+		toScript   string
+		expected   string
+	}{
+		{"Arabic-Indic digits", "١٢٣", "arabic", "ascii", "123"},
+		{"Arabic-Indic digits to latin", "١٢٣", "arabic", "latin", "123"},
+		{"Persian digits", "۴۵۶", "arabic", "ascii", "456"},
+		{"Full-width digits", "１２３", "latin", "ascii", "123"},
+		{"Devanagari digits", "२०२५", "latin", "ascii", "2025"},
+		{"Thai digits", "๑๒๓", "thai", "latin", "123"},
+		{"Euro", "€100", "latin", "ascii", "EUR100"},
+		{"Pound and yen", "£5 ¥7", "latin", "ascii", "GBP5 JPY7"},
+		{"Rupee and rouble", "₹9 ₽3", "latin", "ascii", "INR9 RUB3"},
+		{"ASCII dollar kept", "$20", "latin", "ascii", "$20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+}
+
+// TestVerboseSegments tests that traced segments reconstruct the output and record each method
+func TestVerboseSegments(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	config.Trace = true
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name       string
+		input      string
+		fromScript string
+		toScript   string
+	}{
+		{"Cyrillic words", "Привет, мир", "cyrillic", "latin"},
+		{"Boundary space", "東京Tower", "chinese", "latin"},
+		{"Japanese digraph and long vowel", "きょうとー", "japanese", "latin"},
+		{"Greek digraphs", "Philosophia", "latin", "greek"},
+		{"ASCII fallback", "Jürgen ☃", "latin", "ascii"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, tt.fromScript, tt.toScript, "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if len(result.Segments) == 0 {
+				t.Fatal("Expected segments when tracing")
+			}
+
+			var output strings.Builder
+			for _, segment := range result.Segments {
+				if segment.Method == "" {
+					t.Errorf("Segment %+v has no method", segment)
+				}
+				output.WriteString(segment.Output)
+			}
+			if output.String() != result.Output {
+				t.Errorf("Segments reconstruct %q, want %q", output.String(), result.Output)
+			}
+		})
+	}
+
+	result, err := engine.Transliterate(context.Background(), "東京Tower", "chinese", "ascii", "")
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	methods := make(map[string]string)
+	for _, segment := range result.Segments {
+		methods[segment.Source] = segment.Method
+	}
+	if methods["T"] != "passthrough" || methods[""] != "boundary" || methods["東"] != "fallback" {
+		t.Errorf("Unexpected segment methods: %v", methods)
+	}
+
+	untraced, err := transliteration.NewEngine(transliteration.DefaultConfig(), nil).Transliterate(context.Background(), "мир", "cyrillic", "latin", "")
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if untraced.Segments != nil {
+		t.Errorf("Expected no segments without tracing, got %v", untraced.Segments)
+	}
+}
+
+// TestDetectionDetails tests the optional script detection details in the response
+func TestDetectionDetails(t *testing.T) {
+	ctx := context.Background()
+	request := func(include bool) *TransliterationResponse {
+		t.Helper()
+		resp, err := Transliterate(ctx, &TransliterationRequest{
+			Text:                    "Hello мир",
+			OutputScript:            "ascii",
+			IncludeDetectionDetails: include,
+			Preview:                 true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := request(true)
+	if resp.Detection == nil {
+		t.Fatal("Expected detection details")
+	}
+	if resp.Detection.Script != resp.InputScript {
+		t.Errorf("Expected detected script %q to match input_script %q", resp.Detection.Script, resp.InputScript)
+	}
+	if resp.Detection.Details["latin"] != 5 || resp.Detection.Details["cyrillic"] != 3 {
+		t.Errorf("Expected 5 latin and 3 cyrillic letters, got %v", resp.Detection.Details)
+	}
+	if resp.Detection.Confidence <= 0 || resp.Detection.Confidence > 1 {
+		t.Errorf("Expected detection confidence in (0, 1], got %v", resp.Detection.Confidence)
+	}
+
+	if resp := request(false); resp.Detection != nil {
+		t.Errorf("Expected no detection details unless requested, got %+v", resp.Detection)
+	}
+}
+
+// TestRomanizedHonorifics tests that -san, -ssi and similar are dropped rather than kept as
+// names or mapped to Western titles
+func TestRomanizedHonorifics(t *testing.T) {
+	tests := []struct {
+		input     string
+		locale    string
+		output    string
+		fullASCII string
+	}{
+		{"Tanaka-san Yoko", "ja", "Tanaka Yoko", "TANAKA Yoko"},
+		{"Suzuki-SAMA Haruto", "ja", "Suzuki Haruto", "SUZUKI Haruto"},
+		{"Kim-ssi Minjun", "ko", "Kim Minjun", "KIM Minjun"},
+		{"Park-nim Jisoo", "ko", "Park Jisoo", "PARK Jisoo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			locale := tt.locale
+			resp, err := Transliterate(context.Background(), &TransliterationRequest{
+				Text:         tt.input,
+				OutputScript: "ascii",
+				InputLocale:  &locale,
+				Preview:      true,
+			})
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.OutputText != tt.output {
+				t.Errorf("Expected output %q, got %q", tt.output, resp.OutputText)
+			}
+			if resp.Name == nil {
+				t.Fatal("Expected name parsing")
+			}
+			if resp.Name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected full ASCII %q, got %q", tt.fullASCII, resp.Name.FullASCII)
+			}
+			if len(resp.Name.Titles) != 0 {
+				t.Errorf("Expected no titles for a politeness honorific, got %v", resp.Name.Titles)
+			}
+		})
+	}
+
+	if name, honorifics := nameparser.ExtractRomanizedHonorifics("Tanaka-kun"); name != "Tanaka" || len(honorifics) != 1 || honorifics[0].Gender != "M" {
+		t.Errorf("Expected Tanaka with a male -kun honorific, got %q %+v", name, honorifics)
+	}
+	if name, honorifics := nameparser.ExtractRomanizedHonorifics("Sana -san San"); name != "Sana -san San" || len(honorifics) != 0 {
+		t.Errorf("Expected words without a name before the hyphen to be kept, got %q %+v", name, honorifics)
+	}
+}
+
+// TestPhoneticKeys tests that sound-alike spellings of a name share a phonetic key
+func TestPhoneticKeys(t *testing.T) {
+	keys := func(text, inputScript, outputScript string) *PhoneticKeys {
+		t.Helper()
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:         text,
+			InputScript:  inputScript,
+			OutputScript: outputScript,
+			Preview:      true,
+		})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		return resp.PhoneticKeys
+	}
+
+	catherine, katherine, christopher := keys("Catherine Smith", "latin", "ascii"), keys("Katherine Smyth", "latin", "ascii"), keys("Christopher Smith", "latin", "ascii")
+	if catherine == nil || katherine == nil || christopher == nil {
+		t.Fatal("Expected phonetic keys for Latin output")
+	}
+	if catherine.First != katherine.First {
+		t.Errorf("Expected Catherine and Katherine to share a key, got %q and %q", catherine.First, katherine.First)
+	}
+	if catherine.Family != katherine.Family {
+		t.Errorf("Expected Smith and Smyth to share a key, got %q and %q", catherine.Family, katherine.Family)
+	}
+	if catherine.First == christopher.First {
+		t.Errorf("Expected Catherine and Christopher to have different keys, both got %q", catherine.First)
+	}
+
+	if resp := keys("Ирина Петрова", "cyrillic", "latin"); resp == nil || resp.First != "IRN" || resp.Family != "PTRF" {
+		t.Errorf("Expected keys of the romanized Cyrillic name, got %+v", resp)
+	}
+	if resp := keys("Catherine Smith", "latin", "greek"); resp != nil {
+		t.Errorf("Expected no phonetic keys for Greek output, got %+v", resp)
+	}
+
+	if key := phoneticKey("García-López"); key != "KRX LPS" {
+		t.Errorf("Expected a key per word of a compound name, got %q", key)
+	}
+}
+
+// TestRespectInputCase tests that internal capitals typed in the input survive parsing only
+// when requested, and only when the input carries them
+func TestRespectInputCase(t *testing.T) {
+	tests := []struct {
+		input     string
+		respect   bool
+		first     string
+		family    string
+		fullASCII string
+	}{
+		{"Douglas MacArthur", true, "Douglas", "MacArthur", "Douglas MacArthur"},
+		{"douglas macarthur", true, "Douglas", "MACARTHUR", "Douglas MACARTHUR"},
+		{"Douglas MacArthur", false, "Douglas", "MACARTHUR", "Douglas MACARTHUR"},
+		{"Ronald McDONALD", true, "Ronald", "McDONALD", "Ronald McDONALD"},
+		{"JOHN SMITH", true, "John", "SMITH", "John SMITH"},
+		{"john smith", true, "John", "SMITH", "John SMITH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.input, tt.respect), func(t *testing.T) {
+			resp, err := ParseName(context.Background(), &ParseNameRequest{Text: tt.input, RespectInputCase: tt.respect})
+			if err != nil {
+				t.Fatalf("ParseName failed: %v", err)
+			}
+			if resp.Name.First != tt.first || resp.Name.Family != tt.family {
+				t.Errorf("Expected %q %q, got %q %q", tt.first, tt.family, resp.Name.First, resp.Name.Family)
+			}
+			if resp.Name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected full ASCII %q, got %q", tt.fullASCII, resp.Name.FullASCII)
+			}
+		})
+	}
+
+	resp, err := Transliterate(context.Background(), &TransliterationRequest{
+		Text:             "Douglas MacArthur",
+		InputScript:      "latin",
+		OutputScript:     "ascii",
+		RespectInputCase: true,
+		NameFormat:       nameparser.FormatSortable,
+		Preview:          true,
+	})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if resp.Name.FullASCII != "MacArthur, Douglas" {
+		t.Errorf("Expected the sortable format to keep the input case, got %q", resp.Name.FullASCII)
+	}
+}
+
+// TestInferGenderOption tests turning gender inference off per request and per deployment
+func TestInferGenderOption(t *testing.T) {
+	off, on := false, true
+
+	hasGender := func(t *testing.T, response any) bool {
+		t.Helper()
+		body, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		_, ok := fields["gender"]
+		return ok
+	}
+
+	t.Run("Transliterate", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Анна Волкова", OutputScript: "latin", InferGender: &off, Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.Gender != nil || hasGender(t, resp) {
+			t.Errorf("Expected no gender with infer_gender false, got %+v", resp.Gender)
+		}
+		if resp.Name == nil || resp.Name.Family != "VOLKOVA" {
+			t.Errorf("Expected the name to be parsed regardless, got %+v", resp.Name)
+		}
+
+		resp, err = Transliterate(context.Background(), &TransliterationRequest{Text: "Анна Волкова", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.Gender == nil || !hasGender(t, resp) {
+			t.Error("Expected gender by default")
+		}
+	})
+
+	t.Run("ParseName", func(t *testing.T) {
+		resp, err := ParseName(context.Background(), &ParseNameRequest{Text: "Mrs Jane Smith", InferGender: &off})
+		if err != nil {
+			t.Fatalf("ParseName failed: %v", err)
+		}
+		if resp.Gender != nil || hasGender(t, resp) {
+			t.Errorf("Expected no gender with infer_gender false, got %+v", resp.Gender)
+		}
+	})
+
+	t.Run("Distribution needs inference", func(t *testing.T) {
+		req := &TransliterationRequest{Text: "Anna", OutputScript: "ascii", InferGender: &off, GenderDistribution: true}
+		assertErrorReason(t, validateTransliterationRequest(req), errs.InvalidArgument, ReasonGenderInferenceDisabled)
+	})
+
+	t.Run("Disabled for the deployment", func(t *testing.T) {
+		original := cfg
+		t.Cleanup(func() { cfg = original })
+		cfg = &Config{DisableGenderInference: true}
+
+		resp, err := ParseName(context.Background(), &ParseNameRequest{Text: "Mrs Jane Smith"})
+		if err != nil {
+			t.Fatalf("ParseName failed: %v", err)
+		}
+		if resp.Gender != nil {
+			t.Errorf("Expected no gender when disabled for the deployment, got %+v", resp.Gender)
+		}
+
+		_, err = ParseName(context.Background(), &ParseNameRequest{Text: "Mrs Jane Smith", InferGender: &on})
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonGenderInferenceDisabled)
+	})
+}
+
+// TestGenderDistribution tests M/F/X probabilities derived from the single inference
+func TestGenderDistribution(t *testing.T) {
+	ctx := context.Background()
+	parse := func(text, culture string, distribution bool) *GenderInference {
+		t.Helper()
+		resp, err := ParseName(ctx, &ParseNameRequest{Text: text, Culture: culture, GenderDistribution: distribution})
+		if err != nil {
+			t.Fatalf("ParseName failed: %v", err)
+		}
+		return resp.Gender
+	}
+	sum := func(distribution map[string]float64) float64 {
+		var total float64
+		for _, p := range distribution {
+			total += p
+		}
+		return total
+	}
+
+	bint := parse("Aisha bint Ahmed", "arabic", true)
+	if bint.Value != gender.Female || math.Abs(bint.Distribution[gender.Female]-0.9) > 0.01 {
+		t.Errorf("Expected a bint marker to give F near 0.9, got %s %v", bint.Value, bint.Distribution)
+	}
+	if bint.Distribution[gender.Male] <= bint.Distribution[gender.NonBinary] {
+		t.Errorf("Expected M to keep more of the remainder than X, got %v", bint.Distribution)
+	}
+
+	for _, text := range []string{"Aisha bint Ahmed", "Mx Alex Taylor", "Mr John Smith", "Zxq Vrb"} {
+		distribution := parse(text, "", true).Distribution
+		if len(distribution) != 3 {
+			t.Errorf("%s: expected M, F and X probabilities, got %v", text, distribution)
+		}
+		if total := sum(distribution); math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: expected probabilities summing to 1, got %v (%v)", text, total, distribution)
+		}
+	}
+
+	if unknown := parse("Zxq Vrb", "", true); unknown.Value != gender.Unknown || unknown.Distribution[gender.Male] != unknown.Distribution[gender.Female] {
+		t.Errorf("Expected an unknown name to split M and F evenly, got %s %v", unknown.Value, unknown.Distribution)
+	}
+	if plain := parse("Aisha bint Ahmed", "arabic", false); plain.Distribution != nil || plain.Value != gender.Female {
+		t.Errorf("Expected only the single value unless requested, got %+v", plain)
+	}
+
+	resp, err := Transliterate(ctx, &TransliterationRequest{
+		Text:               "Mr John Smith",
+		InputScript:        "latin",
+		OutputScript:       "ascii",
+		GenderDistribution: true,
+		Preview:            true,
+	})
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if resp.Gender.Value != gender.Male || resp.Gender.Distribution[gender.Male] != resp.Gender.Confidence {
+		t.Errorf("Expected the distribution to give M the inference's confidence, got %+v", resp.Gender)
+	}
+}
+
+// TestTurkishCasing tests that Turkish locales case the dotted and dotless i correctly
+func TestTurkishCasing(t *testing.T) {
+	tests := []struct {
+		input     string
+		locale    string
+		output    string
+		fullASCII string
+	}{
+		{"İSTANBUL", "tr-TR", "ascii", "Istanbul"},
+		{"İSTANBUL", "tr-TR", "latin", "İstanbul"},
+		{"Işık", "tr-TR", "ascii", "Isik"},
+		{"IŞIK", "tr-TR", "latin", "Işık"},
+		{"İlker Çelik", "tr-TR", "latin", "İlker ÇELİK"},
+		{"ilker çelik", "tr", "latin", "İlker ÇELİK"},
+		{"IŞIK", "", "latin", "Işik"}, // Without a Turkish locale, I lowers to i
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+"/"+tt.locale+"/"+tt.output, func(t *testing.T) {
+			req := &TransliterationRequest{
+				Text:         tt.input,
+				InputScript:  "latin",
+				OutputScript: tt.output,
+				Preview:      true,
+			}
+			if tt.locale != "" {
+				req.InputLocale = &tt.locale
+			}
+			resp, err := Transliterate(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.Name == nil || resp.Name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected name %q, got %+v", tt.fullASCII, resp.Name)
+			}
+		})
+	}
+
+	resp, err := ParseName(context.Background(), &ParseNameRequest{Text: "AYŞE IŞIK", Language: "tr"})
+	if err != nil {
+		t.Fatalf("ParseName failed: %v", err)
+	}
+	if resp.Name.First != "Ayse" || resp.Name.Family != "ISIK" {
+		t.Errorf("Expected the ASCII name Ayse ISIK, got %q %q", resp.Name.First, resp.Name.Family)
+	}
+}
+
+// TestValidateName tests structure warnings for names that do not fit their declared culture
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		culture  string
+		parts    int
+		warnings []string
+	}{
+		{"Western mononym", "Madonna", "western", 1, []string{nameparser.WarningUnexpectedTokenCount}},
+		{"Western full name", "Dr John Smith Jr", "western", 2, nil},
+		{"Chinese five tokens", "Li Xiao Ming Da Wei", "chinese", 5, []string{nameparser.WarningUnexpectedTokenCount}},
+		{"Chinese in Han", "李小明", "chinese", 3, nil},
+		{"Chinese too long in Han", "李小明大伟华", "chinese", 6, []string{nameparser.WarningUnexpectedTokenCount}},
+		{"Indonesian mononym", "Sukarno", "indonesian", 1, nil},
+		{"Digits", "J0hn Smith", "western", 2, []string{nameparser.WarningUnexpectedCharacters}},
+		{"Honorific set aside", "Tanaka-san Yoko", "japanese", 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ValidateName(context.Background(), &ValidateNameRequest{Text: tt.text, Culture: tt.culture})
+			if err != nil {
+				t.Fatalf("ValidateName failed: %v", err)
+			}
+			if resp.Parts != tt.parts {
+				t.Errorf("Expected %d parts, got %d", tt.parts, resp.Parts)
+			}
+			var codes []string
+			for _, warning := range resp.Warnings {
+				codes = append(codes, warning.Code)
+			}
+			if !slices.Equal(codes, tt.warnings) {
+				t.Errorf("Expected warnings %v, got %+v", tt.warnings, resp.Warnings)
+			}
+			if resp.Valid != (len(tt.warnings) == 0) {
+				t.Errorf("Expected valid to be %v", len(tt.warnings) == 0)
+			}
+		})
+	}
+
+	_, err := ValidateName(context.Background(), &ValidateNameRequest{Text: "John Smith"})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonCultureRequired)
+	_, err = ValidateName(context.Background(), &ValidateNameRequest{Text: "John Smith", Culture: "martian"})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedCulture)
+}
+
+// TestOriginalNameForms tests that name parts keep their original-script spelling
+func TestOriginalNameForms(t *testing.T) {
+	tests := []struct {
+		name           string
+		original       string
+		romanized      string
+		family         string
+		familyOriginal string
+		firstOriginal  string
+		middleOriginal []string
+	}{
+		{"Chinese", "李小明", "Li Xiaoming", "LI", "李", "小明", nil},
+		{"Chinese syllables", "李小明", "Li Xiao Ming", "LI", "李", "明", []string{"小"}},
+		{"Chinese compound surname", "欧阳娜娜", "Ouyang Nana", "OUYANG", "欧阳", "娜娜", nil},
+		{"Arabic", "أحمد بن محمد العلي", "Ahmed bin Mohammed Alali", "ALALI", "العلي", "أحمد", []string{"بن", "محمد"}},
+		{"Cyrillic", "Иван Петров", "Ivan Petrov", "PETROV", "Петров", "Иван", nil},
+		{"Latin input", "John Smith", "John Smith", "SMITH", "", "", nil},
+		{"Kanji read as Chinese", "田中太郎", "Tanaka Taro", "TANAKA", "", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, _ := analyzeName(tt.original, tt.romanized, "", "", true)
+			if name.Family != tt.family {
+				t.Errorf("Expected family %q, got %q", tt.family, name.Family)
+			}
+			if name.FamilyOriginal != tt.familyOriginal || name.FirstOriginal != tt.firstOriginal {
+				t.Errorf("Expected original %q/%q, got %q/%q", tt.familyOriginal, tt.firstOriginal, name.FamilyOriginal, name.FirstOriginal)
+			}
+			if !slices.Equal(name.MiddleOriginal, tt.middleOriginal) {
+				t.Errorf("Expected original middle names %q, got %q", tt.middleOriginal, name.MiddleOriginal)
+			}
+		})
+	}
+}
+
+// TestTransliterateStream tests chunked conversion of documents larger than a chunk
+func TestTransliterateStream(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		// About 3 MB of prose, split after whitespace
+		{"Multi-megabyte prose", strings.Repeat("Съешь же ещё этих мягких французских булок, да выпей же ЧАЮ.\n", 30000)},
+		// No whitespace, so chunks end on character boundaries inside the run
+		{"Unbroken Cyrillic", strings.Repeat("щука", 60000)},
+		// A letter and its combining breve are never separated
+		{"Combining marks", strings.Repeat("и\u0306", 70000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whole, err := engine.Transliterate(ctx, tt.input, "cyrillic", "latin", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+
+			var streamed strings.Builder
+			result, err := engine.TransliterateStream(ctx, &streamed, strings.NewReader(tt.input), "cyrillic", "latin", "")
+			if err != nil {
+				t.Fatalf("TransliterateStream failed: %v", err)
+			}
+			if streamed.String() != whole.Output {
+				t.Errorf("Streamed output differs from whole-text output (%d and %d bytes)", streamed.Len(), len(whole.Output))
+			}
+			if math.Abs(result.Confidence-whole.Confidence) > 0.01 {
+				t.Errorf("Streamed confidence %f, whole-text confidence %f", result.Confidence, whole.Confidence)
+			}
+		})
+	}
+
+	var out strings.Builder
+	_, err := engine.TransliterateStream(ctx, &out, strings.NewReader("Привет\xff"), "cyrillic", "latin", "")
+	if !errors.Is(err, transliteration.ErrInvalidUTF8) {
+		t.Errorf("Expected ErrInvalidUTF8, got %v", err)
+	}
+}
+
+// TestTransliterateStreamEndpoint tests the raw streaming endpoint and its parameters
+func TestTransliterateStreamEndpoint(t *testing.T) {
+	body := strings.Repeat("Москва ", 20000)
+	req := httptest.NewRequest(http.MethodPost, "/api/transliterate/stream?input_script=cyrillic&output_script=latin", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	TransliterateStream(recorder, req)
+
+	if got := recorder.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if want := strings.Repeat("Moskva ", 20000); recorder.Body.String() != want {
+		t.Errorf("Streamed body starts %q, want %q", recorder.Body.String()[:min(recorder.Body.Len(), 20)], want[:20])
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedReason string
+	}{
+		{"Missing input script", "output_script=latin", ReasonInputScriptRequired},
+		{"Missing output script", "input_script=cyrillic", ReasonOutputScriptRequired},
+		{"Unsupported input script", "input_script=klingon&output_script=latin", ReasonUnsupportedInputScript},
+		{"Invalid locale", "input_script=cyrillic&output_script=latin&locale=not+a+locale", ReasonInvalidLocale},
+		{"Unsupported pair", "input_script=latin&output_script=chinese", ReasonUnsupportedScriptPair},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			_, err := parseStreamParams(query)
+			assertErrorReason(t, err, errs.InvalidArgument, tt.expectedReason)
+		})
+	}
+}
+
+// TestVietnameseDiacriticRestoration tests restoring diacritics to romanized Vietnamese names
+func TestVietnameseDiacriticRestoration(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name                 string
+		input                string
+		expected             string
+		expectedAlternatives []string
+	}{
+		{"Family name", "Nguyen", "Nguyễn", nil},
+		{"Male name", "Nguyen Van Minh", "Nguyễn Văn Minh", []string{"Nguyễn Vân Minh"}},
+		{"Female name", "Tran Thi Lan", "Trần Thị Lan", []string{"Trần Thi Lan"}},
+		{"Upper-case family name", "PHAM Duc Hung", "PHẠM Đức Hùng", []string{"PHẠM Đức Hưng"}},
+		{"Ambiguous given name", "Le Thi Hoa", "Lê Thị Hoa", []string{"Lê Thị Hòa", "Lê Thi Hoa", "Lệ Thị Hoa"}},
+		{"Unknown words kept", "Nguyen Smith", "Nguyễn Smith", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "ascii", "vietnamese", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+			if !slices.Equal(result.Alternatives, tt.expectedAlternatives) {
+				t.Errorf("Alternatives = %q, want %q", result.Alternatives, tt.expectedAlternatives)
+			}
+			if !slices.Equal(engine.GuessedAlternatives(tt.input, "ascii", "vietnamese"), tt.expectedAlternatives) {
+				t.Errorf("Engine.GuessedAlternatives disagrees with the conversion's alternatives")
+			}
+			if result.Confidence > 0.5 {
+				t.Errorf("Confidence = %f, restored diacritics should be marked low confidence", result.Confidence)
+			}
+			if len(result.Notes) == 0 {
+				t.Error("Expected a note that diacritics are a best guess")
+			}
+		})
+	}
+
+	unknown, err := engine.Transliterate(context.Background(), "Nguyen Smith", "latin", "vietnamese", "")
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	known, err := engine.Transliterate(context.Background(), "Nguyen Van", "latin", "vietnamese", "")
+	if err != nil {
+		t.Fatalf("Transliterate failed: %v", err)
+	}
+	if unknown.Confidence >= known.Confidence {
+		t.Errorf("Unknown words should lower confidence: %f vs %f", unknown.Confidence, known.Confidence)
+	}
+
+	if !isSupportedScriptPair("ascii", "vietnamese") || !isSupportedScriptPair("latin", "vietnamese") {
+		t.Error("Expected ascii and latin to vietnamese to be supported")
+	}
+}
+
+// TestRankAlternatives tests ordering alternatives by frequency weight plus closeness to the
+// primary output, without repeating it in another case
+func TestRankAlternatives(t *testing.T) {
+	candidates := []transliteration.Alternative{
+		{Text: "Mihail", Weight: 0.60},
+		{Text: "Mixail", Weight: 0.30},
+		{Text: "mikhail", Weight: 0.90}, // The primary output in another case
+		{Text: "Mykhailo", Weight: 0.50},
+		{Text: "MIHAIL", Weight: 0.60}, // An earlier candidate in another case
+		{Text: "Mikhayl", Weight: 0.50},
+	}
+
+	// Closer spellings outrank a slightly more frequent one that differs throughout
+	want := []string{"Mikhayl", "Mykhailo", "Mihail", "Mixail"}
+	if got := transliteration.RankAlternatives("Mikhail", candidates); !slices.Equal(got, want) {
+		t.Errorf("RankAlternatives = %q, want %q", got, want)
+	}
+}
+
+// TestMaxAlternatives tests that alternative_forms come from competing mappings, and that they
+// and restored Vietnamese alternatives are ranked and capped by max_alternatives
+func TestMaxAlternatives(t *testing.T) {
+	ranked := []string{"Mihail", "Mixail", "Mikhajl", "Mykhail"}
+
+	t.Run("Cap", func(t *testing.T) {
+		tests := []struct {
+			maxAlternatives int
+			want            []string
+		}{
+			{0, ranked[:defaultMaxAlternatives]},
+			{1, ranked[:1]},
+			{maxAlternativesCap, ranked},
+		}
+		for _, tt := range tests {
+			if got := limitAlternatives(ranked, tt.maxAlternatives); !slices.Equal(got, tt.want) {
+				t.Errorf("limitAlternatives(%d) = %q, want %q", tt.maxAlternatives, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("Out of range", func(t *testing.T) {
+		for _, maxAlternatives := range []int{-1, maxAlternativesCap + 1} {
+			_, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Михаил", OutputScript: "latin", Preview: true, MaxAlternatives: maxAlternatives})
+			assertErrorReason(t, err, errs.InvalidArgument, ReasonInvalidMaxAlternatives)
+		}
+	})
+
+	t.Run("Restored Vietnamese", func(t *testing.T) {
+		request := func(maxAlternatives int) *TransliterationRequest {
+			return &TransliterationRequest{Text: "Dang Van Thanh Hung", InputScript: "ascii", OutputScript: "vietnamese", Preview: true, MaxAlternatives: maxAlternatives}
+		}
+		all := []string{"Đặng Văn Thành Hùng", "Đặng Văn Thanh Hưng", "Đăng Văn Thanh Hùng", "Đặng Vân Thanh Hùng"}
+
+		tests := []struct {
+			maxAlternatives int
+			want            []string
+		}{
+			{maxAlternativesCap, all},
+			{0, all[:defaultMaxAlternatives]},
+			{1, all[:1]},
+		}
+		for _, tt := range tests {
+			resp, err := Transliterate(context.Background(), request(tt.maxAlternatives))
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if resp.OutputText != "Đặng Văn Thanh Hùng" || !slices.Equal(resp.Alternatives, tt.want) {
+				t.Errorf("max_alternatives %d: got %q with alternatives %q, want %q", tt.maxAlternatives, resp.OutputText, resp.Alternatives, tt.want)
+			}
+		}
+	})
+
+	t.Run("Competing mappings", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := db.Exec(ctx, `
+			INSERT INTO character_mappings (source_script, target_script, source_char, target_char, frequency_weight)
+			VALUES ('cyrillic', 'latin', 'х', 'h', 0.60), ('cyrillic', 'latin', 'х', 'x', 0.30), ('cyrillic', 'latin', 'х', 'KH', 0.50)
+		`)
+		if err != nil {
+			t.Fatalf("Failed to insert competing mappings: %v", err)
+		}
+		t.Cleanup(func() {
+			db.Exec(ctx, `DELETE FROM character_mappings WHERE source_char = 'х' AND source_script = 'cyrillic' AND target_script = 'latin' AND target_char IN ('h', 'x', 'KH')`)
+		})
+
+		request := &TransliterationRequest{Text: "Михаил", InputScript: "cyrillic", OutputScript: "latin", Preview: true}
+		resp, err := Transliterate(ctx, request)
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		// MiKHail only differs from the output in case
+		if want := []string{"Mihail", "Mixail"}; resp.OutputText != "Mikhail" || !slices.Equal(resp.AlternativeForms, want) {
+			t.Errorf("Got %q with alternatives %q, want Mikhail with %q", resp.OutputText, resp.AlternativeForms, want)
+		}
+
+		request.MaxAlternatives = 1
+		if resp, err := Transliterate(ctx, request); err != nil || !slices.Equal(resp.AlternativeForms, []string{"Mihail"}) {
+			t.Errorf("Expected one alternative, got %q (%v)", resp.AlternativeForms, err)
+		}
+	})
+}
+
+// TestLatinToGreek tests reverse Greek transliteration and round trips back to Latin
+func TestLatinToGreek(t *testing.T) {
+	config := transliteration.DefaultConfig()
+	config.UseDatabase = false
+	engine := transliteration.NewEngine(config, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Digraphs", "Philosophia", "Φιλοσοφια"},
+		{"Final sigma and ps", "Logos kai Psyche", "Λογος και Ψυχε"},
+		{"All caps", "THEOS", "ΘΕΟΣ"},
+		{"Rough breathing carries the capital", "Homeros", "Ομερος"},
+		{"Macrons select eta and omega", "Ōmēros", "Ωμηρος"},
+		{"Punctuation and digits kept", "Sokrates, 399", "Σοκρατες, 399"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Transliterate(context.Background(), tt.input, "latin", "greek", "")
+			if err != nil {
+				t.Fatalf("Transliterate failed: %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, result.Output, tt.expected)
+			}
+		})
+	}
+
+	for _, word := range []string{"Philosophia", "Logos kai Psyche", "Sokrates", "THEOS"} {
+		greek, err := engine.Transliterate(context.Background(), word, "latin", "greek", "")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		latin, err := engine.Transliterate(context.Background(), greek.Output, "greek", "latin", "")
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if latin.Output != word {
+			t.Errorf("Round trip %q → %q → %q", word, greek.Output, latin.Output)
+		}
+	}
+
+	if !isSupportedScriptPair("latin", "greek") {
+		t.Error("Expected latin to greek to be supported")
+	}
+}
+
+// TestScriptPairSupport tests supported script combinations
+func TestScriptPairSupport(t *testing.T) {
+	tests := []struct {
+		name         string
+		inputScript  string
+		outputScript string
+		expected     bool
+	}{
+		{"Latin to ASCII", "latin", "ascii", true},
+		{"Cyrillic to Latin", "cyrillic", "latin", true},
+		{"Chinese to Latin", "chinese", "latin", true},
+		{"Unsupported - Latin to Chinese", "latin", "chinese", false},
+		{"Unsupported - Unknown script", "klingon", "latin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isSupportedScriptPair(tt.inputScript, tt.outputScript)
+			if result != tt.expected {
+				t.Errorf("isSupportedScriptPair(%q, %q) = %v, want %v",
+					tt.inputScript, tt.outputScript, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestUnsupportedScriptPairTargets tests that an unsupported pair's error lists the output
+// scripts the input script does convert to
+func TestUnsupportedScriptPairTargets(t *testing.T) {
+	_, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Smith", InputScript: "latin", OutputScript: "chinese", Preview: true})
+	assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedScriptPair)
+
+	var apiErr *errs.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *errs.Error, got %T", err)
+	}
+	expected := []string{"ascii", "greek", "latin", "vietnamese"}
+	if details := apiErr.Details.(ErrorDetails); !slices.Equal(details.ValidTargets, expected) {
+		t.Errorf("ValidTargets = %v, want %v", details.ValidTargets, expected)
+	}
+	if !strings.Contains(apiErr.Message, "latin converts to ascii, greek, latin, vietnamese") {
+		t.Errorf("Expected the valid targets in the message, got %q", apiErr.Message)
+	}
+}
+
+// TestCaching tests that identical requests are cached
+func TestCaching(t *testing.T) {
+	req := TransliterationRequest{
+		Text:         "Hello",
+		InputScript:  "latin",
+		OutputScript: "ascii",
+	}
+
+	// First request
+	resp1, err := Transliterate(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Second identical request should return cached result
+	resp2, err := Transliterate(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Should be the same result (cached)
+	if resp1.ID != resp2.ID {
+		t.Error("expected cached result to have same ID")
+	}
+
+	if resp1.OutputText != resp2.OutputText {
+		t.Error("expected cached result to have same output")
+	}
+}
+
+// TestAutoScriptDetection tests transliteration with auto-detection
+func TestAutoScriptDetection(t *testing.T) {
 	req := TransliterationRequest{
 		Text:         "Привет", // Don't specify InputScript - should auto-detect
 		OutputScript: "latin",
@@ -900,4 +6535,345 @@ func TestAutoScriptDetection(t *testing.T) {
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
-}
\ No newline at end of file
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+// TestDegenerateNames tests names reduced to nothing or to particles by the parser's own rules
+func TestDegenerateNames(t *testing.T) {
+	tests := []struct {
+		text      string
+		first     string
+		middle    []string
+		family    string
+		fullASCII string
+	}{
+		{"Dr. Jr.", "", nil, "", "Dr Jr"},
+		{"Mr", "", nil, "", "Mr"},
+		{"de", "De", nil, "", "De"},
+		{"bin", "Bin", nil, "", "Bin"},
+		{"Ahmed bin", "Ahmed", []string{"bin"}, "", "Ahmed bin"},
+		{"de la", "De", []string{"la"}, "", "De la"},
+		{"Ahmed bin Rashid", "Ahmed", nil, "BIN RASHID", "Ahmed BIN RASHID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			resp, err := ParseName(context.Background(), &ParseNameRequest{Text: tt.text, Culture: "western"})
+			if err != nil {
+				t.Fatalf("ParseName failed: %v", err)
+			}
+			name := resp.Name
+			if name.First != tt.first || name.Family != tt.family || !slices.Equal(name.Middle, tt.middle) {
+				t.Errorf("Expected %q/%q/%q, got %q/%q/%q", tt.first, tt.middle, tt.family, name.First, name.Middle, name.Family)
+			}
+			if name.FullASCII != tt.fullASCII {
+				t.Errorf("Expected full_ascii %q, got %q", tt.fullASCII, name.FullASCII)
+			}
+		})
+	}
+}
+
+// FuzzTransliterate checks that previews of arbitrary text between any pair of scripts
+// never panic
+func FuzzTransliterate(f *testing.F) {
+	f.Add("Hello World", "latin", "ascii")
+	f.Add("李小明先生", "chinese", "latin")
+	f.Add("Иван Петров", "cyrillic", "latin")
+	f.Add("أحمد بن", "arabic", "latin")
+	f.Add("ジョン・スミス", "japanese", "latin")
+	f.Add("Nguyen", "ascii", "vietnamese")
+	f.Add("Dr.", "latin", "ascii")
+	f.Add("de", "latin", "ascii")
+	f.Add("bin", "latin", "ascii")
+	f.Add("-san", "latin", "ascii")
+	f.Add("e\u0301", "latin", "ascii")
+
+	f.Fuzz(func(t *testing.T, text, inputScript, outputScript string) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{
+			Text:         text,
+			InputScript:  inputScript,
+			OutputScript: outputScript,
+			Preview:      true,
+		})
+		if err != nil {
+			return
+		}
+		if score := *resp.ConfidenceScore; score < 0 || score > 1 {
+			t.Errorf("Transliterate(%q, %s, %s) confidence %f out of range", text, inputScript, outputScript, score)
+		}
+	})
+}
+
+// FuzzParseName checks that names parse without panicking, whatever titles, suffixes,
+// particles and honorifics they are reduced to
+func FuzzParseName(f *testing.F) {
+	for _, seed := range []string{"John Smith", "de", "bin", "Dr", "Dr. Jr.", "Mr de", "al-", "van der", "-san", "Tanaka-san", "李", "先生", "Иванович", "Nguyen Thi"} {
+		f.Add(seed, "")
+	}
+	f.Add("Ahmed bin", "arabic")
+	f.Add("de la", "western")
+	f.Add("Mr.", "chinese")
+
+	parser := nameparser.NewParser(true, true)
+
+	f.Fuzz(func(t *testing.T, text, culture string) {
+		for _, format := range []string{"", "given-first", "family-first", "sortable"} {
+			resp, err := ParseName(context.Background(), &ParseNameRequest{
+				Text:               text,
+				Culture:            culture,
+				NameFormat:         format,
+				RespectInputCase:   true,
+				GenderDistribution: true,
+			})
+			if err == nil && resp.Name == nil {
+				t.Errorf("ParseName(%q, %q) returned no name", text, culture)
+			}
+		}
+		parser.ValidateStructure(text, culture)
+	})
+}
+
+// TestAPIVersion tests that X-API-Version and api_version select the response shape
+func TestAPIVersion(t *testing.T) {
+	request := func(header, query string) *TransliterationRequest {
+		return &TransliterationRequest{
+			Text: "Иван Иванович Петров", InputScript: "cyrillic", OutputScript: "latin", Preview: true,
+			APIVersion: header, APIVersionQuery: query,
+		}
+	}
+
+	t.Run("Current shape by default", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), request("", ""))
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.APIVersion != "2" {
+			t.Errorf("Expected api_version 2, got %q", resp.APIVersion)
+		}
+		if resp.ConfidenceFactors == nil || resp.Name == nil || resp.Name.Patronymic != "Ivanovich" || resp.Name.FamilyOriginal == "" {
+			t.Errorf("Expected confidence factors, a patronymic and original-script parts, got %+v with name %+v", resp, resp.Name)
+		}
+	})
+
+	t.Run("Version 1 omits newer fields", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), request("1", ""))
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.APIVersion != "1" {
+			t.Errorf("Expected api_version 1, got %q", resp.APIVersion)
+		}
+		if resp.OutputText != "Ivan Ivanovich Petrov" {
+			t.Errorf("Expected the same output text, got %q", resp.OutputText)
+		}
+		if !slices.Equal(resp.Name.Middle, []string{"Ivanovich"}) {
+			t.Errorf("Expected the patronymic as a middle name, got %v", resp.Name.Middle)
+		}
+
+		encoded, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		for _, field := range []string{"language_hint", "confidence_factors", "phonetic_keys", "patronymic", "family_original", "first_original"} {
+			if strings.Contains(string(encoded), `"`+field+`"`) {
+				t.Errorf("Expected version 1 to omit %s, got %s", field, encoded)
+			}
+		}
+	})
+
+	t.Run("Query parameter", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), request("", "1"))
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.APIVersion != "1" || resp.ConfidenceFactors != nil {
+			t.Errorf("Expected the version 1 shape, got api_version %q", resp.APIVersion)
+		}
+	})
+
+	t.Run("Header takes precedence", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), request("2", "1"))
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		if resp.APIVersion != "2" {
+			t.Errorf("Expected api_version 2 from the header, got %q", resp.APIVersion)
+		}
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		_, err := Transliterate(context.Background(), request("3", ""))
+		assertErrorReason(t, err, errs.InvalidArgument, ReasonUnsupportedAPIVersion)
+	})
+
+	t.Run("Not part of the request schema", func(t *testing.T) {
+		doc := buildSchemaDocument()
+		for name := range doc.Definitions["TransliterationRequest"].Properties {
+			if strings.Contains(strings.ToLower(name), "version") {
+				t.Errorf("Expected the version to be sent outside the body, schema has %q", name)
+			}
+		}
+	})
+}
+
+// TestFamilyCommaNames tests that names written "Family, Given" keep the words before the
+// comma as the family name, whichever culture parses them
+func TestFamilyCommaNames(t *testing.T) {
+	tests := []struct {
+		text     string
+		family   string
+		first    string
+		middle   []string
+		suffixes []string
+	}{
+		{"Smith, John Michael", "SMITH", "John", []string{"Michael"}, nil},
+		{"Nguyễn, Văn Minh", "NGUYEN", "Minh", []string{"Van"}, nil},
+		{"Garcia Lopez, Maria", "GARCIA LOPEZ", "Maria", nil, nil},
+		{"Smith, John, Jr.", "SMITH", "John", nil, []string{"Jr"}},
+		{"John Smith", "SMITH", "John", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			resp, err := ParseName(context.Background(), &ParseNameRequest{Text: tt.text})
+			if err != nil {
+				t.Fatalf("ParseName failed: %v", err)
+			}
+			name := resp.Name
+			if name.Family != tt.family || name.First != tt.first || !slices.Equal(name.Middle, tt.middle) || !slices.Equal(name.Suffixes, tt.suffixes) {
+				t.Errorf("Got family %q, first %q, middle %v, suffixes %v; want %q, %q, %v, %v",
+					name.Family, name.First, name.Middle, name.Suffixes, tt.family, tt.first, tt.middle, tt.suffixes)
+			}
+		})
+	}
+
+	t.Run("Original script", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "李, 明", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		name := resp.Name
+		if name.Family != "LI" || name.First != "Ming" || name.FamilyOriginal != "李" || name.FirstOriginal != "明" {
+			t.Errorf("Expected LI (李) Ming (明), got %+v", name)
+		}
+		if name.FullASCII != "LI Ming" {
+			t.Errorf("Expected full_ascii in the culture's order, got %q", name.FullASCII)
+		}
+	})
+
+	t.Run("Patronymic", func(t *testing.T) {
+		resp, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Иванов, Иван Иванович", OutputScript: "latin", Preview: true})
+		if err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+		name := resp.Name
+		if name.Family != "IVANOV" || name.First != "Ivan" || name.Patronymic != "Ivanovich" {
+			t.Errorf("Expected IVANOV Ivan Ivanovich, got %+v", name)
+		}
+	})
+}
+
+// recordingLogger keeps the entries logged through it
+type recordingLogger struct {
+	entries []logEntry
+}
+
+// logEntry is one recorded log call
+type logEntry struct {
+	level         string
+	msg           string
+	keysAndValues []any
+}
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...any) {
+	l.entries = append(l.entries, logEntry{"info", msg, keysAndValues})
+}
+
+func (l *recordingLogger) Warn(msg string, keysAndValues ...any) {
+	l.entries = append(l.entries, logEntry{"warn", msg, keysAndValues})
+}
+
+func (l *recordingLogger) Error(msg string, keysAndValues ...any) {
+	l.entries = append(l.entries, logEntry{"error", msg, keysAndValues})
+}
+
+// value returns the value logged for key, or nil
+func (e logEntry) value(key string) any {
+	for i := 0; i+1 < len(e.keysAndValues); i += 2 {
+		if e.keysAndValues[i] == key {
+			return e.keysAndValues[i+1]
+		}
+	}
+	return nil
+}
+
+// failingExecer fails every statement with err
+type failingExecer struct {
+	err error
+}
+
+func (f failingExecer) Exec(ctx context.Context, query string, args ...any) (sqldb.ExecResult, error) {
+	return nil, f.err
+}
+
+// submitAs submits feedback as client for the rest of the test
+func submitAs(t *testing.T, client string) {
+	previous := feedbackClient
+	feedbackClient = func() string { return client }
+	t.Cleanup(func() { feedbackClient = previous })
+}
+
+// useLogger replaces the service logger for the rest of the test
+func useLogger(t *testing.T) *recordingLogger {
+	recorder := &recordingLogger{}
+	previous := logger
+	logger = recorder
+	t.Cleanup(func() { logger = previous })
+	return recorder
+}
+
+// TestLogging tests that recovered errors and transliteration decisions are logged with the request ID
+func TestLogging(t *testing.T) {
+	t.Run("Usage count update failure", func(t *testing.T) {
+		recorder := useLogger(t)
+		updateErr := errors.New("connection reset")
+		countCacheHit(context.Background(), failingExecer{err: updateErr}, "123e4567-e89b-12d3-a456-426614174000")
+
+		if len(recorder.entries) != 1 {
+			t.Fatalf("Expected one log entry, got %+v", recorder.entries)
+		}
+		entry := recorder.entries[0]
+		if entry.level != "error" || entry.msg != "failed to update usage count" {
+			t.Errorf("Expected an error about the usage count, got %s %q", entry.level, entry.msg)
+		}
+		if entry.value("err") != updateErr || entry.value("transliteration_id") != "123e4567-e89b-12d3-a456-426614174000" {
+			t.Errorf("Expected the error and transliteration ID, got %v", entry.keysAndValues)
+		}
+		if entry.keysAndValues[0] != "request_id" {
+			t.Errorf("Expected the request ID first, got %v", entry.keysAndValues)
+		}
+	})
+
+	t.Run("Detection and fallbacks", func(t *testing.T) {
+		recorder := useLogger(t)
+		if _, err := Transliterate(context.Background(), &TransliterationRequest{Text: "Привет ☃", OutputScript: "ascii", Preview: true}); err != nil {
+			t.Fatalf("Transliterate failed: %v", err)
+		}
+
+		var detected, fallback bool
+		for _, entry := range recorder.entries {
+			switch entry.msg {
+			case "script detected":
+				detected = entry.value("script") == "cyrillic" && entry.value("input_script") == "cyrillic"
+			case "characters converted by fallback":
+				fallback = entry.value("count") == 1
+			}
+		}
+		if !detected || !fallback {
+			t.Errorf("Expected the detected script and one fallback logged, got %+v", recorder.entries)
+		}
+	})
+}
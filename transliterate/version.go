@@ -0,0 +1,59 @@
+package transliterate
+
+import "strings"
+
+// API versions, which select the shape of transliteration responses. Version 1 is the shape
+// before responses gained language_hint, confidence_factors, phonetic_keys and the name's
+// patronymic and original-script parts, for clients that decode strictly; version 2 has them.
+// Fields a request opts into, such as segments or pronunciation, are returned in either.
+const (
+	apiVersion1       = "1"
+	apiVersion2       = "2"
+	currentAPIVersion = apiVersion2
+)
+
+// validAPIVersions lists the versions clients can select
+var validAPIVersions = map[string]bool{apiVersion1: true, apiVersion2: true}
+
+// requestedAPIVersion returns the version selected by the X-API-Version header, or else the
+// api_version query parameter, defaulting to the current version
+func requestedAPIVersion(header, query string) string {
+	switch {
+	case header != "":
+		return header
+	case query != "":
+		return query
+	}
+	return currentAPIVersion
+}
+
+// validateAPIVersion reports a requested version the service doesn't serve
+func validateAPIVersion(problems *validationErrors, header, query string) {
+	if version := requestedAPIVersion(header, query); !validAPIVersions[version] {
+		problems.add("api_version", ReasonUnsupportedAPIVersion, "unsupported API version: %s (supported: %s)", version, strings.Join(sortedKeys(validAPIVersions), ", "))
+	}
+}
+
+// applyAPIVersion records the version a response is shaped for and removes the fields that
+// version lacks. Version 1 reads a patronymic as one of the middle names, as it did before
+// names had a patronymic of their own.
+func applyAPIVersion(resp *TransliterationResponse, version string) {
+	resp.APIVersion = version
+	if version != apiVersion1 {
+		return
+	}
+
+	resp.LanguageHint = nil
+	resp.ConfidenceFactors = nil
+	resp.PhoneticKeys = nil
+	if resp.Name != nil {
+		// Copy, since the name may be shared with a cached response
+		name := *resp.Name
+		if name.Patronymic != "" {
+			name.Middle = append(append([]string(nil), name.Middle...), name.Patronymic)
+			name.Patronymic = ""
+		}
+		name.FamilyOriginal, name.FirstOriginal, name.MiddleOriginal = "", "", nil
+		resp.Name = &name
+	}
+}